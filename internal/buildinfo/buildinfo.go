@@ -0,0 +1,43 @@
+// Package buildinfo holds the metadata stamped into the binary at build time:
+//
+//	go build -ldflags "-X focusflow-be/internal/buildinfo.Version=v1.4.0 \
+//	  -X focusflow-be/internal/buildinfo.Commit=$(git rev-parse --short HEAD) \
+//	  -X focusflow-be/internal/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Values not set this way read "dev".
+package buildinfo
+
+import "runtime"
+
+var (
+	Version   = "dev"
+	Commit    = "dev"
+	BuildTime = "dev"
+)
+
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Get returns the build metadata along with the Go version the binary was
+// built with
+func Get() Info {
+	return Info{
+		Version:   orDev(Version),
+		Commit:    orDev(Commit),
+		BuildTime: orDev(BuildTime),
+		GoVersion: runtime.Version(),
+	}
+}
+
+// orDev guards against -X flags given an empty value, e.g. from an unset
+// build argument
+func orDev(value string) string {
+	if value == "" {
+		return "dev"
+	}
+	return value
+}
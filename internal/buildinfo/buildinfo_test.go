@@ -0,0 +1,45 @@
+package buildinfo
+
+import (
+	"encoding/json"
+	"runtime"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	defer func(version, commit, buildTime string) {
+		Version, Commit, BuildTime = version, commit, buildTime
+	}(Version, Commit, BuildTime)
+
+	tests := []struct {
+		name                    string
+		version, commit, built  string
+		wantVersion, wantCommit string
+		wantBuildTime           string
+	}{
+		{"stamped by ldflags", "v1.4.0", "0a45abb", "2026-10-16T09:00:00Z", "v1.4.0", "0a45abb", "2026-10-16T09:00:00Z"},
+		{"not stamped", "dev", "dev", "dev", "dev", "dev", "dev"},
+		{"stamped with empty values", "", "", "", "dev", "dev", "dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Version, Commit, BuildTime = tt.version, tt.commit, tt.built
+
+			want := Info{Version: tt.wantVersion, Commit: tt.wantCommit, BuildTime: tt.wantBuildTime, GoVersion: runtime.Version()}
+			if got := Get(); got != want {
+				t.Errorf("Get() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestInfoJSON(t *testing.T) {
+	encoded, err := json.Marshal(Info{Version: "v1.4.0", Commit: "0a45abb", BuildTime: "2026-10-16T09:00:00Z", GoVersion: "go1.24.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"version":"v1.4.0","commit":"0a45abb","buildTime":"2026-10-16T09:00:00Z","goVersion":"go1.24.0"}`
+	if string(encoded) != want {
+		t.Errorf("JSON = %s, want %s", encoded, want)
+	}
+}
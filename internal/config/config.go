@@ -1,22 +1,148 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"focusflow-be/internal/clock"
 )
 
 type Config struct {
-	Port                string
-	FirebaseAPIKey      string
-	FirebaseAuthDomain  string
-	FirebaseProjectID   string
-	GoogleClientID      string
-	GoogleClientSecret  string
-	GoogleRedirectURI   string
-	JWTSecret          string
+	Port                 string
+	FirebaseAPIKey       string
+	FirebaseAuthDomain   string
+	FirebaseProjectID    string
+	GoogleClientID       string
+	GoogleClientSecret   string
+	GoogleRedirectURI    string
+	GoogleRedirectURIs   []string // allowlist; GoogleRedirectURI is always included
+	GoogleOAuthPKCE      bool     // send a PKCE challenge with the login flow
+	FrontendCallbackURL  string   // when set, the OAuth callback redirects here with the token
+	FrontendCallbackURLs []string // allowlist; FrontendCallbackURL is always included
+	JWTSecret            string
+	JWTAlgorithm         string // HS256 (default) or RS256
+	JWTPrivateKeyPath    string // PEM private key used to sign RS256 tokens
+	JWTPublicKeyPath     string // PEM public key used to verify RS256 tokens
+	AdminAPIKey          string
+
+	// Clock skew allowed when checking a token's expiry and not-before times
+	JWTLeeway time.Duration
+
+	// Shortest JWT_SECRET, in bytes, accepted for HS256 signing
+	JWTSecretMinLength int
+
+	// Meeting scheduling limits
+	MeetingMinDuration     time.Duration
+	MeetingMaxDuration     time.Duration
+	MeetingPastTolerance   time.Duration // how far in the past a new meeting may start
+	MeetingDefaultDuration time.Duration // used when a new meeting omits its end time
+
+	// Attendees included per meeting in list responses; 0 returns them all
+	MeetingListMaxAttendees int
+
+	// Whether tentative meetings count in conflict checks and availability;
+	// the dashboard endpoints can override it with ?includeTentative=
+	ConflictsIncludeTentative bool
+
+	// Reject a new task whose title matches an open one (?checkDuplicate=true
+	// turns the check on per request when this is off)
+	TaskDuplicateCheck bool
+
+	// Scheduled work per day above which the workload view flags a day
+	WorkloadDailyCapacity time.Duration
+
+	// How long after its due time an item starts counting as overdue
+	OverdueGrace time.Duration
+
+	// How long a computed dashboard overview is served before it is rebuilt;
+	// writes drop it sooner. Zero disables the cache.
+	OverviewCacheTTL time.Duration
+
+	// How long a shared overview link stays readable, and the most a user
+	// may ask for
+	OverviewShareTTL time.Duration
+
+	// How many times an unfinished reminder may be carried to the next day
+	ReminderMaxRollovers int
+
+	// How far in the past a new reminder time may be before it's rejected;
+	// absorbs clock skew between client and server
+	ReminderPastTolerance time.Duration
+
+	// Caps on how far a recurring series is expanded
+	RecurrenceMaxOccurrences int
+	RecurrenceHorizon        time.Duration // measured from the series start
+
+	// Source of the current time; swapped for a fake clock in tests
+	Clock clock.Clock
+
+	Features Features
+
+	// Base colors of calendar events, as #rgb or #rrggbb hex
+	MeetingColor  string
+	ReminderColor string
+
+	// Parallel Google Calendar inserts during a bulk sync
+	CalendarSyncConcurrency int
+
+	// Firestore retries for transient errors
+	FirestoreRetryAttempts int
+	FirestoreRetryDeadline time.Duration
+
+	// Response compression
+	GzipEnabled bool
+	GzipMinSize int // bytes
+
+	// Per-request phase timings: sent as Server-Timing when enabled, and
+	// logged for requests slower than the budget (0 disables the log)
+	ServerTiming       bool
+	ResponseTimeBudget time.Duration
+
+	// Reject writes with 503 (toggled at runtime via /admin/read-only)
+	ReadOnlyMode       bool
+	ReadOnlyRetryAfter time.Duration
+
+	// Reject tokens whose user no longer exists (costs a lookup per cache miss)
+	AuthCheckUserExists bool
+	AuthUserCacheTTL    time.Duration
+	AuthUserCacheSize   int
+
+	// Extra routes, on top of the built-in public ones, reachable without a
+	// token; a trailing * matches by prefix
+	AuthPublicRoutes []string
+
+	// Proxies (IPs or CIDRs) whose X-Forwarded-For is believed when resolving
+	// the client IP. Empty trusts none, so the connection's address is used.
+	TrustedProxies []string
+
+	// HTTP server limits
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	ShutdownTimeout time.Duration
+	MaxHeaderBytes  int
+
+	// Variables that were set but couldn't be read; reported by Validate
+	envProblems []string
+}
+
+// Features are optional capabilities that can be switched off independently
+type Features struct {
+	CalendarSync         bool `json:"calendarSync"`         // push items to Google Calendar
+	Webhooks             bool `json:"webhooks"`             // outgoing webhook deliveries
+	ReminderCarryForward bool `json:"reminderCarryForward"` // daily rollover of unfinished reminders
+	DataTransfer         bool `json:"dataTransfer"`         // account export and import
 }
 
 func New() *Config {
-	return &Config{
+	env := &envLoader{}
+	cfg := &Config{
 		Port:                getEnv("PORT", "8080"),
 		FirebaseAPIKey:      getEnv("FIREBASE_API_KEY", ""),
 		FirebaseAuthDomain:  getEnv("FIREBASE_AUTH_DOMAIN", ""),
@@ -24,8 +150,212 @@ func New() *Config {
 		GoogleClientID:      getEnv("GOOGLE_CLIENT_ID", ""),
 		GoogleClientSecret:  getEnv("GOOGLE_CLIENT_SECRET", ""),
 		GoogleRedirectURI:   getEnv("GOOGLE_REDIRECT_URI", ""),
-		JWTSecret:          getEnv("JWT_SECRET", ""),
+		GoogleOAuthPKCE:     env.bool("GOOGLE_OAUTH_PKCE", true),
+		FrontendCallbackURL: getEnv("FRONTEND_CALLBACK_URL", ""),
+		JWTSecret:           getEnv("JWT_SECRET", ""),
+		JWTAlgorithm:        getEnv("JWT_ALGORITHM", "HS256"),
+		JWTPrivateKeyPath:   getEnv("JWT_PRIVATE_KEY_PATH", ""),
+		JWTPublicKeyPath:    getEnv("JWT_PUBLIC_KEY_PATH", ""),
+		AdminAPIKey:         getEnv("ADMIN_API_KEY", ""),
+
+		JWTLeeway: env.durationOrZero("JWT_LEEWAY", 30*time.Second),
+
+		JWTSecretMinLength: env.int("JWT_SECRET_MIN_LENGTH", DefaultJWTSecretMinLength),
+
+		MeetingMinDuration:     env.duration("MEETING_MIN_DURATION", time.Minute),
+		MeetingMaxDuration:     env.duration("MEETING_MAX_DURATION", 24*time.Hour),
+		MeetingPastTolerance:   env.durationOrZero("MEETING_PAST_TOLERANCE", 24*time.Hour),
+		MeetingDefaultDuration: env.duration("MEETING_DEFAULT_DURATION", 30*time.Minute),
+
+		MeetingListMaxAttendees: env.intOrZero("MEETING_LIST_MAX_ATTENDEES", 0),
+
+		ConflictsIncludeTentative: env.bool("CONFLICTS_INCLUDE_TENTATIVE", true),
+
+		TaskDuplicateCheck: env.bool("TASK_DUPLICATE_CHECK", false),
+
+		WorkloadDailyCapacity: env.duration("WORKLOAD_DAILY_CAPACITY", 8*time.Hour),
+
+		OverdueGrace: env.durationOrZero("OVERDUE_GRACE", 0),
+
+		OverviewCacheTTL: env.durationOrZero("OVERVIEW_CACHE_TTL", 30*time.Second),
+
+		OverviewShareTTL: env.duration("OVERVIEW_SHARE_TTL", 7*24*time.Hour),
+
+		ReminderMaxRollovers: env.int("REMINDER_MAX_ROLLOVERS", 3),
+
+		ReminderPastTolerance: env.durationOrZero("REMINDER_PAST_TOLERANCE", time.Minute),
+
+		RecurrenceMaxOccurrences: env.int("RECURRENCE_MAX_OCCURRENCES", 365),
+		RecurrenceHorizon:        env.duration("RECURRENCE_HORIZON", 2*365*24*time.Hour),
+
+		Clock: clock.Real{},
+
+		Features: Features{
+			CalendarSync:         env.bool("FEATURE_CALENDAR_SYNC", false),
+			Webhooks:             env.bool("FEATURE_WEBHOOKS", false),
+			ReminderCarryForward: env.bool("FEATURE_REMINDER_CARRY_FORWARD", true),
+			DataTransfer:         env.bool("FEATURE_DATA_TRANSFER", true),
+		},
+
+		MeetingColor:  getEnv("MEETING_COLOR", "#3b82f6"),  // blue
+		ReminderColor: getEnv("REMINDER_COLOR", "#8b5cf6"), // purple
+
+		CalendarSyncConcurrency: env.int("CALENDAR_SYNC_CONCURRENCY", 4),
+
+		FirestoreRetryAttempts: env.int("FIRESTORE_RETRY_ATTEMPTS", 3),
+		FirestoreRetryDeadline: env.duration("FIRESTORE_RETRY_DEADLINE", 10*time.Second),
+
+		GzipEnabled: env.bool("GZIP_ENABLED", true),
+		GzipMinSize: env.intOrZero("GZIP_MIN_SIZE", 1024),
+
+		ServerTiming:       env.bool("SERVER_TIMING", false),
+		ResponseTimeBudget: env.durationOrZero("RESPONSE_TIME_BUDGET", 0),
+
+		ReadOnlyMode:       env.bool("READ_ONLY_MODE", false),
+		ReadOnlyRetryAfter: env.duration("READ_ONLY_RETRY_AFTER", 5*time.Minute),
+
+		AuthCheckUserExists: env.bool("AUTH_CHECK_USER_EXISTS", false),
+		AuthUserCacheTTL:    env.duration("AUTH_USER_CACHE_TTL", time.Minute),
+		AuthUserCacheSize:   env.int("AUTH_USER_CACHE_SIZE", 10000),
+
+		ReadTimeout:     env.duration("READ_TIMEOUT", 15*time.Second),
+		WriteTimeout:    env.duration("WRITE_TIMEOUT", 30*time.Second),
+		IdleTimeout:     env.duration("IDLE_TIMEOUT", 60*time.Second),
+		ShutdownTimeout: env.duration("SHUTDOWN_TIMEOUT", 10*time.Second),
+		MaxHeaderBytes:  env.int("MAX_HEADER_BYTES", 1<<20),
+	}
+
+	cfg.GoogleRedirectURIs = getListEnv("GOOGLE_REDIRECT_URIS")
+	if cfg.GoogleRedirectURI != "" && !contains(cfg.GoogleRedirectURIs, cfg.GoogleRedirectURI) {
+		cfg.GoogleRedirectURIs = append([]string{cfg.GoogleRedirectURI}, cfg.GoogleRedirectURIs...)
+	}
+	if cfg.GoogleRedirectURI == "" && len(cfg.GoogleRedirectURIs) > 0 {
+		cfg.GoogleRedirectURI = cfg.GoogleRedirectURIs[0]
+	}
+
+	cfg.TrustedProxies = getListEnv("TRUSTED_PROXIES")
+	cfg.AuthPublicRoutes = getListEnv("AUTH_PUBLIC_ROUTES")
+
+	cfg.FrontendCallbackURLs = getListEnv("FRONTEND_CALLBACK_URLS")
+	if cfg.FrontendCallbackURL != "" && !contains(cfg.FrontendCallbackURLs, cfg.FrontendCallbackURL) {
+		cfg.FrontendCallbackURLs = append([]string{cfg.FrontendCallbackURL}, cfg.FrontendCallbackURLs...)
+	}
+
+	cfg.envProblems = env.problems
+	return cfg
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// DefaultJWTSecretMinLength is the shortest JWT_SECRET accepted for HS256
+// signing unless JWT_SECRET_MIN_LENGTH says otherwise
+const DefaultJWTSecretMinLength = 32
+
+// Secrets copied from the examples in .env.example and the README; anyone
+// who has read them can forge tokens
+var exampleJWTSecrets = []string{
+	"your_super_secure_jwt_secret_key_at_least_32_characters_long",
+	"your-super-secure-jwt-secret-32-chars-min",
+}
+
+// ValidationError lists every problem Validate found, each naming the
+// environment variable to fix
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Problems, "; ")
+}
+
+// Validate checks that the required options are set, that the rest hold
+// values that can be used, and that every variable New read could be parsed.
+// It reports every problem at once rather than stopping at the first, so a
+// deployment can be fixed in one go.
+func (c *Config) Validate() error {
+	problems := append([]string(nil), c.envProblems...)
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if strings.TrimSpace(c.FirebaseProjectID) == "" {
+		add("FIREBASE_PROJECT_ID is required: set it to the project ID shown in the Firebase console under Project settings")
+	}
+	if c.GoogleClientID == "" {
+		add("GOOGLE_CLIENT_ID is required: create an OAuth client in the Google Cloud console under APIs & Services > Credentials")
+	}
+	if c.GoogleClientSecret == "" {
+		add("GOOGLE_CLIENT_SECRET is required: copy it from the same OAuth client as GOOGLE_CLIENT_ID")
+	}
+
+	if c.GoogleRedirectURI == "" {
+		add("GOOGLE_REDIRECT_URI is required: set it to this server's callback, e.g. http://localhost:8080/auth/callback, and list it as an authorized redirect URI on the OAuth client")
+	}
+	for _, uri := range c.GoogleRedirectURIs {
+		if err := checkURL(uri); err != nil {
+			add("GOOGLE_REDIRECT_URI(S) entry %q %v", uri, err)
+		}
+	}
+	for _, uri := range c.FrontendCallbackURLs {
+		if err := checkURL(uri); err != nil {
+			add("FRONTEND_CALLBACK_URL(S) entry %q %v", uri, err)
+		}
+	}
+
+	switch c.JWTAlgorithm {
+	case "", "HS256":
+		minLength := c.JWTSecretMinLength
+		if minLength <= 0 {
+			minLength = DefaultJWTSecretMinLength
+		}
+		switch {
+		case c.JWTSecret == "":
+			add("JWT_SECRET is required for HS256: generate one with `openssl rand -base64 48`")
+		case len(c.JWTSecret) < minLength:
+			add("JWT_SECRET must be at least %d bytes, got %d: generate a strong one with `openssl rand -base64 48`", minLength, len(c.JWTSecret))
+		case contains(exampleJWTSecrets, c.JWTSecret):
+			add("JWT_SECRET is still the example value from the docs: replace it with one from `openssl rand -base64 48`")
+		}
+	case "RS256":
+		if c.JWTPrivateKeyPath == "" {
+			add("JWT_PRIVATE_KEY_PATH is required for RS256: point it at a PEM private key, e.g. from `openssl genrsa -out jwt.pem 2048`")
+		}
+		if c.JWTPublicKeyPath == "" {
+			add("JWT_PUBLIC_KEY_PATH is required for RS256: point it at the matching PEM public key, e.g. from `openssl rsa -in jwt.pem -pubout -out jwt.pub`")
+		}
+	default:
+		add("JWT_ALGORITHM must be HS256 or RS256, got %q", c.JWTAlgorithm)
+	}
+
+	colors := []struct {
+		key   string
+		value string
+	}{
+		{"MEETING_COLOR", c.MeetingColor},
+		{"REMINDER_COLOR", c.ReminderColor},
+	}
+	for _, color := range colors {
+		if !hexColor.MatchString(color.value) {
+			add("%s must be a hex color such as #3b82f6, got %q", color.key, color.value)
+		}
 	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// checkURL accepts absolute http and https URLs
+func checkURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("is not a valid URL: %v", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("must be an absolute http or https URL such as https://app.example.com/auth/callback")
+	}
+	return nil
 }
 
 func getEnv(key, defaultValue string) string {
@@ -33,4 +363,104 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+// getListEnv splits a comma-separated variable, dropping empty entries
+func getListEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func contains(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+// envLoader reads typed environment variables, falling back to the default
+// when one is unset. A value that is set but can't be used also falls back,
+// and is recorded so Validate can report it.
+type envLoader struct {
+	problems []string
+}
+
+func (l *envLoader) invalid(key, value, want string) {
+	l.problems = append(l.problems, fmt.Sprintf("%s must be %s, got %q", key, want, value))
+}
+
+// duration reads a positive Go duration such as 30s or 5m
+func (l *envLoader) duration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		l.invalid(key, value, "a positive duration such as 30s or 5m")
+		return defaultValue
+	}
+	return d
+}
+
+// durationOrZero is duration for settings where 0 turns something off
+func (l *envLoader) durationOrZero(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		l.invalid(key, value, "a duration such as 30s, or 0")
+		return defaultValue
+	}
+	return d
+}
+
+// int reads a positive whole number
+func (l *envLoader) int(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		l.invalid(key, value, "a positive whole number")
+		return defaultValue
+	}
+	return n
+}
+
+// intOrZero is int for settings where 0 means none or no limit
+func (l *envLoader) intOrZero(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		l.invalid(key, value, "a whole number, or 0")
+		return defaultValue
+	}
+	return n
+}
+
+func (l *envLoader) bool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		l.invalid(key, value, "true or false")
+		return defaultValue
+	}
+	return b
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// validEnv is a minimal configuration that passes Validate
+var validEnv = map[string]string{
+	"FIREBASE_PROJECT_ID":  "focusflow-test",
+	"GOOGLE_CLIENT_ID":     "client-id",
+	"GOOGLE_CLIENT_SECRET": "client-secret",
+	"GOOGLE_REDIRECT_URI":  "http://localhost:8080/auth/callback",
+	"JWT_SECRET":           strings.Repeat("s", DefaultJWTSecretMinLength),
+}
+
+// setEnv applies validEnv with overrides on top; an empty value unsets the
+// variable
+func setEnv(t *testing.T, overrides map[string]string) {
+	t.Helper()
+	for key, value := range validEnv {
+		t.Setenv(key, value)
+	}
+	for key, value := range overrides {
+		t.Setenv(key, value)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want []string // each must start one problem, in any order
+	}{
+		{"valid", nil, nil},
+		{
+			name: "missing required values",
+			env: map[string]string{
+				"FIREBASE_PROJECT_ID":  "",
+				"GOOGLE_CLIENT_ID":     "",
+				"GOOGLE_CLIENT_SECRET": "",
+				"GOOGLE_REDIRECT_URI":  "",
+				"JWT_SECRET":           "",
+			},
+			want: []string{"FIREBASE_PROJECT_ID", "GOOGLE_CLIENT_ID", "GOOGLE_CLIENT_SECRET", "GOOGLE_REDIRECT_URI", "JWT_SECRET"},
+		},
+		{
+			name: "malformed numbers and durations",
+			env: map[string]string{
+				"READ_TIMEOUT":     "abc",
+				"WRITE_TIMEOUT":    "-5s",
+				"GZIP_MIN_SIZE":    "x",
+				"MAX_HEADER_BYTES": "0",
+				"GZIP_ENABLED":     "maybe",
+			},
+			want: []string{"READ_TIMEOUT", "WRITE_TIMEOUT", "GZIP_MIN_SIZE", "MAX_HEADER_BYTES", "GZIP_ENABLED"},
+		},
+		{
+			name: "zero where it means off",
+			env: map[string]string{
+				"RESPONSE_TIME_BUDGET":       "0",
+				"OVERDUE_GRACE":              "0s",
+				"MEETING_LIST_MAX_ATTENDEES": "0",
+				"GZIP_MIN_SIZE":              "0",
+			},
+		},
+		{
+			name: "malformed URLs",
+			env: map[string]string{
+				"GOOGLE_REDIRECT_URI":   "localhost:8080/auth/callback",
+				"FRONTEND_CALLBACK_URL": "ftp://app.example.com",
+			},
+			want: []string{"GOOGLE_REDIRECT_URI(S)", "FRONTEND_CALLBACK_URL(S)"},
+		},
+		{"unknown algorithm", map[string]string{"JWT_ALGORITHM": "none"}, []string{"JWT_ALGORITHM"}},
+		{"RS256 without keys", map[string]string{"JWT_ALGORITHM": "RS256", "JWT_SECRET": ""}, []string{"JWT_PRIVATE_KEY_PATH", "JWT_PUBLIC_KEY_PATH"}},
+		{"example secret", map[string]string{"JWT_SECRET": exampleJWTSecrets[0]}, []string{"JWT_SECRET"}},
+		{"malformed color", map[string]string{"MEETING_COLOR": "blue"}, []string{"MEETING_COLOR"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, tt.env)
+
+			err := New().Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+
+			var invalid *ValidationError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("err = %v, want a ValidationError", err)
+			}
+			if len(invalid.Problems) != len(tt.want) {
+				t.Errorf("got %d problems, want %d: %q", len(invalid.Problems), len(tt.want), invalid.Problems)
+			}
+			for _, key := range tt.want {
+				found := false
+				for _, problem := range invalid.Problems {
+					if strings.HasPrefix(problem, key+" ") {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("no problem reported for %s in %q", key, invalid.Problems)
+				}
+			}
+		})
+	}
+}
+
+func TestNewFallsBackOnMalformedValues(t *testing.T) {
+	setEnv(t, map[string]string{"READ_TIMEOUT": "abc", "GZIP_MIN_SIZE": "x"})
+
+	cfg := New()
+	if cfg.ReadTimeout != 15*time.Second {
+		t.Errorf("ReadTimeout = %v, want the 15s default", cfg.ReadTimeout)
+	}
+	if cfg.GzipMinSize != 1024 {
+		t.Errorf("GzipMinSize = %d, want the 1024 default", cfg.GzipMinSize)
+	}
+}
+
+func TestValidateJWTSecretLength(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		minLength string
+		wantErr   bool
+	}{
+		{"too short", strings.Repeat("s", DefaultJWTSecretMinLength-1), "", true},
+		{"exactly the minimum", strings.Repeat("s", DefaultJWTSecretMinLength), "", false},
+		{"long", strings.Repeat("s", 64), "", false},
+		{"short for a raised minimum", strings.Repeat("s", 40), "48", true},
+		{"long enough for a raised minimum", strings.Repeat("s", 48), "48", false},
+		{"short but allowed by a lowered minimum", strings.Repeat("s", 16), "16", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, map[string]string{"JWT_SECRET": tt.secret, "JWT_SECRET_MIN_LENGTH": tt.minLength})
+
+			err := New().Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "JWT_SECRET must be at least") {
+				t.Errorf("err = %v, want it to name the minimum length", err)
+			}
+		})
+	}
+}
+
+func TestJWTLeewayZero(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 30 * time.Second},
+		{"0", 0},
+		{"0s", 0},
+		{"2m", 2 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			setEnv(t, map[string]string{"JWT_LEEWAY": tt.value})
+			if got := New().JWTLeeway; got != tt.want {
+				t.Errorf("JWTLeeway = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverviewCacheTTLZero(t *testing.T) {
+	setEnv(t, map[string]string{"OVERVIEW_CACHE_TTL": "0"})
+	cfg := New()
+	if cfg.OverviewCacheTTL != 0 {
+		t.Errorf("OverviewCacheTTL = %v, want 0 to disable the cache", cfg.OverviewCacheTTL)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
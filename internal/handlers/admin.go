@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"focusflow-be/internal/config"
+	"focusflow-be/internal/middleware"
+	"focusflow-be/internal/models"
+	"focusflow-be/internal/services"
+)
+
+type AdminHandler struct {
+	requestStore
+	readOnly *middleware.ReadOnlyMode
+	config   *config.Config
+}
+
+func NewAdminHandler(firebaseService services.Store, readOnly *middleware.ReadOnlyMode, cfg *config.Config) *AdminHandler {
+	return &AdminHandler{
+		requestStore: requestStore{firebaseService},
+		readOnly:     readOnly,
+		config:       cfg,
+	}
+}
+
+func (h *AdminHandler) GetStats(c *gin.Context) {
+	stats, err := h.store(c).GetUsageStats()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch usage stats", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// SetReadOnly switches read-only mode on this instance
+func (h *AdminHandler) SetReadOnly(c *gin.Context) {
+	var req models.ReadOnlyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	h.readOnly.Set(*req.Enabled)
+	log.Printf("🔒 Read-only mode set to %t", *req.Enabled)
+
+	c.JSON(http.StatusOK, gin.H{"readOnly": h.readOnly.Enabled()})
+}
+
+const (
+	carryForwardLock    = "reminder-carry-forward"
+	carryForwardLockTTL = 5 * time.Minute
+)
+
+// CarryForwardReminders moves yesterday's unfinished reminders to the same time
+// today for users who opted in. It is meant to be called once a day by a
+// scheduler, shortly after midnight UTC.
+func (h *AdminHandler) CarryForwardReminders(c *gin.Context) {
+	// Only one instance may run the job; a concurrent call gets a 409
+	acquired, err := h.store(c).AcquireLock(carryForwardLock, carryForwardLockTTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to acquire job lock", "details": err.Error()})
+		return
+	}
+	if !acquired {
+		c.JSON(http.StatusConflict, gin.H{"error": "Reminder carry-forward is already running"})
+		return
+	}
+	defer func() {
+		if err := h.store(c).ReleaseLock(carryForwardLock); err != nil {
+			log.Printf("⚠️ Failed to release %s lock: %v", carryForwardLock, err)
+		}
+	}()
+
+	userIDs, err := h.store(c).GetCarryForwardUserIDs()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users", "details": err.Error()})
+		return
+	}
+
+	startOfDay := h.config.Clock.Now().UTC().Truncate(24 * time.Hour)
+	renewAt := h.config.Clock.Now().Add(carryForwardLockTTL / 2)
+	rolled := 0
+	for _, userID := range userIDs {
+		if h.config.Clock.Now().After(renewAt) {
+			if err := h.store(c).RenewLock(carryForwardLock, carryForwardLockTTL); err != nil {
+				log.Printf("⚠️ Lost %s lock, stopping early: %v", carryForwardLock, err)
+				break
+			}
+			renewAt = h.config.Clock.Now().Add(carryForwardLockTTL / 2)
+		}
+
+		reminders, err := h.store(c).GetReminders(userID)
+		if err != nil {
+			log.Printf("⚠️ Skipping reminder carry-forward for %s: %v", userID, err)
+			continue
+		}
+
+		updates := selectRollovers(reminders, startOfDay, h.config.ReminderMaxRollovers)
+		if err := h.store(c).BatchUpdateReminders(updates); err != nil {
+			log.Printf("⚠️ Failed to carry forward reminders for %s: %v", userID, err)
+			continue
+		}
+		rolled += len(updates)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Reminders carried forward",
+		"users":     len(userIDs),
+		"reminders": rolled,
+	})
+}
+
+// selectRollovers picks unfinished task and personal reminders that fell before
+// startOfDay and haven't hit the rollover cap, moving each to the same time of
+// day on or after startOfDay
+func selectRollovers(reminders []*models.Reminder, startOfDay time.Time, maxRollovers int) map[string]map[string]interface{} {
+	updates := make(map[string]map[string]interface{})
+	for _, reminder := range reminders {
+		if reminder.IsCompleted || !reminder.ReminderTime.Before(startOfDay) || reminder.RolledOverCount >= maxRollovers {
+			continue
+		}
+		if reminder.ReminderType != "task" && reminder.ReminderType != "personal" {
+			continue
+		}
+
+		next := reminder.ReminderTime
+		for next.Before(startOfDay) {
+			next = next.AddDate(0, 0, 1)
+		}
+		updates[reminder.ID] = map[string]interface{}{
+			"reminderTime":    next,
+			"rolledOverCount": reminder.RolledOverCount + 1,
+		}
+	}
+	return updates
+}
\ No newline at end of file
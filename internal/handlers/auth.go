@@ -1,40 +1,82 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"focusflow-be/internal/config"
+	"focusflow-be/internal/locale"
 	"focusflow-be/internal/models"
 	"focusflow-be/internal/services"
 )
 
 type AuthHandler struct {
-	authService     *services.AuthService
-	googleService   *services.GoogleService
-	firebaseService *services.FirebaseService
+	requestStore
+	authService   *services.AuthService
+	googleService *services.GoogleService
+	config        *config.Config
 }
 
-func NewAuthHandler(authService *services.AuthService, googleService *services.GoogleService, firebaseService *services.FirebaseService) *AuthHandler {
+func NewAuthHandler(authService *services.AuthService, googleService *services.GoogleService, firebaseService services.Store, cfg *config.Config) *AuthHandler {
 	return &AuthHandler{
-		authService:     authService,
-		googleService:   googleService,
-		firebaseService: firebaseService,
+		authService:   authService,
+		googleService: googleService,
+		requestStore:  requestStore{firebaseService},
+		config:        cfg,
 	}
 }
 
 func (h *AuthHandler) GoogleAuth(c *gin.Context) {
-	url := h.googleService.GetAuthURL()
-	c.Redirect(http.StatusTemporaryRedirect, url)
+	redirectURI, err := h.googleService.ResolveRedirectURI(c.Query("redirect"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Redirect URI not allowed", "details": err.Error()})
+		return
+	}
+
+	state, err := h.googleService.NewOAuthState(redirectURI)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start authentication", "details": err.Error()})
+		return
+	}
+
+	// SPA and mobile clients can ask for the token as JSON instead of the HTML page
+	if c.Query("response") == "json" {
+		state.ResponseMode = "json"
+	}
+
+	frontendURL, err := h.googleService.ResolveFrontendURL(c.Query("frontend"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Frontend URL not allowed", "details": err.Error()})
+		return
+	}
+	state.FrontendURL = frontendURL
+
+	authURL, err := h.googleService.GetAuthURL(state)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start authentication", "details": err.Error()})
+		return
+	}
+	if h.config.GoogleOAuthPKCE {
+		http.SetCookie(c.Writer, h.googleService.VerifierCookie(state, isHTTPS(c)))
+	}
+	c.Redirect(http.StatusTemporaryRedirect, authURL)
 }
 
 func (h *AuthHandler) GoogleCallback(c *gin.Context) {
+	wantsJSON := strings.Contains(c.GetHeader("Accept"), "application/json")
+
 	code := c.Query("code")
 	if code == "" {
-		c.HTML(http.StatusBadRequest, "error.html", gin.H{
+		callbackError(c, wantsJSON, http.StatusBadRequest, gin.H{
 			"error": "No authorization code provided",
 		})
 		return
@@ -43,17 +85,40 @@ func (h *AuthHandler) GoogleCallback(c *gin.Context) {
 	// Handle OAuth errors
 	if errorParam := c.Query("error"); errorParam != "" {
 		errorDesc := c.Query("error_description")
-		c.HTML(http.StatusBadRequest, "error.html", gin.H{
+		callbackError(c, wantsJSON, http.StatusBadRequest, gin.H{
 			"error":       errorParam,
 			"description": errorDesc,
 		})
 		return
 	}
 
-	token, err := h.googleService.ExchangeCodeForToken(code)
+	state, err := h.googleService.ParseState(c.Query("state"))
+	if err != nil {
+		callbackError(c, wantsJSON, http.StatusBadRequest, gin.H{
+			"error":       "Invalid OAuth state",
+			"description": err.Error(),
+		})
+		return
+	}
+	wantsJSON = wantsJSON || state.ResponseMode == "json"
+
+	if h.config.GoogleOAuthPKCE {
+		// The verifier is single use; drop it whatever the outcome
+		cookie, _ := c.Cookie(services.PKCECookie)
+		c.SetCookie(services.PKCECookie, "", -1, "/", "", isHTTPS(c), true)
+		if err := h.googleService.RestoreVerifier(state, cookie); err != nil {
+			callbackError(c, wantsJSON, http.StatusBadRequest, gin.H{
+				"error":       "Invalid OAuth state",
+				"description": err.Error(),
+			})
+			return
+		}
+	}
+
+	token, err := h.googleService.ExchangeCodeForToken(code, state)
 	if err != nil {
 		log.Printf("Token exchange error: %v", err)
-		c.HTML(http.StatusBadRequest, "error.html", gin.H{
+		callbackError(c, wantsJSON, http.StatusBadRequest, gin.H{
 			"error":       "Token exchange failed",
 			"description": err.Error(),
 		})
@@ -63,7 +128,7 @@ func (h *AuthHandler) GoogleCallback(c *gin.Context) {
 	userInfo, err := h.googleService.GetUserInfo(token)
 	if err != nil {
 		log.Printf("Get user info error: %v", err)
-		c.HTML(http.StatusBadRequest, "error.html", gin.H{
+		callbackError(c, wantsJSON, http.StatusBadRequest, gin.H{
 			"error":       "Failed to get user info",
 			"description": err.Error(),
 		})
@@ -76,17 +141,18 @@ func (h *AuthHandler) GoogleCallback(c *gin.Context) {
 		Name:         userInfo.Name,
 		AccessToken:  token.AccessToken,
 		RefreshToken: &token.RefreshToken,
-		CreatedAt:    time.Now(),
-		LastLogin:    time.Now(),
+		Locale:       userInfo.Locale,
+		CreatedAt:    h.config.Clock.Now(),
+		LastLogin:    h.config.Clock.Now(),
 	}
 
 	// Check if user exists
-	existingUser, err := h.firebaseService.GetUser(userInfo.ID)
+	existingUser, err := h.store(c).GetUser(userInfo.ID)
 	if err != nil {
 		// User doesn't exist, create new one
-		if err := h.firebaseService.CreateUser(userSession); err != nil {
+		if err := h.store(c).CreateUser(userSession); err != nil {
 			log.Printf("Create user error: %v", err)
-			c.HTML(http.StatusInternalServerError, "error.html", gin.H{
+			callbackError(c, wantsJSON, http.StatusInternalServerError, gin.H{
 				"error":       "Failed to create user",
 				"description": err.Error(),
 			})
@@ -97,8 +163,12 @@ func (h *AuthHandler) GoogleCallback(c *gin.Context) {
 		updates := map[string]interface{}{
 			"accessToken":  token.AccessToken,
 			"refreshToken": &token.RefreshToken,
+			"lastLogin":    userSession.LastLogin,
+		}
+		if existingUser.Locale == "" && userInfo.Locale != "" {
+			updates["locale"] = userInfo.Locale
 		}
-		if err := h.firebaseService.UpdateUser(existingUser.UserID, updates); err != nil {
+		if err := h.store(c).UpdateUser(existingUser.UserID, updates); err != nil {
 			log.Printf("Update user error: %v", err)
 		}
 	}
@@ -106,13 +176,31 @@ func (h *AuthHandler) GoogleCallback(c *gin.Context) {
 	jwtToken, err := h.authService.CreateJWT(userSession)
 	if err != nil {
 		log.Printf("JWT creation error: %v", err)
-		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
+		callbackError(c, wantsJSON, http.StatusInternalServerError, gin.H{
 			"error":       "Failed to create JWT",
 			"description": err.Error(),
 		})
 		return
 	}
 
+	if wantsJSON {
+		c.JSON(http.StatusOK, gin.H{
+			"token": jwtToken,
+			"user": gin.H{
+				"id":    userSession.UserID,
+				"email": userSession.Email,
+				"name":  userSession.Name,
+			},
+		})
+		return
+	}
+
+	// Hand the token to the front end in the fragment so it never reaches server logs
+	if state.FrontendURL != "" {
+		c.Redirect(http.StatusFound, state.FrontendURL+"#token="+url.QueryEscape(jwtToken))
+		return
+	}
+
 	// Railway always serves over HTTPS, so force HTTPS for API calls
 	apiBase := fmt.Sprintf("https://%s", c.Request.Host)
 
@@ -220,6 +308,21 @@ curl -H "Authorization: Bearer %s" \\
 	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(successHTML))
 }
 
+// callbackError reports an OAuth callback failure as JSON or as the error page
+func callbackError(c *gin.Context, wantsJSON bool, status int, details gin.H) {
+	if wantsJSON {
+		c.JSON(status, details)
+		return
+	}
+	c.HTML(status, "error.html", details)
+}
+
+// isHTTPS reports whether the client reached us over HTTPS, directly or
+// through Railway's proxy, so cookies can be marked Secure
+func isHTTPS(c *gin.Context) bool {
+	return c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+}
+
 func (h *AuthHandler) GetMe(c *gin.Context) {
 	user, exists := c.Get("user")
 	if !exists {
@@ -235,11 +338,500 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 	})
 }
 
+// GetProfile returns everything the front-end needs to bootstrap in one call:
+// the user, their preferences, the enabled features and item counts. Tokens
+// are never included.
+func (h *AuthHandler) GetProfile(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+	profile, err := h.store(c).GetUser(userSession.UserID)
+	if err != nil {
+		respondResourceError(c, "User", err)
+		return
+	}
+
+	preferences := userPreferences(profile)
+	loc, err := time.LoadLocation(preferences.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	now := h.config.Clock.Now().In(loc)
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	counts, err := h.store(c).GetBadgeCounts(userSession.UserID, dayStart, dayStart.AddDate(0, 0, 1),
+		overdueTasksBefore(now, loc, h.config.OverdueGrace), now.Add(-h.config.OverdueGrace))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch item counts", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":          profile.UserID,
+		"email":       profile.Email,
+		"name":        profile.Name,
+		"createdAt":   profile.CreatedAt,
+		"lastLogin":   profile.LastLogin,
+		"preferences": preferences,
+		"features":    h.config.Features,
+		"counts":      counts,
+	})
+}
+
+// UpdatePreferences changes the caller's stored preferences and returns the
+// updated profile
+func (h *AuthHandler) UpdatePreferences(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	var req models.UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	updates, err := preferenceUpdates(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid preferences", "details": err.Error()})
+		return
+	}
+	if len(updates) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+
+	if err := h.store(c).UpdateUser(userSession.UserID, updates); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update preferences", "details": err.Error()})
+		return
+	}
+
+	profile, err := h.store(c).GetUser(userSession.UserID)
+	if err != nil {
+		respondResourceError(c, "User", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":          profile.UserID,
+		"email":       profile.Email,
+		"name":        profile.Name,
+		"preferences": userPreferences(profile),
+	})
+}
+
+// preferenceUpdates validates a preferences request and turns it into user
+// document updates; empty strings remove the stored value
+func preferenceUpdates(req *models.UpdatePreferencesRequest) (map[string]interface{}, error) {
+	updates := make(map[string]interface{})
+	setOrClear := func(field, value string) {
+		if value == "" {
+			updates[field] = services.DeleteField
+		} else {
+			updates[field] = value
+		}
+	}
+
+	if req.Timezone != nil {
+		timezone := strings.TrimSpace(*req.Timezone)
+		if timezone != "" {
+			if _, err := time.LoadLocation(timezone); err != nil {
+				return nil, fmt.Errorf("timezone %q is not a valid IANA time zone", timezone)
+			}
+		}
+		setOrClear("timezone", timezone)
+	}
+	if req.Locale != nil {
+		tag := strings.TrimSpace(*req.Locale)
+		if tag != "" && !locale.Supported(tag) {
+			return nil, fmt.Errorf("locale %q is not supported", tag)
+		}
+		setOrClear("locale", tag)
+	}
+	if req.CalendarID != nil {
+		setOrClear("calendarId", strings.TrimSpace(*req.CalendarID))
+	}
+	if req.DefaultReminderLead != nil {
+		updates["defaultReminderLead"] = *req.DefaultReminderLead
+	}
+	if req.WebhookURL != nil {
+		webhookURL := strings.TrimSpace(*req.WebhookURL)
+		if webhookURL != "" {
+			parsed, err := url.Parse(webhookURL)
+			if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+				return nil, fmt.Errorf("webhookUrl must be an absolute http(s) URL")
+			}
+		}
+		setOrClear("webhookUrl", webhookURL)
+	}
+	if req.CarryForwardReminders != nil {
+		if *req.CarryForwardReminders {
+			updates["carryForwardReminders"] = true
+		} else {
+			updates["carryForwardReminders"] = services.DeleteField
+		}
+	}
+	if req.AutoMeetingStatus != nil {
+		if *req.AutoMeetingStatus {
+			updates["autoMeetingStatus"] = true
+		} else {
+			updates["autoMeetingStatus"] = services.DeleteField
+		}
+	}
+
+	return updates, nil
+}
+
+// userPreferences reads a user's stored settings, filling in defaults for
+// anything unset
+func userPreferences(user *models.UserSession) models.UserPreferences {
+	preferences := models.UserPreferences{
+		Timezone:              user.Timezone,
+		Locale:                locale.Normalize(user.Locale),
+		CalendarID:            user.CalendarID,
+		WebhookURL:            user.WebhookURL,
+		CarryForwardReminders: user.CarryForwardReminders,
+		AutoMeetingStatus:     user.AutoMeetingStatus,
+	}
+	if user.DefaultReminderLead != nil {
+		preferences.DefaultReminderLead = *user.DefaultReminderLead
+	}
+	if preferences.Timezone == "" {
+		preferences.Timezone = "UTC"
+	}
+	if preferences.CalendarID == "" {
+		preferences.CalendarID = "primary"
+	}
+	return preferences
+}
+
+// ValidateToken reports whether the bearer token is usable and how long it has
+// left. It always answers 200 so clients can branch on the body instead of
+// treating an expired token as a failed request.
+func (h *AuthHandler) ValidateToken(c *gin.Context) {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		c.JSON(http.StatusOK, gin.H{"valid": false, "reason": "missing"})
+		return
+	}
+
+	claims, err := h.authService.ParseJWT(token)
+	if err != nil {
+		if errors.Is(err, services.ErrTokenExpired) && claims.ExpiresAt != nil {
+			c.JSON(http.StatusOK, gin.H{
+				"valid":     false,
+				"reason":    "expired",
+				"userId":    claims.UserID,
+				"expiresAt": claims.ExpiresAt.Time,
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"valid": false, "reason": "invalid"})
+		return
+	}
+
+	response := gin.H{"valid": true, "userId": claims.UserID}
+	if claims.ExpiresAt != nil {
+		response["expiresAt"] = claims.ExpiresAt.Time
+		response["expiresIn"] = int(claims.ExpiresAt.Time.Sub(h.config.Clock.Now()).Seconds())
+	}
+	c.JSON(http.StatusOK, response)
+}
+
 func (h *AuthHandler) Debug(c *gin.Context) {
+	redirectURI, _ := h.googleService.ResolveRedirectURI("")
 	c.JSON(http.StatusOK, gin.H{
 		"status":            "ok",
 		"hasClientId":       h.googleService != nil,
-		"hasFirebaseConfig": h.firebaseService != nil,
-		"redirectUri":       h.googleService.GetAuthURL(),
+		"hasFirebaseConfig": h.store(c) != nil,
+		"redirectUri":       redirectURI,
 	})
+}
+
+// ExportData streams the caller's profile and all of their tasks, meetings and
+// reminders as a single JSON bundle. OAuth tokens are never included.
+func (h *AuthHandler) ExportData(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	profile := &models.UserProfile{
+		UserID: userSession.UserID,
+		Email:  userSession.Email,
+		Name:   userSession.Name,
+	}
+	if stored, err := h.store(c).GetUser(userSession.UserID); err == nil {
+		profile.Locale = stored.Locale
+		profile.CreatedAt = stored.CreatedAt
+		profile.LastLogin = stored.LastLogin
+	}
+
+	// Fetch everything up front so a failure can still be reported with a status code
+	tasks, err := h.store(c).GetTasks(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks", "details": err.Error()})
+		return
+	}
+	meetings, err := h.store(c).GetMeetings(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meetings", "details": err.Error()})
+		return
+	}
+	reminders, err := h.store(c).GetReminders(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reminders", "details": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="focusflow-export-%s.json"`, h.config.Clock.Now().Format("20060102")))
+	c.Status(http.StatusOK)
+
+	// Write the bundle piece by piece instead of marshaling it in one buffer
+	w := c.Writer
+	header, _ := json.Marshal(gin.H{"version": 1, "exportedAt": h.config.Clock.Now().UTC(), "profile": profile})
+	w.Write(header[:len(header)-1])
+	streamJSONArray(w, "tasks", tasks)
+	streamJSONArray(w, "meetings", meetings)
+	streamJSONArray(w, "reminders", reminders)
+	io.WriteString(w, "}")
+}
+
+// streamJSONArray writes `,"name":[...]` one element at a time, flushing as it goes
+func streamJSONArray[T any](w gin.ResponseWriter, name string, items []T) {
+	fmt.Fprintf(w, `,%q:[`, name)
+	for i, item := range items {
+		if i > 0 {
+			io.WriteString(w, ",")
+		}
+		if data, err := json.Marshal(item); err == nil {
+			w.Write(data)
+		} else {
+			io.WriteString(w, "null")
+		}
+	}
+	io.WriteString(w, "]")
+	w.Flush()
+}
+
+// linkImportedTasks points imported reminders and action items at the tasks
+// created for them; links to tasks that were not imported are dropped.
+func linkImportedTasks(sourceIDs, createdIDs []string, meetings, reminders []interface{}) {
+	created := make(map[string]string, len(sourceIDs))
+	for i, id := range sourceIDs {
+		if id != "" && i < len(createdIDs) {
+			created[id] = createdIDs[i]
+		}
+	}
+	relink := func(taskID *string) *string {
+		if taskID == nil {
+			return nil
+		}
+		if id, ok := created[*taskID]; ok {
+			return &id
+		}
+		return nil
+	}
+
+	for _, item := range meetings {
+		meeting := item.(*models.Meeting)
+		for i := range meeting.ActionItems {
+			meeting.ActionItems[i].TaskID = relink(meeting.ActionItems[i].TaskID)
+		}
+	}
+	for _, item := range reminders {
+		reminder := item.(*models.Reminder)
+		reminder.TaskID = relink(reminder.TaskID)
+	}
+}
+
+// ImportData recreates the tasks, meetings and reminders of an exported bundle
+// under the caller's account. Records get new IDs and lose their Google Calendar
+// link, and task links are moved to the new task IDs; invalid records are
+// reported individually instead of failing the import.
+func (h *AuthHandler) ImportData(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	var bundle models.DataBundle
+	if err := c.ShouldBindJSON(&bundle); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	result := &models.ImportResult{Errors: []*models.ImportError{}}
+	now := h.config.Clock.Now()
+	reject := func(kind string, index int, reason string) {
+		result.Errors = append(result.Errors, &models.ImportError{Type: kind, Index: index, Error: reason})
+	}
+
+	var tasks []interface{}
+	var sourceTaskIDs []string
+	for i, task := range bundle.Tasks {
+		if reason := validateImportedTask(task); reason != "" {
+			reject("task", i, reason)
+			continue
+		}
+		sourceTaskIDs = append(sourceTaskIDs, task.ID)
+		task.ID = ""
+		task.UserID = userSession.UserID
+		task.GoogleEventID = nil
+		task.Completed = task.Status == "completed"
+		if task.CreatedAt.IsZero() {
+			task.CreatedAt = now
+		}
+		task.UpdatedAt = now
+		tasks = append(tasks, task)
+	}
+
+	var meetings []interface{}
+	for i, meeting := range bundle.Meetings {
+		if reason := validateImportedMeeting(meeting); reason != "" {
+			reject("meeting", i, reason)
+			continue
+		}
+		meeting.ID = ""
+		meeting.UserID = userSession.UserID
+		meeting.GoogleEventID = nil
+		if meeting.CreatedAt.IsZero() {
+			meeting.CreatedAt = now
+		}
+		meeting.UpdatedAt = now
+		meetings = append(meetings, meeting)
+	}
+
+	var reminders []interface{}
+	for i, reminder := range bundle.Reminders {
+		if reason := validateImportedReminder(reminder); reason != "" {
+			reject("reminder", i, reason)
+			continue
+		}
+		reminder.ID = ""
+		reminder.UserID = userSession.UserID
+		reminder.GoogleEventID = nil
+		if reminder.CreatedAt.IsZero() {
+			reminder.CreatedAt = now
+		}
+		reminder.UpdatedAt = now
+		reminders = append(reminders, reminder)
+	}
+
+	batches := []struct {
+		collection string
+		items      []interface{}
+		count      *int
+	}{
+		{"tasks", tasks, &result.Tasks},
+		{"meetings", meetings, &result.Meetings},
+		{"reminders", reminders, &result.Reminders},
+	}
+	for _, batch := range batches {
+		ids, err := h.store(c).BatchCreate(batch.collection, batch.items)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import " + batch.collection, "details": err.Error(), "imported": result})
+			return
+		}
+		*batch.count = len(ids)
+		if batch.collection == "tasks" {
+			linkImportedTasks(sourceTaskIDs, ids, meetings, reminders)
+		}
+	}
+
+	log.Printf("📦 Imported %d tasks, %d meetings, %d reminders for %s (%d skipped)",
+		result.Tasks, result.Meetings, result.Reminders, userSession.Email, len(result.Errors))
+
+	c.JSON(http.StatusOK, result)
+}
+
+func validateImportedTask(task *models.Task) string {
+	switch {
+	case task == nil:
+		return "record is empty"
+	case task.Title == "":
+		return "title is required"
+	case !models.IsValidPriority(task.Priority):
+		return fmt.Sprintf("invalid priority %q", task.Priority)
+	case task.EstimatedHours != nil && *task.EstimatedHours < 0:
+		return "estimatedHours must not be negative"
+	case task.ActualHours != nil && *task.ActualHours < 0:
+		return "actualHours must not be negative"
+	}
+	if err := models.ValidateMetadata(task.Metadata); err != nil {
+		return err.Error()
+	}
+	switch task.Status {
+	case "":
+		task.Status = "todo"
+	case "todo", "in-progress", "completed":
+	default:
+		return fmt.Sprintf("invalid status %q", task.Status)
+	}
+	return ""
+}
+
+func validateImportedMeeting(meeting *models.Meeting) string {
+	switch {
+	case meeting == nil:
+		return "record is empty"
+	case meeting.Title == "":
+		return "title is required"
+	case meeting.StartTime.IsZero() || meeting.EndTime.IsZero():
+		return "startTime and endTime are required"
+	case !meeting.EndTime.After(meeting.StartTime):
+		return "endTime must be after startTime"
+	}
+	switch meeting.MeetingType {
+	case "call", "in-person", "video":
+	default:
+		return fmt.Sprintf("invalid meetingType %q", meeting.MeetingType)
+	}
+	switch meeting.Status {
+	case "":
+		meeting.Status = "scheduled"
+	case "scheduled", "ongoing", "completed", "cancelled":
+	default:
+		return fmt.Sprintf("invalid status %q", meeting.Status)
+	}
+	return ""
+}
+
+func validateImportedReminder(reminder *models.Reminder) string {
+	switch {
+	case reminder == nil:
+		return "record is empty"
+	case reminder.Title == "":
+		return "title is required"
+	case reminder.ReminderTime.IsZero():
+		return "reminderTime is required"
+	case !models.IsValidPriority(reminder.Priority):
+		return fmt.Sprintf("invalid priority %q", reminder.Priority)
+	}
+	if reminder.Recurrence != nil {
+		if err := reminder.Recurrence.Validate(); err != nil {
+			return err.Error()
+		}
+	}
+	switch reminder.ReminderType {
+	case "task", "meeting", "personal":
+	default:
+		return fmt.Sprintf("invalid reminderType %q", reminder.ReminderType)
+	}
+	return ""
 }
\ No newline at end of file
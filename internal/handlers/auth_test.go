@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"focusflow-be/internal/clock"
+	"focusflow-be/internal/models"
+	"focusflow-be/internal/services"
+)
+
+func TestExportData(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		failOn string
+		want   int
+	}{
+		{"complete export", "", http.StatusOK},
+		{"tasks unavailable", "tasks", http.StatusInternalServerError},
+		{"meetings unavailable", "meetings", http.StatusInternalServerError},
+		{"reminders unavailable", "reminders", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.tasks["t1"] = &models.Task{ID: "t1", UserID: "user-1", Title: "Write report"}
+			if tt.failOn != "" {
+				store.fail[tt.failOn] = errors.New("firestore unavailable")
+			}
+			h := NewAuthHandler(nil, nil, store, testConfig(now))
+
+			w := serve(h.ExportData, http.MethodGet, "/auth/me/export", "/auth/me/export", "", "user-1")
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want != http.StatusOK {
+				// A failed export must not look like a backup
+				if w.Header().Get("Content-Disposition") != "" {
+					t.Errorf("failed export was sent as an attachment")
+				}
+				return
+			}
+
+			var bundle struct {
+				Tasks []models.Task `json:"tasks"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &bundle); err != nil {
+				t.Fatalf("export is not valid JSON: %v", err)
+			}
+			if len(bundle.Tasks) != 1 {
+				t.Errorf("exported %d tasks, want 1", len(bundle.Tasks))
+			}
+		})
+	}
+}
+
+func TestImportDataRelinksTasks(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	store := newMockStore()
+	h := NewAuthHandler(nil, nil, store, testConfig(now))
+
+	body := `{
+		"version": 1,
+		"tasks": [
+			{"id": "old-a", "title": "Draft agenda", "priority": "medium"},
+			{"id": "old-bad", "title": "", "priority": "medium"},
+			{"id": "old-b", "title": "Send notes", "priority": "low"}
+		],
+		"meetings": [{
+			"id": "old-m", "title": "Planning", "meetingType": "video",
+			"startTime": "2026-10-20T10:00:00Z", "endTime": "2026-10-20T11:00:00Z",
+			"actionItems": [
+				{"id": "a1", "text": "Send notes", "taskId": "old-b"},
+				{"id": "a2", "text": "Book room", "taskId": "old-bad"},
+				{"id": "a3", "text": "Follow up"}
+			]
+		}],
+		"reminders": [
+			{"id": "old-r1", "title": "Agenda due", "reminderTime": "2026-10-19T09:00:00Z", "reminderType": "task", "priority": "medium", "taskId": "old-a"},
+			{"id": "old-r2", "title": "Someone else's task", "reminderTime": "2026-10-19T09:00:00Z", "reminderType": "task", "priority": "medium", "taskId": "elsewhere"}
+		]
+	}`
+
+	w := serve(h.ImportData, http.MethodPost, "/auth/me/import", "/auth/me/import", body, "user-1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if len(store.batches) != 3 {
+		t.Fatalf("got %d batches, want 3", len(store.batches))
+	}
+
+	taskID := func(id *string) string {
+		if id == nil {
+			return "<nil>"
+		}
+		return *id
+	}
+
+	meeting := store.batches[1][0].(*models.Meeting)
+	wantActions := []string{"tasks-new-2", "<nil>", "<nil>"}
+	for i, want := range wantActions {
+		if got := taskID(meeting.ActionItems[i].TaskID); got != want {
+			t.Errorf("action item %d taskId = %s, want %s", i, got, want)
+		}
+	}
+
+	wantReminders := []string{"tasks-new-1", "<nil>"}
+	for i, want := range wantReminders {
+		reminder := store.batches[2][i].(*models.Reminder)
+		if got := taskID(reminder.TaskID); got != want {
+			t.Errorf("reminder %d taskId = %s, want %s", i, got, want)
+		}
+	}
+}
+
+func TestPreferenceUpdates(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{"nothing sent", `{}`, map[string]interface{}{}, false},
+		{"time zone", `{"timezone": " Europe/Berlin "}`, map[string]interface{}{"timezone": "Europe/Berlin"}, false},
+		{"clear time zone", `{"timezone": ""}`, map[string]interface{}{"timezone": services.DeleteField}, false},
+		{"invalid time zone", `{"timezone": "Mars/Olympus"}`, nil, true},
+		{"locale", `{"locale": "en-GB"}`, map[string]interface{}{"locale": "en-GB"}, false},
+		{"unsupported locale", `{"locale": "xx"}`, nil, true},
+		{"calendar", `{"calendarId": " team@group.calendar.google.com "}`, map[string]interface{}{"calendarId": "team@group.calendar.google.com"}, false},
+		{"reminder lead", `{"defaultReminderLead": 0}`, map[string]interface{}{"defaultReminderLead": 0}, false},
+		{"webhook", `{"webhookUrl": "https://hooks.example.com/x"}`, map[string]interface{}{"webhookUrl": "https://hooks.example.com/x"}, false},
+		{"clear webhook", `{"webhookUrl": " "}`, map[string]interface{}{"webhookUrl": services.DeleteField}, false},
+		{"relative webhook", `{"webhookUrl": "/hooks/x"}`, nil, true},
+		{"non-http webhook", `{"webhookUrl": "ftp://hooks.example.com/x"}`, nil, true},
+		{"switches on", `{"carryForwardReminders": true, "autoMeetingStatus": true}`, map[string]interface{}{"carryForwardReminders": true, "autoMeetingStatus": true}, false},
+		{"switches off", `{"carryForwardReminders": false, "autoMeetingStatus": false}`, map[string]interface{}{"carryForwardReminders": services.DeleteField, "autoMeetingStatus": services.DeleteField}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req models.UpdatePreferencesRequest
+			if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+				t.Fatal(err)
+			}
+			updates, err := preferenceUpdates(&req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(updates, tt.want) {
+				t.Errorf("updates = %v, want %v", updates, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateToken(t *testing.T) {
+	issued := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	cfg := testConfig(issued)
+	cfg.JWTSecret = "test-secret"
+	auth, err := services.NewAuthService(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := auth.CreateJWT(&models.UserSession{UserID: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCfg := testConfig(issued)
+	otherCfg.JWTSecret = "other-secret"
+	other, err := services.NewAuthService(otherCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, err := other.CreateJWT(&models.UserSession{UserID: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiresAt := issued.Add(24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		header        string
+		now           time.Time
+		wantValid     bool
+		wantReason    string
+		wantUser      string
+		wantExpiresIn int
+	}{
+		{"valid", "Bearer " + token, issued.Add(time.Hour), true, "", "user-1", 23 * 60 * 60},
+		{"expired", "Bearer " + token, expiresAt.Add(time.Minute), false, "expired", "user-1", 0},
+		{"malformed", "Bearer not-a-jwt", issued, false, "invalid", "", 0},
+		{"signed with another key", "Bearer " + forged, issued, false, "invalid", "", 0},
+		{"missing", "", issued, false, "missing", "", 0},
+		{"not a bearer token", "Basic " + token, issued, false, "missing", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Clock.(*clock.Fake).Set(tt.now)
+			h := NewAuthHandler(auth, nil, newMockStore(), cfg)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.GET("/auth/validate", h.ValidateToken)
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/auth/validate", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			r.ServeHTTP(w, req)
+
+			// Every outcome is a 200 so clients branch on the body
+			var body struct {
+				Valid     bool       `json:"valid"`
+				Reason    string     `json:"reason"`
+				UserID    string     `json:"userId"`
+				ExpiresAt *time.Time `json:"expiresAt"`
+				ExpiresIn int        `json:"expiresIn"`
+			}
+			decode(t, w, &body)
+			if body.Valid != tt.wantValid || body.Reason != tt.wantReason || body.UserID != tt.wantUser || body.ExpiresIn != tt.wantExpiresIn {
+				t.Errorf("body = %s", w.Body.String())
+			}
+			if hasExpiry := body.ExpiresAt != nil; hasExpiry != (tt.wantUser != "") {
+				t.Errorf("expiresAt sent = %v for %s", hasExpiry, w.Body.String())
+			} else if hasExpiry && !body.ExpiresAt.Equal(expiresAt) {
+				t.Errorf("expiresAt = %v, want %v", body.ExpiresAt, expiresAt)
+			}
+		})
+	}
+}
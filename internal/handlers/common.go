@@ -0,0 +1,451 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"focusflow-be/internal/config"
+	"focusflow-be/internal/models"
+	"focusflow-be/internal/services"
+)
+
+// RegisterValidators adds the custom binding tags used by request DTOs
+func RegisterValidators() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("unexpected validator engine")
+	}
+	return v.RegisterValidation("priority", func(fl validator.FieldLevel) bool {
+		return models.IsValidPriority(fl.Field().String())
+	})
+}
+
+// Ownership policy
+//
+// A resource that doesn't exist and a resource owned by another user both
+// return 404, so callers can't probe which IDs exist. 403 is reserved for
+// resources the caller is allowed to see but not to change, such as a task
+// shared with them read-only.
+var (
+	errResourceNotFound  = errors.New("resource not found")
+	errResourceForbidden = errors.New("operation not permitted on this resource")
+)
+
+// checkOwnership applies the ownership policy to a task, meeting or reminder
+func checkOwnership(resource interface{}, userID string) error {
+	var ownerID string
+	switch r := resource.(type) {
+	case *models.Task:
+		if r == nil {
+			return errResourceNotFound
+		}
+		ownerID = r.UserID
+	case *models.Meeting:
+		if r == nil {
+			return errResourceNotFound
+		}
+		ownerID = r.UserID
+	case *models.Reminder:
+		if r == nil {
+			return errResourceNotFound
+		}
+		ownerID = r.UserID
+	default:
+		return errResourceNotFound
+	}
+
+	if ownerID != userID {
+		return errResourceNotFound
+	}
+	return nil
+}
+
+// respondResourceError writes the status the ownership policy assigns to err
+func respondResourceError(c *gin.Context, resource string, err error) {
+	switch {
+	case errors.Is(err, errResourceNotFound), errors.Is(err, services.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": resource + " not found"})
+	case errors.Is(err, errResourceForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to modify this " + strings.ToLower(resource)})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch " + strings.ToLower(resource), "details": err.Error()})
+	}
+}
+
+// fetchErrors collects the collections a combined view, such as the overview
+// or the calendar, failed to load. The view is still built from the rest and
+// marked partial, unless the request asks for ?strict=true.
+type fetchErrors []models.CollectionError
+
+// partialHeader lists the failed collections on responses whose body is a
+// bare array and so has nowhere to carry a partial flag
+const partialHeader = "X-Partial-Content"
+
+func (f *fetchErrors) add(collection string, err error) {
+	log.Printf("⚠️ Failed to load %s, serving a partial response: %v", collection, err)
+	*f = append(*f, models.CollectionError{Collection: collection, Error: err.Error()})
+}
+
+// rejectIfStrict writes a 500 listing the failures when the request is strict
+// and there are any, and reports whether it did
+func (f fetchErrors) rejectIfStrict(c *gin.Context) bool {
+	if len(f) == 0 || c.Query("strict") != "true" {
+		return false
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load all collections and strict mode is on", "errors": f})
+	return true
+}
+
+// setHeader sets partialHeader to the failed collections, if any
+func (f fetchErrors) setHeader(c *gin.Context) {
+	if len(f) == 0 {
+		return
+	}
+	collections := make([]string, len(f))
+	for i, failure := range f {
+		collections[i] = failure.Collection
+	}
+	c.Header(partialHeader, strings.Join(collections, ","))
+}
+
+// warnings collects problems that are worth telling the client about but don't
+// stop a create, unless the request asks for ?strict=true
+type warnings []string
+
+func (w *warnings) add(format string, args ...interface{}) {
+	*w = append(*w, fmt.Sprintf(format, args...))
+}
+
+// rejectIfStrict writes a 400 listing the warnings when the request is strict
+// and there are any, and reports whether it did
+func (w warnings) rejectIfStrict(c *gin.Context) bool {
+	if len(w) == 0 || c.Query("strict") != "true" {
+		return false
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Request has warnings and strict mode is on", "warnings": w})
+	return true
+}
+
+// attach adds the warnings to a response body when there are any
+func (w warnings) attach(response gin.H) gin.H {
+	if len(w) > 0 {
+		response["warnings"] = w
+	}
+	return response
+}
+
+// applyClearFields marks each requested field for removal. A field can't be
+// set and cleared in the same request.
+func applyClearFields(updates map[string]interface{}, clearFields []string) error {
+	for _, field := range clearFields {
+		if _, ok := updates[field]; ok {
+			return fmt.Errorf("field %s cannot be both set and cleared", field)
+		}
+		updates[field] = services.DeleteField
+	}
+	return nil
+}
+
+const (
+	maxPageSize      = 100
+	nextCursorHeader = "X-Next-Cursor"
+)
+
+// cursorKey signs pagination cursors with the JWT secret, falling back to the
+// Google client secret when tokens are signed with RS256 and there is none
+func cursorKey(cfg *config.Config) []byte {
+	if cfg.JWTSecret != "" {
+		return []byte(cfg.JWTSecret)
+	}
+	return []byte(cfg.GoogleClientSecret)
+}
+
+// parseRelativeDuration accepts Go durations ("36h") plus day and week
+// suffixes ("3d", "2w") and rejects non-positive values
+func parseRelativeDuration(value string) (time.Duration, error) {
+	var d time.Duration
+	switch {
+	case strings.HasSuffix(value, "d") || strings.HasSuffix(value, "w"):
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+		if strings.HasSuffix(value, "w") {
+			d *= 7
+		}
+	default:
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		d = parsed
+	}
+
+	if d <= 0 {
+		return 0, fmt.Errorf("duration must be positive")
+	}
+	return d, nil
+}
+
+const mimeCSV = "text/csv"
+
+// respondData writes data as JSON, or as CSV when the Accept header prefers
+// text/csv. When the request has ?fields=a,b only those JSON fields of each
+// item are returned (or become the CSV columns); unknown names are a 400.
+func respondData(c *gin.Context, status int, data interface{}) {
+	var fields []string
+	for _, field := range strings.Split(c.Query("fields"), ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+
+	if c.NegotiateFormat(binding.MIMEJSON, mimeCSV) == mimeCSV {
+		if err := writeCSV(c, status, data, fields); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fields parameter", "details": err.Error()})
+		}
+		return
+	}
+
+	if len(fields) == 0 {
+		c.JSON(status, data)
+		return
+	}
+
+	projected, err := projectFields(data, fields)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fields parameter", "details": err.Error()})
+		return
+	}
+
+	c.JSON(status, projected)
+}
+
+// projectFields reduces a struct, or a slice of structs, to the given JSON fields
+func projectFields(data interface{}, fields []string) (interface{}, error) {
+	value := reflect.ValueOf(data)
+
+	if value.Kind() == reflect.Slice {
+		elemType := value.Type().Elem()
+		for elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+		index, err := fieldIndex(elemType, fields)
+		if err != nil {
+			return nil, err
+		}
+
+		items := make([]map[string]interface{}, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			items = append(items, pickFields(value.Index(i), index))
+		}
+		return items, nil
+	}
+
+	structValue := reflect.Indirect(value)
+	index, err := fieldIndex(structValue.Type(), fields)
+	if err != nil {
+		return nil, err
+	}
+	return pickFields(structValue, index), nil
+}
+
+// fieldIndex maps each requested JSON name to its struct field position
+func fieldIndex(t reflect.Type, fields []string) (map[string]int, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("field selection is not supported for this response")
+	}
+
+	byName := make(map[string]int)
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			byName[name] = i
+		}
+	}
+
+	index := make(map[string]int, len(fields))
+	for _, field := range fields {
+		i, ok := byName[field]
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q", field)
+		}
+		index[field] = i
+	}
+	return index, nil
+}
+
+func pickFields(value reflect.Value, index map[string]int) map[string]interface{} {
+	value = reflect.Indirect(value)
+	picked := make(map[string]interface{}, len(index))
+	for name, i := range index {
+		picked[name] = value.Field(i).Interface()
+	}
+	return picked
+}
+
+// writeCSV streams a struct, or a slice of structs, as CSV with one column per
+// JSON field (or per selected field), flushing as rows are written
+func writeCSV(c *gin.Context, status int, data interface{}, fields []string) error {
+	value := reflect.ValueOf(data)
+	if value.Kind() != reflect.Slice {
+		slice := reflect.MakeSlice(reflect.SliceOf(value.Type()), 0, 1)
+		value = reflect.Append(slice, value)
+	}
+
+	elemType := value.Type().Elem()
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if len(fields) == 0 {
+		fields = jsonFieldNames(elemType)
+	}
+	index, err := fieldIndex(elemType, fields)
+	if err != nil {
+		return err
+	}
+
+	c.Header("Content-Type", mimeCSV+"; charset=utf-8")
+	c.Status(status)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write(fields)
+	row := make([]string, len(fields))
+	for i := 0; i < value.Len(); i++ {
+		item := reflect.Indirect(value.Index(i))
+		for j, field := range fields {
+			row[j] = csvValue(item.Field(index[field]))
+		}
+		w.Write(row)
+		if i%100 == 99 {
+			w.Flush()
+			c.Writer.Flush()
+		}
+	}
+	w.Flush()
+	return nil
+}
+
+// jsonFieldNames lists a struct's JSON field names in declaration order
+func jsonFieldNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func csvValue(value reflect.Value) string {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+
+	switch v := value.Interface().(type) {
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case []string:
+		return strings.Join(v, ";")
+	case string:
+		return v
+	case bool, int, float64:
+		return fmt.Sprint(v)
+	}
+
+	encoded, _ := json.Marshal(value.Interface())
+	return string(encoded)
+}
+
+// applyTimedMeetingStatus replaces each meeting's status with the one its
+// times imply when userID has turned on autoMeetingStatus. Only the response
+// changes; the stored status stays as it was.
+func applyTimedMeetingStatus(store services.Store, userID string, now time.Time, meetings ...*models.Meeting) {
+	if len(meetings) == 0 {
+		return
+	}
+	if profile, err := store.GetUser(userID); err != nil || !profile.AutoMeetingStatus {
+		return
+	}
+	for _, meeting := range meetings {
+		meeting.Status = meeting.TimedStatus(now)
+	}
+}
+
+// requestLocation picks the ?tz= override, then userID's stored time zone,
+// then UTC
+func requestLocation(c *gin.Context, store services.Store, userID string) (*time.Location, error) {
+	if override := c.Query("tz"); override != "" {
+		return time.LoadLocation(override)
+	}
+	return profileLocation(store, userID), nil
+}
+
+// profileLocation is userID's stored time zone, or UTC
+func profileLocation(store services.Store, userID string) *time.Location {
+	if profile, err := store.GetUser(userID); err == nil && profile.Timezone != "" {
+		if loc, err := time.LoadLocation(profile.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// isOverdue reports whether task is overdue: unfinished, and its due day in
+// loc, pushed back by the grace period, ended before today began. Every
+// overdue task list and count uses this definition.
+func isOverdue(task *models.Task, now time.Time, loc *time.Location, grace time.Duration) bool {
+	if task.Status == "completed" || task.DueDate == nil {
+		return false
+	}
+	return task.DueDate.Before(overdueTasksBefore(now, loc, grace))
+}
+
+// overdueTasksBefore is the instant isOverdue compares due dates with, for
+// queries that filter on it directly
+func overdueTasksBefore(now time.Time, loc *time.Location, grace time.Duration) time.Time {
+	now = now.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	return today.Add(-grace)
+}
+
+// storeContext is the context handlers bind their store to. It carries the
+// request's values, such as the Server-Timing recorder, but not its
+// cancellation: a write that has started should finish even if the client
+// hangs up, as it did before stores were bound to requests.
+func storeContext(c *gin.Context) context.Context {
+	return context.WithoutCancel(c.Request.Context())
+}
+
+// requestStore is embedded by the handlers to hold their store and bind it to
+// each request
+type requestStore struct {
+	firebaseService services.Store
+}
+
+// store returns the handler's store bound to the request, so its Firestore
+// calls show up in Server-Timing
+func (r requestStore) store(c *gin.Context) services.Store {
+	return r.firebaseService.WithContext(storeContext(c))
+}
\ No newline at end of file
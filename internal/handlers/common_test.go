@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"focusflow-be/internal/models"
+	"focusflow-be/internal/services"
+)
+
+func TestParseRelativeDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"36h", 36 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"1d", 24 * time.Hour, false},
+		{"3d", 72 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"0d", 0, true},
+		{"0s", 0, true},
+		{"-1d", 0, true},
+		{"-2h", 0, true},
+		{"1.5d", 0, true},
+		{"d", 0, true},
+		{"3", 0, true},
+		{"soon", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseRelativeDuration(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("duration = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsOverdue(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	newYork, _ := time.LoadLocation("America/New_York")
+	at := func(day, hour, min, sec int) *time.Time {
+		due := time.Date(2026, 10, day, hour, min, sec, 0, time.UTC)
+		return &due
+	}
+
+	tests := []struct {
+		name  string
+		task  models.Task
+		loc   *time.Location
+		grace time.Duration
+		want  bool
+	}{
+		// With two hours of grace yesterday's tasks are overdue from 22:00 on
+		{"just outside grace", models.Task{Status: "todo", DueDate: at(15, 21, 59, 59)}, time.UTC, 2 * time.Hour, true},
+		{"just inside grace", models.Task{Status: "todo", DueDate: at(15, 22, 0, 0)}, time.UTC, 2 * time.Hour, false},
+		{"end of yesterday without grace", models.Task{Status: "todo", DueDate: at(15, 23, 59, 59)}, time.UTC, 0, true},
+		{"earlier today", models.Task{Status: "in-progress", DueDate: at(16, 1, 0, 0)}, time.UTC, 0, false},
+		{"completed", models.Task{Status: "completed", DueDate: at(1, 9, 0, 0)}, time.UTC, 0, false},
+		{"no due date", models.Task{Status: "todo"}, time.UTC, 0, false},
+		// Today began at 04:00 UTC in New York, so grace ends at 02:00 UTC
+		{"just outside grace in New York", models.Task{Status: "todo", DueDate: at(16, 1, 59, 59)}, newYork, 2 * time.Hour, true},
+		{"just inside grace in New York", models.Task{Status: "todo", DueDate: at(16, 2, 0, 0)}, newYork, 2 * time.Hour, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isOverdue(&tt.task, now, tt.loc, tt.grace); got != tt.want {
+				t.Errorf("isOverdue = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClearFields(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	start := now.Add(24 * time.Hour)
+
+	newStore := func() *mockStore {
+		store := newMockStore()
+		store.tasks["t1"] = &models.Task{ID: "t1", UserID: "user-1", Title: "Write report", Status: "todo"}
+		store.meetings["m1"] = &models.Meeting{ID: "m1", UserID: "user-1", Title: "Standup", StartTime: start, EndTime: start.Add(time.Hour), Status: "scheduled"}
+		store.reminders["r1"] = &models.Reminder{ID: "r1", UserID: "user-1", Title: "Review", ReminderTime: start}
+		return store
+	}
+	tasks := func(store *mockStore) gin.HandlerFunc {
+		return NewTaskHandler(store, nil, nil, testConfig(now)).UpdateTask
+	}
+	meetings := func(store *mockStore) gin.HandlerFunc {
+		return NewMeetingHandler(store, nil, nil, testConfig(now)).UpdateMeeting
+	}
+	reminders := func(store *mockStore) gin.HandlerFunc {
+		return NewReminderHandler(store, nil, nil, testConfig(now)).UpdateReminder
+	}
+
+	tests := []struct {
+		name     string
+		handler  func(*mockStore) gin.HandlerFunc
+		route    string
+		path     string
+		body     string
+		field    string
+		want     interface{} // value written for field
+		wantCode int
+	}{
+		{"set a task due date", tasks, "/tasks/:id", "/tasks/t1?force=true", `{"dueDate": "2026-10-20T17:00:00Z"}`, "dueDate", time.Date(2026, 10, 20, 17, 0, 0, 0, time.UTC), http.StatusOK},
+		{"clear a task due date", tasks, "/tasks/:id", "/tasks/t1?force=true", `{"clearFields": ["dueDate", "description"]}`, "dueDate", services.DeleteField, http.StatusOK},
+		{"set and clear a task field", tasks, "/tasks/:id", "/tasks/t1?force=true", `{"description": "Draft", "clearFields": ["description"]}`, "", nil, http.StatusBadRequest},
+		{"clear a task's title", tasks, "/tasks/:id", "/tasks/t1?force=true", `{"clearFields": ["title"]}`, "", nil, http.StatusBadRequest},
+		{"set a meeting location", meetings, "/meetings/:id", "/meetings/m1", `{"location": "Room 4"}`, "location", "Room 4", http.StatusOK},
+		{"clear a meeting location", meetings, "/meetings/:id", "/meetings/m1", `{"clearFields": ["location"]}`, "location", services.DeleteField, http.StatusOK},
+		{"set and clear a meeting field", meetings, "/meetings/:id", "/meetings/m1", `{"location": "Room 4", "clearFields": ["location"]}`, "", nil, http.StatusBadRequest},
+		{"set a reminder description", reminders, "/reminders/:id", "/reminders/r1", `{"description": "Bring slides"}`, "description", "Bring slides", http.StatusOK},
+		{"clear a reminder description", reminders, "/reminders/:id", "/reminders/r1", `{"clearFields": ["description"]}`, "description", services.DeleteField, http.StatusOK},
+		{"clear a reminder's time", reminders, "/reminders/:id", "/reminders/r1", `{"clearFields": ["reminderTime"]}`, "", nil, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newStore()
+			w := serve(tt.handler(store), http.MethodPut, tt.route, tt.path, tt.body, "user-1")
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				if len(store.updates) != 0 {
+					t.Errorf("updates written: %v", store.updates)
+				}
+				return
+			}
+			if len(store.updates) != 1 {
+				t.Fatalf("%d updates written, want 1", len(store.updates))
+			}
+			got := store.updates[0][tt.field]
+			if wantTime, ok := tt.want.(time.Time); ok {
+				if gotTime, _ := got.(time.Time); !gotTime.Equal(wantTime) {
+					t.Errorf("%s = %v, want %v", tt.field, got, tt.want)
+				}
+			} else if got != tt.want {
+				t.Errorf("%s = %v, want %v", tt.field, got, tt.want)
+			}
+		})
+	}
+}
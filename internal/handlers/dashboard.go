@@ -1,24 +1,34 @@
 package handlers
 
 import (
+	"fmt"
+	"math"
 	"net/http"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"focusflow-be/internal/config"
+	"focusflow-be/internal/locale"
 	"focusflow-be/internal/models"
 	"focusflow-be/internal/services"
 )
 
 type DashboardHandler struct {
-	firebaseService *services.FirebaseService
-	authService     *services.AuthService
+	requestStore
+	authService *services.AuthService
+	config      *config.Config
+	overviews   *overviewCache
 }
 
-func NewDashboardHandler(firebaseService *services.FirebaseService, authService *services.AuthService) *DashboardHandler {
+func NewDashboardHandler(firebaseService services.Store, authService *services.AuthService, cfg *config.Config) *DashboardHandler {
 	return &DashboardHandler{
-		firebaseService: firebaseService,
-		authService:     authService,
+		requestStore: requestStore{firebaseService},
+		authService:  authService,
+		config:       cfg,
+		overviews:    newOverviewCache(cfg.OverviewCacheTTL, cfg.Clock),
 	}
 }
 
@@ -32,22 +42,37 @@ func (h *DashboardHandler) GetCalendarEvents(c *gin.Context) {
 	userSession := user.(*models.UserSession)
 
 	var events []models.CalendarEvent
+	var failed fetchErrors
+
+	// Finished items are shown unless the caller opts out
+	includeCompleted := c.DefaultQuery("includeCompleted", "true") != "false"
 
 	// Get tasks
-	tasks, err := h.firebaseService.GetTasks(userSession.UserID)
-	if err == nil {
+	tasks, err := h.store(c).GetTasks(userSession.UserID)
+	if err != nil {
+		failed.add("tasks", err)
+	} else {
 		for _, task := range tasks {
+			if !includeCompleted && task.Status == "completed" {
+				continue
+			}
 			if task.DueDate != nil {
-				startTime := time.Now()
+				startTime := h.config.Clock.Now()
 				if task.StartDate != nil {
 					startTime = *task.StartDate
 				}
 
 				color := "#10b981" // green for low
-				if task.Priority == "medium" {
+				switch task.Priority {
+				case "medium":
 					color = "#f59e0b" // yellow
-				} else if task.Priority == "high" {
+				case "high":
 					color = "#ef4444" // red
+				case "urgent":
+					color = "#991b1b" // dark red
+				}
+				if task.Blocked {
+					color = "#6b7280" // gray
 				}
 
 				events = append(events, models.CalendarEvent{
@@ -59,16 +84,23 @@ func (h *DashboardHandler) GetCalendarEvents(c *gin.Context) {
 					Status:      task.Status,
 					Color:       &color,
 					Description: task.Description,
+					Blocked:     task.Blocked,
 				})
 			}
 		}
 	}
 
 	// Get meetings
-	meetings, err := h.firebaseService.GetMeetings(userSession.UserID)
-	if err == nil {
+	meetings, err := h.store(c).GetMeetings(userSession.UserID)
+	if err != nil {
+		failed.add("meetings", err)
+	} else {
+		applyTimedMeetingStatus(h.store(c), userSession.UserID, h.config.Clock.Now(), meetings...)
 		for _, meeting := range meetings {
-			color := "#3b82f6" // blue
+			if !includeCompleted && (meeting.Status == "completed" || meeting.Status == "cancelled") {
+				continue
+			}
+			color := h.config.MeetingColor
 			events = append(events, models.CalendarEvent{
 				ID:          meeting.ID,
 				Title:       meeting.Title,
@@ -78,15 +110,21 @@ func (h *DashboardHandler) GetCalendarEvents(c *gin.Context) {
 				Status:      meeting.Status,
 				Color:       &color,
 				Description: meeting.Description,
+				Tentative:   meeting.Tentative,
 			})
 		}
 	}
 
 	// Get reminders
-	reminders, err := h.firebaseService.GetReminders(userSession.UserID)
-	if err == nil {
+	reminders, err := h.store(c).GetReminders(userSession.UserID)
+	if err != nil {
+		failed.add("reminders", err)
+	} else {
 		for _, reminder := range reminders {
-			color := "#8b5cf6" // purple
+			if !includeCompleted && reminder.IsCompleted {
+				continue
+			}
+			color := h.config.ReminderColor
 			status := "pending"
 			if reminder.IsCompleted {
 				status = "completed"
@@ -105,9 +143,40 @@ func (h *DashboardHandler) GetCalendarEvents(c *gin.Context) {
 		}
 	}
 
+	if failed.rejectIfStrict(c) {
+		return
+	}
+
+	// Add localized display times alongside the machine-readable fields
+	if c.Query("humanize") == "true" {
+		userLocale := h.userLocale(c, userSession.UserID)
+		for i := range events {
+			if start, err := time.Parse(time.RFC3339, events[i].Start); err == nil {
+				displayStart := locale.FormatDateTime(start, userLocale)
+				events[i].DisplayStart = &displayStart
+			}
+			if end, err := time.Parse(time.RFC3339, events[i].End); err == nil {
+				displayEnd := locale.FormatDateTime(end, userLocale)
+				events[i].DisplayEnd = &displayEnd
+			}
+		}
+	}
+
+	failed.setHeader(c)
 	c.JSON(http.StatusOK, events)
 }
 
+// userLocale picks the ?locale= override, then the stored preference, then the default
+func (h *DashboardHandler) userLocale(c *gin.Context, userID string) string {
+	if override := c.Query("locale"); override != "" {
+		return locale.Normalize(override)
+	}
+	if profile, err := h.store(c).GetUser(userID); err == nil {
+		return locale.Normalize(profile.Locale)
+	}
+	return locale.Default
+}
+
 func (h *DashboardHandler) GetGanttData(c *gin.Context) {
 	user, exists := c.Get("user")
 	if !exists {
@@ -118,10 +187,13 @@ func (h *DashboardHandler) GetGanttData(c *gin.Context) {
 	userSession := user.(*models.UserSession)
 
 	var ganttItems []models.GanttItem
+	var failed fetchErrors
 
 	// Get tasks with start and end dates
-	tasks, err := h.firebaseService.GetTasks(userSession.UserID)
-	if err == nil {
+	tasks, err := h.store(c).GetTasks(userSession.UserID)
+	if err != nil {
+		failed.add("tasks", err)
+	} else {
 		for _, task := range tasks {
 			if task.StartDate != nil && task.DueDate != nil {
 				progress := 0
@@ -140,14 +212,18 @@ func (h *DashboardHandler) GetGanttData(c *gin.Context) {
 					Type:     "task",
 					Status:   task.Status,
 					Priority: task.Priority,
+					Blocked:  task.Blocked,
 				})
 			}
 		}
 	}
 
 	// Get meetings
-	meetings, err := h.firebaseService.GetMeetings(userSession.UserID)
-	if err == nil {
+	meetings, err := h.store(c).GetMeetings(userSession.UserID)
+	if err != nil {
+		failed.add("meetings", err)
+	} else {
+		applyTimedMeetingStatus(h.store(c), userSession.UserID, h.config.Clock.Now(), meetings...)
 		for _, meeting := range meetings {
 			progress := 0
 			if meeting.Status == "completed" {
@@ -169,6 +245,11 @@ func (h *DashboardHandler) GetGanttData(c *gin.Context) {
 		}
 	}
 
+	if failed.rejectIfStrict(c) {
+		return
+	}
+
+	failed.setHeader(c)
 	c.JSON(http.StatusOK, ganttItems)
 }
 
@@ -181,12 +262,43 @@ func (h *DashboardHandler) GetOverview(c *gin.Context) {
 
 	userSession := user.(*models.UserSession)
 
-	overview := models.Overview{}
-	today := time.Now().Format("2006-01-02")
+	if c.Query("fresh") != "true" {
+		if overview, ok := h.overviews.get(userSession.UserID); ok {
+			c.JSON(http.StatusOK, overview)
+			return
+		}
+	}
+
+	overview, failed := h.computeOverview(c, userSession.UserID)
+	if failed.rejectIfStrict(c) {
+		return
+	}
+
+	// A partial overview is still served, but not kept
+	if len(failed) > 0 {
+		overview.Partial = true
+		overview.Errors = failed
+	} else {
+		h.overviews.put(userSession.UserID, overview)
+	}
+
+	c.JSON(http.StatusOK, overview)
+}
+
+// computeOverview scans the user's items for the overview counts. The counts
+// of a collection that couldn't be read are left at zero and it is listed in
+// failed.
+func (h *DashboardHandler) computeOverview(c *gin.Context, userID string) (overview models.Overview, failed fetchErrors) {
+	now := h.config.Clock.Now()
+	today := now.Format("2006-01-02")
+	// The overview is cached per user, so it ignores ?tz= and uses the profile
+	loc := profileLocation(h.store(c), userID)
 
 	// Get task statistics
-	tasks, err := h.firebaseService.GetTasks(userSession.UserID)
-	if err == nil {
+	tasks, err := h.store(c).GetTasks(userID)
+	if err != nil {
+		failed.add("tasks", err)
+	} else {
 		overview.Tasks.Total = len(tasks)
 		for _, task := range tasks {
 			switch task.Status {
@@ -198,22 +310,25 @@ func (h *DashboardHandler) GetOverview(c *gin.Context) {
 				overview.Tasks.Todo++
 			}
 
-			if task.Priority == "high" {
+			if models.IsHighPriority(task.Priority) {
 				overview.Tasks.HighPriority++
 			}
+			if task.Blocked {
+				overview.Tasks.Blocked++
+			}
 
-			// Check if overdue
-			if task.DueDate != nil && task.Status != "completed" {
-				if task.DueDate.Format("2006-01-02") < today {
-					overview.Tasks.Overdue++
-				}
+			if isOverdue(task, now, loc, h.config.OverdueGrace) {
+				overview.Tasks.Overdue++
 			}
 		}
 	}
 
 	// Get meeting statistics
-	meetings, err := h.firebaseService.GetMeetings(userSession.UserID)
-	if err == nil {
+	meetings, err := h.store(c).GetMeetings(userID)
+	if err != nil {
+		failed.add("meetings", err)
+	} else {
+		applyTimedMeetingStatus(h.store(c), userID, h.config.Clock.Now(), meetings...)
 		overview.Meetings.Total = len(meetings)
 		for _, meeting := range meetings {
 			if meeting.StartTime.Format("2006-01-02") == today {
@@ -226,25 +341,1020 @@ func (h *DashboardHandler) GetOverview(c *gin.Context) {
 			case "completed":
 				overview.Meetings.Completed++
 			}
+
+			if meeting.Attended != nil {
+				if *meeting.Attended {
+					overview.Meetings.Attended++
+				} else {
+					overview.Meetings.Missed++
+				}
+			}
+		}
+
+		if marked := overview.Meetings.Attended + overview.Meetings.Missed; marked > 0 {
+			overview.Meetings.AttendanceRate = float64(overview.Meetings.Attended) / float64(marked)
 		}
 	}
 
 	// Get reminder statistics
-	reminders, err := h.firebaseService.GetReminders(userSession.UserID)
-	if err == nil {
+	reminders, err := h.store(c).GetReminders(userID)
+	if err != nil {
+		failed.add("reminders", err)
+	} else {
 		overview.Reminders.Total = len(reminders)
-		now := time.Now()
 		for _, reminder := range reminders {
 			if reminder.IsCompleted {
 				overview.Reminders.Completed++
 			} else {
 				overview.Reminders.Pending++
-				if reminder.ReminderTime.Before(now) {
+				if reminder.ReminderTime.Add(h.config.OverdueGrace).Before(now) {
 					overview.Reminders.Overdue++
 				}
 			}
 		}
 	}
 
-	c.JSON(http.StatusOK, overview)
+	return overview, failed
+}
+
+// GetBadges returns cheap counts for navigation badges. "Today" is the current
+// day in the ?tz= zone, then the user's stored time zone, then UTC.
+func (h *DashboardHandler) GetBadges(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	loc, err := h.userLocation(c, userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tz parameter, expected an IANA time zone", "details": err.Error()})
+		return
+	}
+
+	now := h.config.Clock.Now().In(loc)
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
+	counts, err := h.store(c).GetBadgeCounts(userSession.UserID, dayStart, dayEnd,
+		overdueTasksBefore(now, loc, h.config.OverdueGrace), now.Add(-h.config.OverdueGrace))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch badge counts", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, counts)
+}
+
+// GetDay returns the tasks due, meetings and reminders on the :date (YYYY-MM-DD)
+// calendar day, with a short summary. The day is taken in the ?tz= zone, then
+// the user's stored time zone, then UTC.
+func (h *DashboardHandler) GetDay(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	loc, err := h.userLocation(c, userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tz parameter, expected an IANA time zone", "details": err.Error()})
+		return
+	}
+
+	dayStart, err := time.ParseInLocation("2006-01-02", c.Param("date"), loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date, expected YYYY-MM-DD", "details": err.Error()})
+		return
+	}
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
+	tasks, err := h.store(c).GetTasksDueBetween(userSession.UserID, dayStart, dayEnd.Add(-time.Nanosecond))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks", "details": err.Error()})
+		return
+	}
+	meetings, err := h.store(c).GetMeetings(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meetings", "details": err.Error()})
+		return
+	}
+	reminders, err := h.store(c).GetReminders(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reminders", "details": err.Error()})
+		return
+	}
+
+	view := models.DayView{
+		Date:      dayStart.Format("2006-01-02"),
+		Timezone:  loc.String(),
+		Tasks:     tasks,
+		Meetings:  []*models.Meeting{},
+		Reminders: []*models.Reminder{},
+	}
+	for _, meeting := range meetings {
+		if meeting.StartTime.Before(dayEnd) && meeting.EndTime.After(dayStart) {
+			view.Meetings = append(view.Meetings, meeting)
+		}
+	}
+	sort.Slice(view.Meetings, func(i, j int) bool {
+		return view.Meetings[i].StartTime.Before(view.Meetings[j].StartTime)
+	})
+	for _, reminder := range reminders {
+		if !reminder.ReminderTime.Before(dayStart) && reminder.ReminderTime.Before(dayEnd) {
+			view.Reminders = append(view.Reminders, reminder)
+		}
+	}
+	sort.Slice(view.Reminders, func(i, j int) bool {
+		return view.Reminders[i].ReminderTime.Before(view.Reminders[j].ReminderTime)
+	})
+
+	view.Summary = summarizeDay(dayStart, view)
+	c.JSON(http.StatusOK, view)
+}
+
+// GetWorkload sums open task estimates (on their due day) and meeting time per
+// day between ?from= and ?to= (YYYY-MM-DD, inclusive; default the next seven
+// days), flagging days over WORKLOAD_DAILY_CAPACITY. Days are taken in the
+// ?tz= zone, then the user's stored time zone, then UTC.
+func (h *DashboardHandler) GetWorkload(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	loc, err := h.userLocation(c, userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tz parameter, expected an IANA time zone", "details": err.Error()})
+		return
+	}
+
+	now := h.config.Clock.Now().In(loc)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if value := c.Query("from"); value != "" {
+		if from, err = time.ParseInLocation("2006-01-02", value, loc); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from parameter, expected YYYY-MM-DD", "details": err.Error()})
+			return
+		}
+	}
+	to := from.AddDate(0, 0, 6)
+	if value := c.Query("to"); value != "" {
+		if to, err = time.ParseInLocation("2006-01-02", value, loc); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to parameter, expected YYYY-MM-DD", "details": err.Error()})
+			return
+		}
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return
+	}
+	if to.After(from.AddDate(0, 0, maxWorkloadDays-1)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Range is limited to %d days", maxWorkloadDays)})
+		return
+	}
+	end := to.AddDate(0, 0, 1)
+
+	tasks, err := h.store(c).GetTasksDueBetween(userSession.UserID, from, end.Add(-time.Nanosecond))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks", "details": err.Error()})
+		return
+	}
+	meetings, err := h.store(c).GetMeetings(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meetings", "details": err.Error()})
+		return
+	}
+
+	days := workloadByDay(from, end, tasks, meetings, h.config.WorkloadDailyCapacity)
+	overCapacity := 0
+	for _, day := range days {
+		if day.OverCapacity {
+			overCapacity++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":             from.Format("2006-01-02"),
+		"to":               to.Format("2006-01-02"),
+		"timezone":         loc.String(),
+		"capacityHours":    h.config.WorkloadDailyCapacity.Hours(),
+		"days":             days,
+		"overCapacityDays": overCapacity,
+	})
+}
+
+const maxWorkloadDays = 92
+
+// workloadByDay buckets work into the calendar days from start up to end.
+// A task's whole estimate lands on its due day; a meeting counts on each day
+// it spans. Completed tasks and cancelled meetings are left out.
+func workloadByDay(start, end time.Time, tasks []*models.Task, meetings []*models.Meeting, capacity time.Duration) []models.WorkloadDay {
+	var days []models.WorkloadDay
+	for dayStart := start; dayStart.Before(end); dayStart = dayStart.AddDate(0, 0, 1) {
+		dayEnd := dayStart.AddDate(0, 0, 1)
+		day := models.WorkloadDay{Date: dayStart.Format("2006-01-02")}
+
+		for _, task := range tasks {
+			if task.Status == "completed" || task.EstimatedHours == nil || task.DueDate == nil {
+				continue
+			}
+			if !task.DueDate.Before(dayStart) && task.DueDate.Before(dayEnd) {
+				day.TaskHours += *task.EstimatedHours
+			}
+		}
+		for _, meeting := range meetings {
+			if meeting.Status == "cancelled" {
+				continue
+			}
+			day.MeetingHours += clampedDuration(meeting.StartTime, meeting.EndTime, dayStart, dayEnd).Hours()
+		}
+
+		day.TotalHours = day.TaskHours + day.MeetingHours
+		day.OverCapacity = capacity > 0 && day.TotalHours > capacity.Hours()
+		days = append(days, day)
+	}
+	return days
+}
+
+// summarizeDay counts a day's items and works out how much of the working day
+// is spent in meetings. Overlapping meetings are only counted once towards
+// free time.
+func summarizeDay(dayStart time.Time, view models.DayView) models.DaySummary {
+	summary := models.DaySummary{
+		Tasks:     len(view.Tasks),
+		Meetings:  len(view.Meetings),
+		Reminders: len(view.Reminders),
+	}
+
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	workStart := time.Date(dayStart.Year(), dayStart.Month(), dayStart.Day(), workdayStartHour, 0, 0, 0, dayStart.Location())
+	workEnd := time.Date(dayStart.Year(), dayStart.Month(), dayStart.Day(), workdayEndHour, 0, 0, 0, dayStart.Location())
+
+	var busy time.Duration
+	cursor := workStart
+	// view.Meetings is sorted by start time
+	for _, meeting := range view.Meetings {
+		if meeting.Status == "cancelled" {
+			continue
+		}
+		summary.MeetingMinutes += int(clampedDuration(meeting.StartTime, meeting.EndTime, dayStart, dayEnd).Minutes())
+
+		start, end := meeting.StartTime, meeting.EndTime
+		if start.Before(cursor) {
+			start = cursor
+		}
+		if end.After(workEnd) {
+			end = workEnd
+		}
+		if end.After(start) {
+			busy += end.Sub(start)
+			cursor = end
+		}
+	}
+
+	summary.FreeMinutes = int((workEnd.Sub(workStart) - busy).Minutes())
+	return summary
+}
+
+// GetAvailability splits a day's working hours into ?slotMinutes= slots
+// (default 30) marked busy or free, along with the merged busy ranges and the
+// gaps between them. Meetings, including their buffers, count as busy, as do
+// open tasks with both a start and a due time unless ?includeTasks=false.
+// ?date= (YYYY-MM-DD, default today) is taken in ?tz=, then the profile time
+// zone, then UTC; ?workStart= and ?workEnd= (HH:MM) override the 08:00-18:00
+// working day.
+func (h *DashboardHandler) GetAvailability(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	loc, err := h.userLocation(c, userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tz parameter, expected an IANA time zone", "details": err.Error()})
+		return
+	}
+
+	now := h.config.Clock.Now().In(loc)
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if value := c.Query("date"); value != "" {
+		if dayStart, err = time.ParseInLocation("2006-01-02", value, loc); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date parameter, expected YYYY-MM-DD", "details": err.Error()})
+			return
+		}
+	}
+
+	slotMinutes, err := strconv.Atoi(c.DefaultQuery("slotMinutes", "30"))
+	if err != nil || slotMinutes < 5 || slotMinutes > 240 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "slotMinutes must be between 5 and 240"})
+		return
+	}
+
+	workStart, err := timeOfDay(dayStart, c.Query("workStart"), workdayStartHour)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workStart parameter, expected HH:MM", "details": err.Error()})
+		return
+	}
+	workEnd, err := timeOfDay(dayStart, c.Query("workEnd"), workdayEndHour)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workEnd parameter, expected HH:MM", "details": err.Error()})
+		return
+	}
+	if !workEnd.After(workStart) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "workEnd must be after workStart"})
+		return
+	}
+
+	meetings, err := h.store(c).GetMeetings(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meetings", "details": err.Error()})
+		return
+	}
+
+	includeTentative := h.includeTentative(c)
+	var busy []models.TimeRange
+	for _, meeting := range meetings {
+		if meeting.Status == "cancelled" || (meeting.Tentative && !includeTentative) {
+			continue
+		}
+		start, end := meeting.BlockedWindow()
+		busy = append(busy, models.TimeRange{Start: start, End: end})
+	}
+
+	if c.Query("includeTasks") != "false" {
+		tasks, err := h.store(c).GetTasks(userSession.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks", "details": err.Error()})
+			return
+		}
+		for _, task := range tasks {
+			if task.Status != "completed" && task.StartDate != nil && task.DueDate != nil {
+				busy = append(busy, models.TimeRange{Start: *task.StartDate, End: *task.DueDate})
+			}
+		}
+	}
+
+	availability := computeAvailability(workStart, workEnd, busy, time.Duration(slotMinutes)*time.Minute)
+	availability.Date = dayStart.Format("2006-01-02")
+	availability.Timezone = loc.String()
+	availability.SlotMinutes = slotMinutes
+	c.JSON(http.StatusOK, availability)
+}
+
+// timeOfDay returns value (HH:MM) on day, or fallbackHour:00 when value is empty
+func timeOfDay(day time.Time, value string, fallbackHour int) (time.Time, error) {
+	if value == "" {
+		return time.Date(day.Year(), day.Month(), day.Day(), fallbackHour, 0, 0, 0, day.Location()), nil
+	}
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, day.Location()), nil
+}
+
+// computeAvailability clips the busy ranges to [workStart, workEnd), merges
+// the ones that overlap or touch, and marks each slot of length slot busy if
+// it overlaps a merged range. A final slot cut short by workEnd is kept.
+func computeAvailability(workStart, workEnd time.Time, busy []models.TimeRange, slot time.Duration) models.Availability {
+	loc := workStart.Location()
+	availability := models.Availability{
+		WorkStart: workStart,
+		WorkEnd:   workEnd,
+		Slots:     []models.AvailabilitySlot{},
+		Busy:      []models.TimeRange{},
+		Free:      []models.TimeRange{},
+	}
+
+	var clipped []models.TimeRange
+	for _, r := range busy {
+		start, end := r.Start, r.End
+		if start.Before(workStart) {
+			start = workStart
+		}
+		if end.After(workEnd) {
+			end = workEnd
+		}
+		if end.After(start) {
+			clipped = append(clipped, models.TimeRange{Start: start.In(loc), End: end.In(loc)})
+		}
+	}
+	sort.Slice(clipped, func(i, j int) bool {
+		return clipped[i].Start.Before(clipped[j].Start)
+	})
+	for _, r := range clipped {
+		if last := len(availability.Busy) - 1; last >= 0 && !r.Start.After(availability.Busy[last].End) {
+			if r.End.After(availability.Busy[last].End) {
+				availability.Busy[last].End = r.End
+			}
+			continue
+		}
+		availability.Busy = append(availability.Busy, r)
+	}
+
+	cursor := workStart
+	for _, r := range availability.Busy {
+		if r.Start.After(cursor) {
+			availability.Free = append(availability.Free, models.TimeRange{Start: cursor, End: r.Start})
+		}
+		availability.BusyMinutes += int(r.End.Sub(r.Start).Minutes())
+		cursor = r.End
+	}
+	if workEnd.After(cursor) {
+		availability.Free = append(availability.Free, models.TimeRange{Start: cursor, End: workEnd})
+	}
+	for i, r := range availability.Free {
+		availability.FreeMinutes += int(r.End.Sub(r.Start).Minutes())
+		if availability.LongestFree == nil || r.End.Sub(r.Start) > availability.LongestFree.End.Sub(availability.LongestFree.Start) {
+			availability.LongestFree = &availability.Free[i]
+		}
+	}
+
+	next := 0 // first merged range that may still overlap a slot
+	for start := workStart; start.Before(workEnd); start = start.Add(slot) {
+		end := start.Add(slot)
+		if end.After(workEnd) {
+			end = workEnd
+		}
+		for next < len(availability.Busy) && !availability.Busy[next].End.After(start) {
+			next++
+		}
+		isBusy := next < len(availability.Busy) && availability.Busy[next].Start.Before(end)
+		availability.Slots = append(availability.Slots, models.AvailabilitySlot{Start: start, End: end, Busy: isBusy})
+	}
+	return availability
+}
+
+// GetConflicts lists pairs of upcoming meetings that overlap, buffers
+// included, so double-bookings show up before the day arrives. Open tasks
+// with a start and due time are checked against meetings too unless
+// ?includeTasks=false. ?days= sets how far ahead to look (default 30).
+func (h *DashboardHandler) GetConflicts(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > 365 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 365"})
+		return
+	}
+	from := h.config.Clock.Now()
+	to := from.AddDate(0, 0, days)
+
+	meetings, err := h.store(c).GetMeetings(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meetings", "details": err.Error()})
+		return
+	}
+
+	includeTentative := h.includeTentative(c)
+	var items []models.ConflictItem
+	for _, meeting := range meetings {
+		if meeting.Status == "cancelled" || meeting.Status == "completed" || (meeting.Tentative && !includeTentative) {
+			continue
+		}
+		start, end := meeting.BlockedWindow()
+		items = append(items, models.ConflictItem{ID: meeting.ID, Type: "meeting", Title: meeting.Title, Start: start, End: end, Tentative: meeting.Tentative})
+	}
+
+	if c.Query("includeTasks") != "false" {
+		tasks, err := h.store(c).GetTasks(userSession.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks", "details": err.Error()})
+			return
+		}
+		for _, task := range tasks {
+			if task.Status != "completed" && task.StartDate != nil && task.DueDate != nil {
+				items = append(items, models.ConflictItem{ID: task.ID, Type: "task", Title: task.Title, Start: *task.StartDate, End: *task.DueDate})
+			}
+		}
+	}
+
+	inRange := items[:0]
+	for _, item := range items {
+		if item.Start.Before(to) && from.Before(item.End) {
+			inRange = append(inRange, item)
+		}
+	}
+
+	conflicts := findConflicts(inRange)
+	c.JSON(http.StatusOK, gin.H{
+		"from":      from,
+		"to":        to,
+		"count":     len(conflicts),
+		"conflicts": conflicts,
+	})
+}
+
+// includeTentative reads ?includeTentative=true|false, falling back to
+// CONFLICTS_INCLUDE_TENTATIVE
+func (h *DashboardHandler) includeTentative(c *gin.Context) bool {
+	switch c.Query("includeTentative") {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return h.config.ConflictsIncludeTentative
+}
+
+// findConflicts pairs up overlapping items with a sweep over their start
+// times, so only items that are still running get compared. Items that merely
+// touch don't conflict. Two tasks never conflict with each other: tasks often
+// span days and are worked on side by side.
+func findConflicts(items []models.ConflictItem) []models.Conflict {
+	sorted := append([]models.ConflictItem(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	conflicts := []models.Conflict{}
+	var active []models.ConflictItem // started before the current item and not yet ended
+	for _, item := range sorted {
+		if !item.End.After(item.Start) {
+			continue
+		}
+
+		running := active[:0]
+		for _, other := range active {
+			if other.End.After(item.Start) {
+				running = append(running, other)
+			}
+		}
+		active = running
+
+		for _, other := range active {
+			if other.Type == "task" && item.Type == "task" {
+				continue
+			}
+			overlapEnd := other.End
+			if item.End.Before(overlapEnd) {
+				overlapEnd = item.End
+			}
+			conflicts = append(conflicts, models.Conflict{
+				First:          other,
+				Second:         item,
+				OverlapStart:   item.Start,
+				OverlapEnd:     overlapEnd,
+				OverlapMinutes: int(overlapEnd.Sub(item.Start).Minutes()),
+			})
+		}
+		active = append(active, item)
+	}
+	return conflicts
+}
+
+// GetSchedule lays out the week containing ?week= (YYYY-MM-DD, default this
+// week) as a Monday to Sunday grid of ?slotMinutes= slots (default 60) across
+// the working hours, for a printable planner. Meetings and tasks with a start
+// and due time fill the slots; tasks with only one date go in that day's
+// anytime list. Days are taken in ?tz=, then the profile time zone.
+func (h *DashboardHandler) GetSchedule(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	loc, err := h.userLocation(c, userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tz parameter, expected an IANA time zone", "details": err.Error()})
+		return
+	}
+
+	now := h.config.Clock.Now().In(loc)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if value := c.Query("week"); value != "" {
+		if day, err = time.ParseInLocation("2006-01-02", value, loc); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid week parameter, expected YYYY-MM-DD", "details": err.Error()})
+			return
+		}
+	}
+	weekStart := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+
+	slotMinutes, err := strconv.Atoi(c.DefaultQuery("slotMinutes", "60"))
+	if err != nil || slotMinutes < 15 || slotMinutes > 240 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "slotMinutes must be between 15 and 240"})
+		return
+	}
+
+	workStart, err := timeOfDay(weekStart, c.Query("workStart"), workdayStartHour)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workStart parameter, expected HH:MM", "details": err.Error()})
+		return
+	}
+	workEnd, err := timeOfDay(weekStart, c.Query("workEnd"), workdayEndHour)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workEnd parameter, expected HH:MM", "details": err.Error()})
+		return
+	}
+	if !workEnd.After(workStart) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "workEnd must be after workStart"})
+		return
+	}
+
+	meetings, err := h.store(c).GetMeetings(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meetings", "details": err.Error()})
+		return
+	}
+	tasks, err := h.store(c).GetTasks(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks", "details": err.Error()})
+		return
+	}
+
+	applyTimedMeetingStatus(h.store(c), userSession.UserID, h.config.Clock.Now(), meetings...)
+	var items []models.ScheduleItem
+	for _, meeting := range meetings {
+		if meeting.Status == "cancelled" {
+			continue
+		}
+		start, end := meeting.StartTime, meeting.EndTime
+		items = append(items, models.ScheduleItem{ID: meeting.ID, Type: "meeting", Title: meeting.Title, Status: meeting.Status, Start: &start, End: &end, Tentative: meeting.Tentative})
+	}
+	for _, task := range tasks {
+		if task.Status == "completed" {
+			continue
+		}
+		item := models.ScheduleItem{ID: task.ID, Type: "task", Title: task.Title, Status: task.Status, Priority: task.Priority}
+		switch {
+		case task.StartDate != nil && task.DueDate != nil:
+			item.Start, item.End = task.StartDate, task.DueDate
+		case task.DueDate != nil:
+			item.Due = task.DueDate
+		case task.StartDate != nil:
+			item.Due = task.StartDate
+		default:
+			continue
+		}
+		items = append(items, item)
+	}
+
+	schedule := models.WeekSchedule{
+		WeekStart:   weekStart.Format("2006-01-02"),
+		Timezone:    loc.String(),
+		WorkStart:   workStart.Format("15:04"),
+		WorkEnd:     workEnd.Format("15:04"),
+		SlotMinutes: slotMinutes,
+		Days:        buildWeekSchedule(weekStart, workStart.Format("15:04"), workEnd.Format("15:04"), time.Duration(slotMinutes)*time.Minute, items),
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// buildWeekSchedule places items on the seven days from weekStart. Working
+// hours are given as HH:MM and applied to each day separately, so they keep
+// their wall-clock times across a DST change. A timed item is listed in every
+// slot it overlaps, and in a day's outsideHours when it falls on that day but
+// misses the working hours.
+func buildWeekSchedule(weekStart time.Time, workStartText, workEndText string, slot time.Duration, items []models.ScheduleItem) []models.ScheduleDay {
+	loc := weekStart.Location()
+	days := make([]models.ScheduleDay, 0, 7)
+	for i := 0; i < 7; i++ {
+		dayStart := weekStart.AddDate(0, 0, i)
+		dayEnd := dayStart.AddDate(0, 0, 1)
+		workStart, _ := timeOfDay(dayStart, workStartText, workdayStartHour)
+		workEnd, _ := timeOfDay(dayStart, workEndText, workdayEndHour)
+
+		day := models.ScheduleDay{
+			Date:         dayStart.Format("2006-01-02"),
+			Weekday:      dayStart.Weekday().String(),
+			Slots:        []models.ScheduleSlot{},
+			Anytime:      []models.ScheduleItem{},
+			OutsideHours: []models.ScheduleItem{},
+		}
+		for start := workStart; start.Before(workEnd); start = start.Add(slot) {
+			end := start.Add(slot)
+			if end.After(workEnd) {
+				end = workEnd
+			}
+			day.Slots = append(day.Slots, models.ScheduleSlot{Start: start, End: end, Items: []models.ScheduleItem{}})
+		}
+
+		for _, item := range items {
+			if item.Start == nil {
+				if due := item.Due.In(loc); !due.Before(dayStart) && due.Before(dayEnd) {
+					day.Anytime = append(day.Anytime, item)
+				}
+				continue
+			}
+			if !item.Start.Before(dayEnd) || !item.End.After(dayStart) {
+				continue
+			}
+			placed := false
+			for j := range day.Slots {
+				if item.Start.Before(day.Slots[j].End) && item.End.After(day.Slots[j].Start) {
+					day.Slots[j].Items = append(day.Slots[j].Items, item)
+					placed = true
+				}
+			}
+			if !placed {
+				day.OutsideHours = append(day.OutsideHours, item)
+			}
+		}
+
+		for j := range day.Slots {
+			sortScheduleItems(day.Slots[j].Items)
+		}
+		sortScheduleItems(day.Anytime)
+		sortScheduleItems(day.OutsideHours)
+		days = append(days, day)
+	}
+	return days
+}
+
+// sortScheduleItems orders timed items by start and anytime ones by date
+func sortScheduleItems(items []models.ScheduleItem) {
+	at := func(item models.ScheduleItem) time.Time {
+		if item.Start != nil {
+			return *item.Start
+		}
+		return *item.Due
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return at(items[i]).Before(at(items[j]))
+	})
+}
+
+// clampedDuration is how much of [start, end) falls inside [from, to)
+func clampedDuration(start, end, from, to time.Time) time.Duration {
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	if end.Before(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// GetOverdue lists everything that needs attention, most overdue first:
+// unfinished tasks due before today, pending reminders whose time has passed
+// and meetings still scheduled after their start. OVERDUE_GRACE applies to
+// all three. "Today" is taken in ?tz=, then the profile time zone, then UTC.
+func (h *DashboardHandler) GetOverdue(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	loc, err := h.userLocation(c, userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tz parameter, expected an IANA time zone", "details": err.Error()})
+		return
+	}
+
+	now := h.config.Clock.Now()
+	cutoff := now.Add(-h.config.OverdueGrace)
+
+	items := []models.OverdueItem{}
+	add := func(kind, id, title, priority string, due time.Time) {
+		items = append(items, models.OverdueItem{
+			Type:           kind,
+			ID:             id,
+			Title:          title,
+			Priority:       priority,
+			DueAt:          due,
+			OverdueMinutes: int(now.Sub(due).Minutes()),
+		})
+	}
+
+	tasks, err := h.store(c).GetTasks(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks", "details": err.Error()})
+		return
+	}
+	for _, task := range tasks {
+		if isOverdue(task, now, loc, h.config.OverdueGrace) {
+			add("task", task.ID, task.Title, task.Priority, *task.DueDate)
+		}
+	}
+
+	reminders, err := h.store(c).GetReminders(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reminders", "details": err.Error()})
+		return
+	}
+	for _, reminder := range reminders {
+		if !reminder.IsCompleted && reminder.ReminderTime.Before(cutoff) {
+			add("reminder", reminder.ID, reminder.Title, reminder.Priority, reminder.ReminderTime)
+		}
+	}
+
+	meetings, err := h.store(c).GetMeetings(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meetings", "details": err.Error()})
+		return
+	}
+	for _, meeting := range meetings {
+		if meeting.Status == "scheduled" && meeting.StartTime.Before(cutoff) {
+			add("meeting", meeting.ID, meeting.Title, "", meeting.StartTime)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DueAt.Before(items[j].DueAt)
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":    items,
+		"total":    len(items),
+		"timezone": loc.String(),
+	})
+}
+
+// GetEstimateAccuracy reports how the user's estimates compared with the hours
+// actually spent, over completed tasks that recorded both
+func (h *DashboardHandler) GetEstimateAccuracy(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	tasks, err := h.store(c).GetTasks(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, estimateAccuracy(tasks))
+}
+
+// Ratios within [onTargetMin, onTargetMax) count as on target
+const (
+	onTargetMin = 0.8
+	onTargetMax = 1.2
+)
+
+// estimateBounds are the lower bounds of the distribution buckets
+var estimateBounds = []float64{0, 0.5, onTargetMin, onTargetMax, 2}
+
+// estimateAccuracy computes the actual / estimated ratios. Tasks that aren't
+// completed, or lack either figure, or were estimated at zero hours are left
+// out.
+func estimateAccuracy(tasks []*models.Task) models.EstimateAccuracy {
+	accuracy := models.EstimateAccuracy{Distribution: make([]models.EstimateBucket, len(estimateBounds))}
+	for i, min := range estimateBounds {
+		bucket := &accuracy.Distribution[i]
+		bucket.Min = min
+		if i+1 < len(estimateBounds) {
+			max := estimateBounds[i+1]
+			bucket.Max = &max
+			bucket.Label = fmt.Sprintf("%g-%g", min, max)
+		} else {
+			bucket.Label = fmt.Sprintf("%g+", min)
+		}
+	}
+
+	var ratioSum float64
+	for _, task := range tasks {
+		if task.Status != "completed" || task.EstimatedHours == nil || task.ActualHours == nil || *task.EstimatedHours <= 0 {
+			continue
+		}
+
+		ratio := *task.ActualHours / *task.EstimatedHours
+		accuracy.Tasks++
+		accuracy.EstimatedHours += *task.EstimatedHours
+		accuracy.ActualHours += *task.ActualHours
+		ratioSum += ratio
+
+		switch {
+		case ratio < onTargetMin:
+			accuracy.Overestimated++
+		case ratio < onTargetMax:
+			accuracy.OnTarget++
+		default:
+			accuracy.Underestimated++
+		}
+		for i := len(estimateBounds) - 1; i >= 0; i-- {
+			if ratio >= estimateBounds[i] {
+				accuracy.Distribution[i].Count++
+				break
+			}
+		}
+	}
+
+	if accuracy.Tasks > 0 {
+		accuracy.AverageRatio = roundRatio(ratioSum / float64(accuracy.Tasks))
+		accuracy.OverallRatio = roundRatio(accuracy.ActualHours / accuracy.EstimatedHours)
+	}
+	return accuracy
+}
+
+func roundRatio(ratio float64) float64 {
+	return math.Round(ratio*100) / 100
+}
+
+// userLocation picks the ?tz= override, then the stored preference, then UTC
+func (h *DashboardHandler) userLocation(c *gin.Context, userID string) (*time.Location, error) {
+	return requestLocation(c, h.store(c), userID)
+}
+
+// GetActivity returns a feed of recent actions derived from item timestamps,
+// newest first. ?days= sets the window (default 30), ?limit= and ?offset= page
+// through it.
+func (h *DashboardHandler) GetActivity(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > 365 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 365"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 200"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must not be negative"})
+		return
+	}
+
+	since := h.config.Clock.Now().AddDate(0, 0, -days)
+	var activity []models.ActivityEntry
+	add := func(kind, action, id, title string, at *time.Time) {
+		if at != nil && !at.IsZero() && !at.Before(since) {
+			activity = append(activity, models.ActivityEntry{Type: kind, Action: action, ID: id, Title: title, Timestamp: *at})
+		}
+	}
+
+	tasks, err := h.store(c).GetTasks(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks", "details": err.Error()})
+		return
+	}
+	for _, task := range tasks {
+		add("task", "created", task.ID, task.Title, &task.CreatedAt)
+		add("task", "started", task.ID, task.Title, task.StartedAt)
+		add("task", "completed", task.ID, task.Title, task.CompletedAt)
+	}
+
+	meetings, err := h.store(c).GetMeetings(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meetings", "details": err.Error()})
+		return
+	}
+	for _, meeting := range meetings {
+		add("meeting", "created", meeting.ID, meeting.Title, &meeting.CreatedAt)
+		add("meeting", "completed", meeting.ID, meeting.Title, meeting.CompletedAt)
+	}
+
+	reminders, err := h.store(c).GetReminders(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reminders", "details": err.Error()})
+		return
+	}
+	for _, reminder := range reminders {
+		add("reminder", "completed", reminder.ID, reminder.Title, reminder.CompletedAt)
+	}
+
+	sort.SliceStable(activity, func(i, j int) bool {
+		return activity[i].Timestamp.After(activity[j].Timestamp)
+	})
+
+	total := len(activity)
+	page := []models.ActivityEntry{}
+	if offset < total {
+		page = activity[offset:min(offset+limit, total)]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"activity": page,
+		"total":    total,
+		"limit":    limit,
+		"offset":   offset,
+	})
 }
\ No newline at end of file
@@ -0,0 +1,688 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"focusflow-be/internal/models"
+)
+
+func TestGetBadgesDay(t *testing.T) {
+	// 02:30 UTC is still the previous evening in New York
+	now := time.Date(2026, 10, 16, 2, 30, 0, 0, time.UTC)
+	newYork, _ := time.LoadLocation("America/New_York")
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+
+	tests := []struct {
+		name     string
+		stored   string
+		query    string
+		want     time.Time
+		wantCode int
+	}{
+		{"defaults to UTC", "", "", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), http.StatusOK},
+		{"stored time zone", "America/New_York", "", time.Date(2026, 10, 15, 0, 0, 0, 0, newYork), http.StatusOK},
+		{"tz overrides stored zone", "America/New_York", "?tz=Asia/Tokyo", time.Date(2026, 10, 16, 0, 0, 0, 0, tokyo), http.StatusOK},
+		{"invalid tz", "", "?tz=Mars/Olympus", time.Time{}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.users["user-1"] = &models.UserSession{UserID: "user-1", Timezone: tt.stored}
+			h := NewDashboardHandler(store, nil, testConfig(now))
+
+			w := serve(h.GetBadges, http.MethodGet, "/dashboard/badges", "/dashboard/badges"+tt.query, "", "user-1")
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			if len(store.badgeDays) != 1 || !store.badgeDays[0].Equal(tt.want) {
+				t.Errorf("day started at %v, want %v", store.badgeDays, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverviewCache(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	// Each step reads the overview, expecting want tasks, or changes the tasks:
+	// "write" goes through the API, "failed write" is rejected after touching
+	// the store, and "external" changes the store behind the API's back
+	type step struct {
+		action string
+		want   int
+	}
+	get := func(want int) step { return step{"get", want} }
+	fresh := func(want int) step { return step{"fresh", want} }
+
+	tests := []struct {
+		name  string
+		ttl   time.Duration
+		steps []step
+	}{
+		{"read is cached", time.Minute, []step{get(1), {action: "external"}, get(1)}},
+		{"write invalidates and read repopulates", time.Minute, []step{get(1), {action: "write"}, get(2), {action: "external"}, get(2)}},
+		{"failed write keeps the cache", time.Minute, []step{get(1), {action: "failed write"}, get(1)}},
+		{"fresh bypasses the cache", time.Minute, []step{get(1), {action: "external"}, fresh(2)}},
+		{"zero TTL disables the cache", 0, []step{get(1), {action: "external"}, get(2)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.tasks["t0"] = &models.Task{ID: "t0", UserID: "user-1", Status: "todo"}
+			addTask := func() {
+				id := fmt.Sprintf("t%d", len(store.tasks))
+				store.tasks[id] = &models.Task{ID: id, UserID: "user-1", Status: "todo"}
+			}
+			cfg := testConfig(now)
+			cfg.OverviewCacheTTL = tt.ttl
+			h := NewDashboardHandler(store, nil, cfg)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(func(c *gin.Context) { c.Set("user", &models.UserSession{UserID: "user-1"}) })
+			r.Use(h.InvalidateOverviews())
+			r.GET("/dashboard/overview", h.GetOverview)
+			r.POST("/tasks", func(c *gin.Context) {
+				addTask()
+				if c.Query("fail") == "true" {
+					c.Status(http.StatusBadRequest)
+					return
+				}
+				c.Status(http.StatusCreated)
+			})
+
+			for i, s := range tt.steps {
+				var path string
+				switch s.action {
+				case "external":
+					addTask()
+					continue
+				case "write":
+					r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/tasks", nil))
+					continue
+				case "failed write":
+					r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/tasks?fail=true", nil))
+					continue
+				case "get":
+					path = "/dashboard/overview"
+				case "fresh":
+					path = "/dashboard/overview?fresh=true"
+				}
+
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+				var overview models.Overview
+				if err := json.Unmarshal(w.Body.Bytes(), &overview); err != nil {
+					t.Fatalf("step %d: %v: %s", i, err, w.Body.String())
+				}
+				if overview.Tasks.Total != s.want {
+					t.Errorf("step %d (%s): tasks total = %d, want %d", i, s.action, overview.Tasks.Total, s.want)
+				}
+			}
+		})
+	}
+}
+
+func TestOverviewAttendanceRate(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	attended, missed := true, false
+
+	tests := []struct {
+		name         string
+		marks        []*bool
+		wantAttended int
+		wantMissed   int
+		wantRate     float64
+	}{
+		{"nothing marked", []*bool{nil, nil}, 0, 0, 0},
+		{"all attended", []*bool{&attended, &attended}, 2, 0, 1},
+		{"unmarked meetings don't count", []*bool{&attended, &missed, &missed, &attended, nil}, 2, 2, 0.5},
+		{"one in three", []*bool{&attended, &missed, &missed}, 1, 2, 1.0 / 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			for i, mark := range tt.marks {
+				id := fmt.Sprintf("m%d", i)
+				store.meetings[id] = &models.Meeting{ID: id, UserID: "user-1", Status: "completed", StartTime: now.AddDate(0, 0, -i-1), Attended: mark}
+			}
+			h := NewDashboardHandler(store, nil, testConfig(now))
+
+			var overview models.Overview
+			decode(t, serve(h.GetOverview, http.MethodGet, "/dashboard/overview", "/dashboard/overview", "", "user-1"), &overview)
+			got := overview.Meetings
+			if got.Attended != tt.wantAttended || got.Missed != tt.wantMissed || got.AttendanceRate != tt.wantRate {
+				t.Errorf("attended %d, missed %d, rate %v; want %d, %d, %v", got.Attended, got.Missed, got.AttendanceRate, tt.wantAttended, tt.wantMissed, tt.wantRate)
+			}
+		})
+	}
+}
+
+func TestCalendarEventsHumanize(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	remindAt := time.Date(2026, 10, 19, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		query       string
+		wantDisplay string
+	}{
+		{"machine fields only by default", "", ""},
+		{"stored preference", "?humanize=true", "lundi 19 octobre 2026 14:30"},
+		{"override", "?humanize=true&locale=en-US", "Monday, October 19, 2026 2:30 PM"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.users["user-1"] = &models.UserSession{UserID: "user-1", Locale: "fr-FR"}
+			store.reminders["call"] = &models.Reminder{ID: "call", UserID: "user-1", Title: "Call", ReminderTime: remindAt}
+			h := NewDashboardHandler(store, nil, testConfig(now))
+
+			var events []models.CalendarEvent
+			decode(t, serve(h.GetCalendarEvents, http.MethodGet, "/dashboard/calendar", "/dashboard/calendar"+tt.query, "", "user-1"), &events)
+			if len(events) != 1 {
+				t.Fatalf("events = %+v, want the reminder", events)
+			}
+			event := events[0]
+			if event.Start != "2026-10-19T14:30:00Z" {
+				t.Errorf("start = %q, want RFC3339", event.Start)
+			}
+			var display string
+			if event.DisplayStart != nil {
+				display = *event.DisplayStart
+			}
+			if display != tt.wantDisplay {
+				t.Errorf("displayStart = %q, want %q", display, tt.wantDisplay)
+			}
+		})
+	}
+}
+
+func TestGetOverdue(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	at := func(day, hour, minute int) *time.Time {
+		when := time.Date(2026, 10, day, hour, minute, 0, 0, time.UTC)
+		return &when
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		wantIDs []string
+	}{
+		{"UTC", "", []string{"late-task", "missed-meeting", "late-reminder"}},
+		// Today began at 04:00 UTC in New York, so a task due at 03:00 UTC is a day late there
+		{"New York", "?tz=America/New_York", []string{"late-task", "missed-meeting", "ny-task", "late-reminder"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.tasks["late-task"] = &models.Task{ID: "late-task", UserID: "user-1", Status: "todo", DueDate: at(14, 17, 0)}
+			store.tasks["ny-task"] = &models.Task{ID: "ny-task", UserID: "user-1", Status: "in-progress", DueDate: at(16, 3, 0)}
+			store.tasks["today-task"] = &models.Task{ID: "today-task", UserID: "user-1", Status: "todo", DueDate: at(16, 7, 0)}
+			store.tasks["done-task"] = &models.Task{ID: "done-task", UserID: "user-1", Status: "completed", DueDate: at(14, 17, 0)}
+			store.tasks["undated-task"] = &models.Task{ID: "undated-task", UserID: "user-1", Status: "todo"}
+			store.tasks["their-task"] = &models.Task{ID: "their-task", UserID: "user-2", Status: "todo", DueDate: at(14, 17, 0)}
+			store.reminders["late-reminder"] = &models.Reminder{ID: "late-reminder", UserID: "user-1", ReminderTime: *at(16, 8, 0)}
+			store.reminders["grace-reminder"] = &models.Reminder{ID: "grace-reminder", UserID: "user-1", ReminderTime: *at(16, 8, 50)}
+			store.reminders["done-reminder"] = &models.Reminder{ID: "done-reminder", UserID: "user-1", ReminderTime: *at(16, 7, 0), IsCompleted: true}
+			store.meetings["missed-meeting"] = &models.Meeting{ID: "missed-meeting", UserID: "user-1", Status: "scheduled", StartTime: *at(15, 10, 0)}
+			store.meetings["grace-meeting"] = &models.Meeting{ID: "grace-meeting", UserID: "user-1", Status: "scheduled", StartTime: *at(16, 8, 50)}
+			store.meetings["held-meeting"] = &models.Meeting{ID: "held-meeting", UserID: "user-1", Status: "completed", StartTime: *at(15, 10, 0)}
+			store.meetings["cancelled-meeting"] = &models.Meeting{ID: "cancelled-meeting", UserID: "user-1", Status: "cancelled", StartTime: *at(15, 10, 0)}
+			store.meetings["next-meeting"] = &models.Meeting{ID: "next-meeting", UserID: "user-1", Status: "scheduled", StartTime: *at(17, 10, 0)}
+			cfg := testConfig(now)
+			cfg.OverdueGrace = 15 * time.Minute
+			h := NewDashboardHandler(store, nil, cfg)
+
+			var response struct {
+				Items []models.OverdueItem `json:"items"`
+				Total int                  `json:"total"`
+			}
+			decode(t, serve(h.GetOverdue, http.MethodGet, "/dashboard/overdue", "/dashboard/overdue"+tt.query, "", "user-1"), &response)
+
+			var ids []string
+			for _, item := range response.Items {
+				ids = append(ids, item.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) || response.Total != len(tt.wantIDs) {
+				t.Errorf("overdue = %v (total %d), want %v, most overdue first", ids, response.Total, tt.wantIDs)
+			}
+			if last := response.Items[len(response.Items)-1]; last.OverdueMinutes != 60 {
+				t.Errorf("%s overdue by %d minutes, want 60", last.ID, last.OverdueMinutes)
+			}
+		})
+	}
+}
+
+func TestGetEstimateAccuracy(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	hours := func(h float64) *float64 { return &h }
+
+	store := newMockStore()
+	seed := []*models.Task{
+		{ID: "slow", Status: "completed", EstimatedHours: hours(2), ActualHours: hours(3)},        // 1.5
+		{ID: "exact", Status: "completed", EstimatedHours: hours(4), ActualHours: hours(4)},       // 1.0
+		{ID: "quick", Status: "completed", EstimatedHours: hours(2), ActualHours: hours(0.5)},     // 0.25
+		{ID: "very-slow", Status: "completed", EstimatedHours: hours(1), ActualHours: hours(2.5)}, // 2.5
+		// Left out: unfinished, missing a figure, or estimated at zero
+		{ID: "open", Status: "in-progress", EstimatedHours: hours(1), ActualHours: hours(5)},
+		{ID: "no-actual", Status: "completed", EstimatedHours: hours(3)},
+		{ID: "no-estimate", Status: "completed", ActualHours: hours(3)},
+		{ID: "zero-estimate", Status: "completed", EstimatedHours: hours(0), ActualHours: hours(1)},
+	}
+	for _, task := range seed {
+		task.UserID = "user-1"
+		store.tasks[task.ID] = task
+	}
+	h := NewDashboardHandler(store, nil, testConfig(now))
+
+	var got models.EstimateAccuracy
+	decode(t, serve(h.GetEstimateAccuracy, http.MethodGet, "/dashboard/estimate-accuracy", "/dashboard/estimate-accuracy", "", "user-1"), &got)
+
+	if got.Tasks != 4 || got.EstimatedHours != 9 || got.ActualHours != 10 {
+		t.Errorf("tasks %d, estimated %v, actual %v; want 4, 9, 10", got.Tasks, got.EstimatedHours, got.ActualHours)
+	}
+	// Mean of 1.5, 1, 0.25 and 2.5; and 10 / 9 overall
+	if got.AverageRatio != 1.31 || got.OverallRatio != 1.11 {
+		t.Errorf("average %v, overall %v; want 1.31, 1.11", got.AverageRatio, got.OverallRatio)
+	}
+	if got.Underestimated != 2 || got.OnTarget != 1 || got.Overestimated != 1 {
+		t.Errorf("under %d, on target %d, over %d; want 2, 1, 1", got.Underestimated, got.OnTarget, got.Overestimated)
+	}
+	wantBuckets := map[string]int{"0-0.5": 1, "0.5-0.8": 0, "0.8-1.2": 1, "1.2-2": 1, "2+": 1}
+	if len(got.Distribution) != len(wantBuckets) {
+		t.Fatalf("distribution = %+v, want %d buckets", got.Distribution, len(wantBuckets))
+	}
+	for _, bucket := range got.Distribution {
+		if want, ok := wantBuckets[bucket.Label]; !ok || bucket.Count != want {
+			t.Errorf("bucket %s has %d tasks, want %d", bucket.Label, bucket.Count, want)
+		}
+	}
+}
+
+func TestEstimateAccuracyEmpty(t *testing.T) {
+	got := estimateAccuracy(nil)
+	if got.Tasks != 0 || got.AverageRatio != 0 || got.OverallRatio != 0 || len(got.Distribution) != len(estimateBounds) {
+		t.Errorf("estimateAccuracy(nil) = %+v, want zeros and empty buckets", got)
+	}
+}
+
+func TestComputeAvailability(t *testing.T) {
+	at := func(hour, minute int) time.Time { return time.Date(2026, 10, 20, hour, minute, 0, 0, time.UTC) }
+	span := func(fromHour, fromMinute, toHour, toMinute int) models.TimeRange {
+		return models.TimeRange{Start: at(fromHour, fromMinute), End: at(toHour, toMinute)}
+	}
+
+	busy := []models.TimeRange{
+		span(11, 30, 13, 0), // runs past the end of the day
+		span(9, 45, 10, 30), // overlaps the one below
+		span(9, 15, 10, 0),
+		span(10, 30, 10, 45), // touches the merged range
+		span(7, 0, 8, 0),     // before the day starts
+	}
+	got := computeAvailability(at(9, 0), at(12, 0), busy, 30*time.Minute)
+
+	if want := []models.TimeRange{span(9, 15, 10, 45), span(11, 30, 12, 0)}; !slices.Equal(got.Busy, want) {
+		t.Errorf("busy = %v, want %v", got.Busy, want)
+	}
+	if want := []models.TimeRange{span(9, 0, 9, 15), span(10, 45, 11, 30)}; !slices.Equal(got.Free, want) {
+		t.Errorf("free = %v, want %v", got.Free, want)
+	}
+	if got.BusyMinutes != 120 || got.FreeMinutes != 60 {
+		t.Errorf("busy %d min, free %d min; want 120, 60", got.BusyMinutes, got.FreeMinutes)
+	}
+	if got.LongestFree == nil || *got.LongestFree != span(10, 45, 11, 30) {
+		t.Errorf("longest free = %v, want 10:45-11:30", got.LongestFree)
+	}
+	var slots []bool
+	for _, slot := range got.Slots {
+		slots = append(slots, slot.Busy)
+	}
+	if want := []bool{true, true, true, true, false, true}; !slices.Equal(slots, want) {
+		t.Errorf("slots busy = %v, want %v", slots, want)
+	}
+
+	// A day that doesn't divide evenly keeps its short last slot
+	short := computeAvailability(at(9, 0), at(10, 10), nil, time.Hour)
+	if len(short.Slots) != 2 || short.Slots[1] != (models.AvailabilitySlot{Start: at(10, 0), End: at(10, 10)}) {
+		t.Errorf("slots = %v, want 09:00-10:00 and 10:00-10:10", short.Slots)
+	}
+	if len(short.Busy) != 0 || len(short.Free) != 1 || short.FreeMinutes != 70 {
+		t.Errorf("empty day = busy %v, free %v (%d min)", short.Busy, short.Free, short.FreeMinutes)
+	}
+}
+
+func TestGetAvailability(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := func(hour, minute int) time.Time { return time.Date(2026, 10, 20, hour, minute, 0, 0, tokyo) }
+	buffer := 15
+
+	tests := []struct {
+		name     string
+		query    string
+		wantBusy []models.TimeRange
+	}{
+		{"meetings and timed tasks", "", []models.TimeRange{{Start: local(9, 45), End: local(11, 0)}, {Start: local(11, 30), End: local(12, 0)}}},
+		{"meetings only", "&includeTasks=false", []models.TimeRange{{Start: local(9, 45), End: local(11, 0)}}},
+		{"tentative included", "&includeTentative=true&includeTasks=false", []models.TimeRange{{Start: local(9, 0), End: local(9, 30)}, {Start: local(9, 45), End: local(11, 0)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.meetings["sync"] = &models.Meeting{ID: "sync", UserID: "user-1", Status: "scheduled", StartTime: local(10, 0), EndTime: local(10, 30), BufferBefore: &buffer}
+			store.meetings["overlap"] = &models.Meeting{ID: "overlap", UserID: "user-1", Status: "scheduled", StartTime: local(10, 15), EndTime: local(11, 0)}
+			store.meetings["maybe"] = &models.Meeting{ID: "maybe", UserID: "user-1", Status: "scheduled", StartTime: local(9, 0), EndTime: local(9, 30), Tentative: true}
+			store.meetings["called-off"] = &models.Meeting{ID: "called-off", UserID: "user-1", Status: "cancelled", StartTime: local(11, 0), EndTime: local(12, 0)}
+			taskStart, taskDue := local(11, 30), local(12, 0)
+			store.tasks["focus"] = &models.Task{ID: "focus", UserID: "user-1", Status: "todo", StartDate: &taskStart, DueDate: &taskDue}
+			store.tasks["done"] = &models.Task{ID: "done", UserID: "user-1", Status: "completed", StartDate: &taskStart, DueDate: &taskDue}
+			h := NewDashboardHandler(store, nil, testConfig(now))
+
+			path := "/dashboard/availability?date=2026-10-20&tz=Asia/Tokyo&workStart=09:00&workEnd=12:00" + tt.query
+			var got models.Availability
+			decode(t, serve(h.GetAvailability, http.MethodGet, "/dashboard/availability", path, "", "user-1"), &got)
+
+			if got.Date != "2026-10-20" || got.Timezone != "Asia/Tokyo" || !got.WorkStart.Equal(local(9, 0)) || got.SlotMinutes != 30 || len(got.Slots) != 6 {
+				t.Errorf("day = %s %s from %v, %d slots of %d min", got.Date, got.Timezone, got.WorkStart, len(got.Slots), got.SlotMinutes)
+			}
+			if len(got.Busy) != len(tt.wantBusy) {
+				t.Fatalf("busy = %v, want %v", got.Busy, tt.wantBusy)
+			}
+			for i, r := range got.Busy {
+				if !r.Start.Equal(tt.wantBusy[i].Start) || !r.End.Equal(tt.wantBusy[i].End) {
+					t.Errorf("busy[%d] = %v-%v, want %v-%v", i, r.Start, r.End, tt.wantBusy[i].Start, tt.wantBusy[i].End)
+				}
+			}
+		})
+	}
+}
+
+func TestFindConflicts(t *testing.T) {
+	at := func(hour, minute int) time.Time { return time.Date(2026, 10, 20, hour, minute, 0, 0, time.UTC) }
+	meeting := func(id string, fromHour, fromMinute, toHour, toMinute int) models.ConflictItem {
+		return models.ConflictItem{ID: id, Type: "meeting", Start: at(fromHour, fromMinute), End: at(toHour, toMinute)}
+	}
+	task := func(id string, fromHour, fromMinute, toHour, toMinute int) models.ConflictItem {
+		item := meeting(id, fromHour, fromMinute, toHour, toMinute)
+		item.Type = "task"
+		return item
+	}
+
+	tests := []struct {
+		name  string
+		items []models.ConflictItem
+		want  []string // first/second/minutes
+	}{
+		{"overlapping", []models.ConflictItem{meeting("b", 9, 30, 10, 30), meeting("a", 9, 0, 10, 0)}, []string{"a/b/30"}},
+		{"nested", []models.ConflictItem{meeting("outer", 9, 0, 12, 0), meeting("inner", 10, 0, 10, 45)}, []string{"outer/inner/45"}},
+		{"adjacent", []models.ConflictItem{meeting("a", 9, 0, 10, 0), meeting("b", 10, 0, 11, 0)}, nil},
+		{"three deep", []models.ConflictItem{meeting("a", 9, 0, 11, 0), meeting("b", 9, 30, 10, 30), meeting("c", 10, 0, 10, 15)}, []string{"a/b/60", "a/c/15", "b/c/15"}},
+		{"ended before the next starts", []models.ConflictItem{meeting("a", 9, 0, 9, 30), meeting("b", 9, 15, 12, 0), meeting("c", 10, 0, 11, 0)}, []string{"a/b/15", "b/c/60"}},
+		{"task over a meeting", []models.ConflictItem{task("focus", 9, 0, 17, 0), meeting("sync", 10, 0, 10, 30)}, []string{"focus/sync/30"}},
+		{"tasks side by side", []models.ConflictItem{task("a", 9, 0, 17, 0), task("b", 10, 0, 12, 0)}, nil},
+		{"zero length", []models.ConflictItem{meeting("a", 9, 0, 10, 0), meeting("b", 9, 30, 9, 30)}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, conflict := range findConflicts(tt.items) {
+				got = append(got, fmt.Sprintf("%s/%s/%d", conflict.First.ID, conflict.Second.ID, conflict.OverlapMinutes))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("conflicts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetConflicts(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time { return time.Date(2026, 10, 20, hour, minute, 0, 0, time.UTC) }
+	buffer := 15
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"meetings and timed tasks", "", []string{"sync/early/10", "sync/focus/15"}},
+		{"meetings only", "?includeTasks=false", []string{"sync/early/10"}},
+		{"out of range", "?days=1", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.meetings["sync"] = &models.Meeting{ID: "sync", UserID: "user-1", Status: "scheduled", StartTime: at(10, 0), EndTime: at(10, 30), BufferBefore: &buffer}
+			store.meetings["early"] = &models.Meeting{ID: "early", UserID: "user-1", Status: "scheduled", StartTime: at(9, 50), EndTime: at(10, 0)}
+			store.meetings["called-off"] = &models.Meeting{ID: "called-off", UserID: "user-1", Status: "cancelled", StartTime: at(10, 0), EndTime: at(11, 0)}
+			taskStart, taskDue := at(10, 15), at(11, 0)
+			store.tasks["focus"] = &models.Task{ID: "focus", UserID: "user-1", Status: "todo", StartDate: &taskStart, DueDate: &taskDue}
+			store.tasks["done"] = &models.Task{ID: "done", UserID: "user-1", Status: "completed", StartDate: &taskStart, DueDate: &taskDue}
+			h := NewDashboardHandler(store, nil, testConfig(now))
+
+			var body struct {
+				Count     int               `json:"count"`
+				Conflicts []models.Conflict `json:"conflicts"`
+			}
+			decode(t, serve(h.GetConflicts, http.MethodGet, "/dashboard/conflicts", "/dashboard/conflicts"+tt.query, "", "user-1"), &body)
+
+			var got []string
+			for _, conflict := range body.Conflicts {
+				got = append(got, fmt.Sprintf("%s/%s/%d", conflict.First.ID, conflict.Second.ID, conflict.OverlapMinutes))
+			}
+			if !slices.Equal(got, tt.want) || body.Count != len(tt.want) {
+				t.Errorf("conflicts = %v (count %d), want %v", got, body.Count, tt.want)
+			}
+		})
+	}
+
+	h := NewDashboardHandler(newMockStore(), nil, testConfig(now))
+	if w := serve(h.GetConflicts, http.MethodGet, "/dashboard/conflicts", "/dashboard/conflicts?days=0", "", "user-1"); w.Code != http.StatusBadRequest {
+		t.Errorf("days=0: status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestGetSchedule(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(day, hour, minute int) time.Time { return time.Date(2026, 10, day, hour, minute, 0, 0, newYork) }
+
+	store := newMockStore()
+	store.meetings["standup"] = &models.Meeting{ID: "standup", UserID: "user-1", Status: "scheduled", StartTime: at(20, 9, 30), EndTime: at(20, 10, 30)}
+	// Wednesday in UTC, but Tuesday evening in New York
+	store.meetings["late"] = &models.Meeting{ID: "late", UserID: "user-1", Status: "scheduled", StartTime: at(20, 22, 0), EndTime: at(20, 22, 30)}
+	store.meetings["called-off"] = &models.Meeting{ID: "called-off", UserID: "user-1", Status: "cancelled", StartTime: at(19, 10, 0), EndTime: at(19, 11, 0)}
+	focusStart, focusDue := at(22, 11, 0), at(22, 12, 0)
+	store.tasks["focus"] = &models.Task{ID: "focus", UserID: "user-1", Status: "todo", StartDate: &focusStart, DueDate: &focusDue}
+	fridayNight := at(23, 23, 30)
+	store.tasks["anytime"] = &models.Task{ID: "anytime", UserID: "user-1", Status: "todo", DueDate: &fridayNight}
+	store.tasks["done"] = &models.Task{ID: "done", UserID: "user-1", Status: "completed", DueDate: &fridayNight}
+	nextWeek := at(26, 10, 0)
+	store.tasks["next-week"] = &models.Task{ID: "next-week", UserID: "user-1", Status: "todo", DueDate: &nextWeek}
+	store.tasks["undated"] = &models.Task{ID: "undated", UserID: "user-1", Status: "todo"}
+	h := NewDashboardHandler(store, nil, testConfig(now))
+
+	var got models.WeekSchedule
+	path := "/dashboard/schedule?week=2026-10-21&tz=America/New_York&workStart=09:00&workEnd=12:00"
+	decode(t, serve(h.GetSchedule, http.MethodGet, "/dashboard/schedule", path, "", "user-1"), &got)
+
+	if got.WeekStart != "2026-10-19" || got.Timezone != "America/New_York" || got.WorkStart != "09:00" || got.WorkEnd != "12:00" || got.SlotMinutes != 60 {
+		t.Errorf("week = %s %s %s-%s every %d min", got.WeekStart, got.Timezone, got.WorkStart, got.WorkEnd, got.SlotMinutes)
+	}
+	if len(got.Days) != 7 || got.Days[0].Weekday != "Monday" || got.Days[6].Date != "2026-10-25" {
+		t.Fatalf("days = %+v, want Monday 19th to Sunday 25th", got.Days)
+	}
+
+	// Where each item landed, as date/slot index, date/anytime or date/outside
+	var placed []string
+	for _, day := range got.Days {
+		if len(day.Slots) != 3 || day.Slots[0].Start.In(newYork).Format("15:04") != "09:00" {
+			t.Errorf("%s slots = %+v, want three from 09:00", day.Date, day.Slots)
+		}
+		for i, slot := range day.Slots {
+			for _, item := range slot.Items {
+				placed = append(placed, fmt.Sprintf("%s/%d/%s", day.Date, i, item.ID))
+			}
+		}
+		for _, item := range day.Anytime {
+			placed = append(placed, day.Date+"/anytime/"+item.ID)
+		}
+		for _, item := range day.OutsideHours {
+			placed = append(placed, day.Date+"/outside/"+item.ID)
+		}
+	}
+	want := []string{
+		"2026-10-20/0/standup",
+		"2026-10-20/1/standup",
+		"2026-10-20/outside/late",
+		"2026-10-22/2/focus",
+		"2026-10-23/anytime/anytime",
+	}
+	if !slices.Equal(placed, want) {
+		t.Errorf("placed = %v, want %v", placed, want)
+	}
+}
+
+func TestBuildWeekScheduleDST(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Clocks go back on Sunday 1 November
+	weekStart := time.Date(2026, 10, 26, 0, 0, 0, 0, newYork)
+	days := buildWeekSchedule(weekStart, "09:00", "17:00", time.Hour, nil)
+	for _, day := range days {
+		if len(day.Slots) != 8 {
+			t.Errorf("%s has %d slots, want 8", day.Date, len(day.Slots))
+			continue
+		}
+		if start := day.Slots[0].Start.In(newYork); start.Hour() != 9 || start.Minute() != 0 {
+			t.Errorf("%s starts at %s, want 09:00", day.Date, start.Format("15:04"))
+		}
+	}
+	if days[6].Date != "2026-11-01" {
+		t.Errorf("last day = %s, want 2026-11-01", days[6].Date)
+	}
+}
+
+func TestWorkloadByDayFractionalHours(t *testing.T) {
+	day := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	hours := func(h float64) *float64 { return &h }
+	due := day.Add(17 * time.Hour)
+	nextDue := due.AddDate(0, 0, 1)
+
+	tasks := []*models.Task{
+		{ID: "a", Status: "todo", EstimatedHours: hours(1.5), DueDate: &due},
+		{ID: "b", Status: "in-progress", EstimatedHours: hours(0.75), DueDate: &due},
+		{ID: "c", Status: "todo", EstimatedHours: hours(2.25), DueDate: &nextDue},
+		{ID: "done", Status: "completed", EstimatedHours: hours(4), DueDate: &due},
+	}
+	meetings := []*models.Meeting{
+		{ID: "standup", Status: "scheduled", StartTime: day.Add(9 * time.Hour), EndTime: day.Add(9*time.Hour + 15*time.Minute)},
+	}
+
+	days := workloadByDay(day, day.AddDate(0, 0, 2), tasks, meetings, 150*time.Minute)
+	want := []models.WorkloadDay{
+		{Date: "2026-10-20", TaskHours: 2.25, MeetingHours: 0.25, TotalHours: 2.5},
+		{Date: "2026-10-21", TaskHours: 2.25, TotalHours: 2.25},
+	}
+	if !slices.Equal(days, want) {
+		t.Errorf("workload = %+v, want %+v", days, want)
+	}
+
+	// Just over capacity
+	tasks[1].EstimatedHours = hours(1)
+	if days := workloadByDay(day, day.AddDate(0, 0, 1), tasks, meetings, 150*time.Minute); !days[0].OverCapacity || days[0].TotalHours != 2.75 {
+		t.Errorf("workload = %+v, want 2.75 hours over a 2.5 hour capacity", days[0])
+	}
+}
+
+// The overview, the overdue list, the badges and the agenda must all agree on
+// which tasks are overdue
+func TestOverdueDefinitionsAgree(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	due := func(day, hour, min int) *time.Time {
+		d := time.Date(2026, 10, day, hour, min, 0, 0, time.UTC)
+		return &d
+	}
+
+	tests := []struct {
+		name     string
+		timezone string
+		want     int
+	}{
+		// Yesterday plus an hour of grace ended at 23:00 UTC on the 15th
+		{"UTC", "", 2},
+		// and at 03:00 UTC on the 16th in New York, catching 02:30 as well
+		{"New York", "America/New_York", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.users["user-1"] = &models.UserSession{UserID: "user-1", Timezone: tt.timezone}
+			for i, dueDate := range []*time.Time{due(14, 21, 0), due(15, 22, 0), due(16, 2, 30), due(16, 3, 0), due(17, 9, 0), nil} {
+				id := fmt.Sprintf("t%d", i+1)
+				store.tasks[id] = &models.Task{ID: id, UserID: "user-1", Status: "todo", DueDate: dueDate}
+			}
+			store.tasks["done"] = &models.Task{ID: "done", UserID: "user-1", Status: "completed", DueDate: due(1, 9, 0)}
+			cfg := testConfig(now)
+			cfg.OverdueGrace = time.Hour
+			dashboard := NewDashboardHandler(store, nil, cfg)
+			tasks := NewTaskHandler(store, nil, nil, cfg)
+
+			var overview models.Overview
+			decode(t, serve(dashboard.GetOverview, http.MethodGet, "/dashboard/overview", "/dashboard/overview", "", "user-1"), &overview)
+			var overdue struct {
+				Total int `json:"total"`
+			}
+			decode(t, serve(dashboard.GetOverdue, http.MethodGet, "/dashboard/overdue", "/dashboard/overdue", "", "user-1"), &overdue)
+			var badges models.BadgeCounts
+			decode(t, serve(dashboard.GetBadges, http.MethodGet, "/dashboard/badges", "/dashboard/badges", "", "user-1"), &badges)
+			var agenda struct {
+				Overdue []*models.Task `json:"overdue"`
+			}
+			decode(t, serve(tasks.GetTaskAgenda, http.MethodGet, "/tasks/agenda", "/tasks/agenda", "", "user-1"), &agenda)
+
+			got := map[string]int{
+				"overview": overview.Tasks.Overdue,
+				"overdue":  overdue.Total,
+				"badges":   badges.Overdue,
+				"agenda":   len(agenda.Overdue),
+			}
+			for source, count := range got {
+				if count != tt.want {
+					t.Errorf("%s counts %d overdue, want %d", source, count, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func decode(t *testing.T, w *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("decoding %s: %v", w.Body.String(), err)
+	}
+}
@@ -1,26 +1,65 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"focusflow-be/internal/config"
+	"focusflow-be/internal/ical"
 	"focusflow-be/internal/models"
+	"focusflow-be/internal/recurrence"
 	"focusflow-be/internal/services"
 )
 
 type MeetingHandler struct {
-	firebaseService *services.FirebaseService
-	authService     *services.AuthService
+	requestStore
+	authService   *services.AuthService
+	googleService *services.GoogleService
+	config        *config.Config
 }
 
-func NewMeetingHandler(firebaseService *services.FirebaseService, authService *services.AuthService) *MeetingHandler {
+func NewMeetingHandler(firebaseService services.Store, authService *services.AuthService, googleService *services.GoogleService, cfg *config.Config) *MeetingHandler {
 	return &MeetingHandler{
-		firebaseService: firebaseService,
-		authService:     authService,
+		requestStore:  requestStore{firebaseService},
+		authService:   authService,
+		googleService: googleService,
+		config:        cfg,
 	}
 }
 
+// Meetings starting outside these hours get an off-hours warning
+const (
+	workdayStartHour = 8
+	workdayEndHour   = 18
+)
+
+// validateMeetingTimes rejects inverted, too short, too long, or long-past meetings
+func (h *MeetingHandler) validateMeetingTimes(start, end time.Time) error {
+	if end.Before(start) {
+		return errors.New("End time must be after start time")
+	}
+
+	duration := end.Sub(start)
+	if duration < h.config.MeetingMinDuration {
+		return fmt.Errorf("Meeting must last at least %s", h.config.MeetingMinDuration)
+	}
+	if duration > h.config.MeetingMaxDuration {
+		return fmt.Errorf("Meeting cannot last longer than %s", h.config.MeetingMaxDuration)
+	}
+	if start.Before(h.config.Clock.Now().Add(-h.config.MeetingPastTolerance)) {
+		return fmt.Errorf("Meeting cannot start more than %s in the past", h.config.MeetingPastTolerance)
+	}
+
+	return nil
+}
+
 func (h *MeetingHandler) GetMeetings(c *gin.Context) {
 	user, exists := c.Get("user")
 	if !exists {
@@ -29,13 +68,50 @@ func (h *MeetingHandler) GetMeetings(c *gin.Context) {
 	}
 
 	userSession := user.(*models.UserSession)
-	meetings, err := h.firebaseService.GetMeetings(userSession.UserID)
+
+	var meetings []*models.Meeting
+	var err error
+	if attendee := c.Query("attendee"); attendee != "" {
+		meetings, err = h.store(c).GetMeetingsWithAttendee(userSession.UserID, normalizeEmail(attendee))
+	} else {
+		meetings, err = h.store(c).GetMeetings(userSession.UserID)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meetings", "details": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, meetings)
+	applyTimedMeetingStatus(h.store(c), userSession.UserID, h.config.Clock.Now(), meetings...)
+
+	// Keep list payloads small; the full list is on GET /meetings/:id
+	if limit := h.config.MeetingListMaxAttendees; limit > 0 {
+		for _, meeting := range meetings {
+			meeting.AttendeeCount = len(meeting.Attendees)
+			if len(meeting.Attendees) > limit {
+				meeting.Attendees = meeting.Attendees[:limit]
+			}
+		}
+	}
+
+	respondData(c, http.StatusOK, meetings)
+}
+
+// GetMeeting returns one meeting with all of its attendees
+func (h *MeetingHandler) GetMeeting(c *gin.Context) {
+	meetingID := c.Param("id")
+	if meetingID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Meeting ID is required"})
+		return
+	}
+
+	meeting, ok := h.loadOwnedMeeting(c, meetingID)
+	if !ok {
+		return
+	}
+
+	applyTimedMeetingStatus(h.store(c), meeting.UserID, h.config.Clock.Now(), meeting)
+	meeting.AttendeeCount = len(meeting.Attendees)
+	c.JSON(http.StatusOK, meeting)
 }
 
 func (h *MeetingHandler) CreateMeeting(c *gin.Context) {
@@ -53,33 +129,412 @@ func (h *MeetingHandler) CreateMeeting(c *gin.Context) {
 		return
 	}
 
-	// Validate that end time is after start time
-	if req.EndTime.Before(req.StartTime) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "End time must be after start time"})
+	endTime := req.StartTime.Add(h.config.MeetingDefaultDuration)
+	if req.EndTime != nil {
+		endTime = *req.EndTime
+	}
+
+	if err := h.validateMeetingTimes(req.StartTime, endTime); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	meeting := &models.Meeting{
-		UserID:      userSession.UserID,
-		Title:       req.Title,
-		Description: req.Description,
-		StartTime:   req.StartTime,
-		EndTime:     req.EndTime,
-		Attendees:   req.Attendees,
-		Location:    req.Location,
-		MeetingType: req.MeetingType,
-		Status:      "scheduled",
+		UserID:       userSession.UserID,
+		Title:        req.Title,
+		Description:  req.Description,
+		StartTime:    req.StartTime,
+		EndTime:      endTime,
+		Attendees:    normalizeEmails(req.Attendees),
+		Location:     req.Location,
+		MeetingType:  req.MeetingType,
+		Status:       "scheduled",
+		BufferBefore: req.BufferBefore,
+		BufferAfter:  req.BufferAfter,
+
+		ReminderMinutes: req.ReminderMinutes,
+		NotifyAttendees: req.NotifyAttendees,
+		Tentative:       req.Tentative,
+	}
+
+	// Conflicts are reported rather than rejected, since double-booking is sometimes intended
+	var conflicts []string
+	if existing, err := h.store(c).GetMeetings(userSession.UserID); err == nil {
+		conflicts = findMeetingConflicts(existing, meeting, h.config.ConflictsIncludeTentative)
 	}
 
-	meetingID, err := h.firebaseService.CreateMeeting(meeting)
+	var warn warnings
+	if len(conflicts) > 0 {
+		warn.add("overlaps %d other meeting(s)", len(conflicts))
+	}
+	// Judged in the offset the client sent the start time with
+	if hour := req.StartTime.Hour(); hour < workdayStartHour || hour >= workdayEndHour {
+		warn.add("startTime is outside working hours (%02d:00-%02d:00)", workdayStartHour, workdayEndHour)
+	}
+	if day := req.StartTime.Weekday(); day == time.Saturday || day == time.Sunday {
+		warn.add("startTime falls on a weekend")
+	}
+	if warn.rejectIfStrict(c) {
+		return
+	}
+
+	meetingID, err := h.store(c).CreateMeeting(meeting)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create meeting", "details": err.Error()})
 		return
 	}
 
+	blockedStart, blockedEnd := meeting.BlockedWindow()
+	response := gin.H{
+		"id":           meetingID,
+		"message":      "Meeting created successfully",
+		"blockedStart": blockedStart,
+		"blockedEnd":   blockedEnd,
+	}
+	if len(conflicts) > 0 {
+		response["conflicts"] = conflicts
+	}
+	c.JSON(http.StatusCreated, warn.attach(response))
+}
+
+// findMeetingConflicts lists the IDs of active meetings whose blocked window,
+// buffers included, overlaps the candidate's. Unless includeTentative is set,
+// tentative meetings neither conflict nor are conflicted with.
+func findMeetingConflicts(meetings []*models.Meeting, candidate *models.Meeting, includeTentative bool) []string {
+	start, end := candidate.BlockedWindow()
+	conflicts := []string{}
+	if candidate.Tentative && !includeTentative {
+		return conflicts
+	}
+	for _, other := range meetings {
+		if other.Status == "cancelled" || other.Status == "completed" || (other.Tentative && !includeTentative) {
+			continue
+		}
+		otherStart, otherEnd := other.BlockedWindow()
+		if start.Before(otherEnd) && otherStart.Before(end) {
+			conflicts = append(conflicts, other.ID)
+		}
+	}
+	return conflicts
+}
+
+func (h *MeetingHandler) DuplicateMeeting(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	meetingID := c.Param("id")
+	if meetingID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Meeting ID is required"})
+		return
+	}
+
+	var req models.DuplicateMeetingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validateMeetingTimes(req.StartTime, req.EndTime); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	source, ok := h.loadOwnedMeeting(c, meetingID)
+	if !ok {
+		return
+	}
+
+	// Copy the template fields only; the copy gets its own status and calendar event
+	meeting := &models.Meeting{
+		UserID:       userSession.UserID,
+		Title:        source.Title,
+		Description:  source.Description,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		Attendees:    source.Attendees,
+		Location:     source.Location,
+		MeetingType:  source.MeetingType,
+		Status:       "scheduled",
+		BufferBefore: source.BufferBefore,
+		BufferAfter:  source.BufferAfter,
+
+		ReminderMinutes: source.ReminderMinutes,
+		NotifyAttendees: source.NotifyAttendees,
+		Tentative:       source.Tentative,
+	}
+
+	newMeetingID, err := h.store(c).CreateMeeting(meeting)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to duplicate meeting", "details": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
-		"id":      meetingID,
-		"message": "Meeting created successfully",
+		"id":      newMeetingID,
+		"message": "Meeting duplicated successfully",
+	})
+}
+
+func (h *MeetingHandler) UpdateMeeting(c *gin.Context) {
+	meetingID := c.Param("id")
+	if meetingID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Meeting ID is required"})
+		return
+	}
+
+	meeting, ok := h.loadOwnedMeeting(c, meetingID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateMeetingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	// A time sent on its own is checked against the stored other end
+	start, end := meeting.StartTime, meeting.EndTime
+	timesChanged := req.StartTime != nil || req.EndTime != nil
+	if timesChanged {
+		if req.StartTime != nil {
+			start = *req.StartTime
+		}
+		if req.EndTime != nil {
+			end = *req.EndTime
+		}
+		if err := h.validateMeetingTimes(start, end); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	updates := make(map[string]interface{})
+	if req.Title != nil {
+		updates["title"] = *req.Title
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.StartTime != nil {
+		updates["startTime"] = *req.StartTime
+	}
+	if req.EndTime != nil {
+		updates["endTime"] = *req.EndTime
+	}
+	if req.Attendees != nil {
+		updates["attendees"] = normalizeEmails(req.Attendees)
+	}
+	if len(req.AddAttendees) > 0 || len(req.RemoveAttendees) > 0 {
+		if req.Attendees != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "attendees cannot be combined with addAttendees or removeAttendees"})
+			return
+		}
+		if attendees := editAttendees(meeting.Attendees, req.AddAttendees, req.RemoveAttendees); len(attendees) > 0 {
+			updates["attendees"] = attendees
+		} else {
+			updates["attendees"] = services.DeleteField
+		}
+	}
+	if req.Location != nil {
+		updates["location"] = *req.Location
+	}
+	if req.MeetingType != nil {
+		updates["meetingType"] = *req.MeetingType
+	}
+	if req.NotifyAttendees != nil {
+		updates["notifyAttendees"] = *req.NotifyAttendees
+	}
+	if req.Tentative != nil {
+		if *req.Tentative {
+			updates["tentative"] = true
+		} else {
+			updates["tentative"] = services.DeleteField
+		}
+	}
+	if err := applyClearFields(updates, req.ClearFields); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.store(c).UpdateMeeting(meetingID, updates); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update meeting", "details": err.Error()})
+		return
+	}
+
+	response := gin.H{"message": "Meeting updated successfully"}
+	if timesChanged && meeting.GoogleEventID != nil {
+		notify := meeting.NotifyAttendees
+		if req.NotifyAttendees != nil {
+			notify = req.NotifyAttendees
+		}
+		profile, err := h.store(c).GetUser(meeting.UserID)
+		if err == nil {
+			err = h.googleService.MoveCalendarEvent(h.googleService.UserToken(profile), *meeting.GoogleEventID, start, end, services.SendUpdates(notify))
+		}
+		if err != nil {
+			log.Printf("⚠️ Failed to move calendar event for meeting %s: %v", meetingID, err)
+			response["calendarError"] = err.Error()
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RescheduleMeeting moves a meeting to new times, counting the move. Conflicts
+// are reported as warnings like on create. A meeting on Google Calendar is
+// moved there too, which notifies its attendees unless notifyAttendees is
+// false; if that fails the meeting stays rescheduled and the error is returned
+// alongside.
+func (h *MeetingHandler) RescheduleMeeting(c *gin.Context) {
+	meetingID := c.Param("id")
+	if meetingID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Meeting ID is required"})
+		return
+	}
+
+	meeting, ok := h.loadOwnedMeeting(c, meetingID)
+	if !ok {
+		return
+	}
+
+	var req models.RescheduleMeetingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if meeting.Status == "completed" || meeting.Status == "cancelled" {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("A %s meeting cannot be rescheduled", meeting.Status)})
+		return
+	}
+
+	endTime := req.StartTime.Add(meeting.EndTime.Sub(meeting.StartTime))
+	if req.EndTime != nil {
+		endTime = *req.EndTime
+	}
+	if err := h.validateMeetingTimes(req.StartTime, endTime); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	moved := *meeting
+	moved.StartTime, moved.EndTime = req.StartTime, endTime
+
+	var conflicts []string
+	if existing, err := h.store(c).GetMeetings(meeting.UserID); err == nil {
+		for _, id := range findMeetingConflicts(existing, &moved, h.config.ConflictsIncludeTentative) {
+			if id != meeting.ID {
+				conflicts = append(conflicts, id)
+			}
+		}
+	}
+
+	var warn warnings
+	if len(conflicts) > 0 {
+		warn.add("overlaps %d other meeting(s)", len(conflicts))
+	}
+	if warn.rejectIfStrict(c) {
+		return
+	}
+
+	updates := map[string]interface{}{
+		"startTime":       moved.StartTime,
+		"endTime":         moved.EndTime,
+		"rescheduleCount": meeting.RescheduleCount + 1,
+	}
+	if err := h.store(c).UpdateMeeting(meetingID, updates); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reschedule meeting", "details": err.Error()})
+		return
+	}
+
+	blockedStart, blockedEnd := moved.BlockedWindow()
+	response := gin.H{
+		"message":         "Meeting rescheduled successfully",
+		"startTime":       moved.StartTime,
+		"endTime":         moved.EndTime,
+		"blockedStart":    blockedStart,
+		"blockedEnd":      blockedEnd,
+		"rescheduleCount": meeting.RescheduleCount + 1,
+	}
+	if len(conflicts) > 0 {
+		response["conflicts"] = conflicts
+	}
+
+	if meeting.GoogleEventID != nil {
+		notify := meeting.NotifyAttendees
+		if req.NotifyAttendees != nil {
+			notify = req.NotifyAttendees
+		}
+		profile, err := h.store(c).GetUser(meeting.UserID)
+		if err == nil {
+			err = h.googleService.MoveCalendarEvent(h.googleService.UserToken(profile), *meeting.GoogleEventID, moved.StartTime, moved.EndTime, services.SendUpdates(notify))
+		}
+		if err != nil {
+			log.Printf("⚠️ Failed to move calendar event for meeting %s: %v", meetingID, err)
+			response["calendarError"] = err.Error()
+		}
+	}
+
+	c.JSON(http.StatusOK, warn.attach(response))
+}
+
+// SyncCalendar pushes the caller's upcoming scheduled meetings that aren't on
+// their calendar yet, with their travel blocks, notification overrides and
+// attendee emails. Each meeting is synced independently; the response lists
+// the ones that failed so they can be retried.
+func (h *MeetingHandler) SyncCalendar(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+	profile, err := h.store(c).GetUser(userSession.UserID)
+	if err != nil {
+		respondResourceError(c, "User", err)
+		return
+	}
+
+	meetings, err := h.store(c).GetMeetings(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meetings", "details": err.Error()})
+		return
+	}
+
+	now := h.config.Clock.Now()
+	token := h.googleService.UserToken(profile)
+	updates := make(map[string]map[string]interface{})
+	failed := []models.SyncFailure{}
+	for _, meeting := range meetings {
+		if meeting.GoogleEventID != nil || meeting.Status != "scheduled" || !meeting.StartTime.After(now) {
+			continue
+		}
+		// The event can exist even when a travel block failed; keep its ID
+		// so a retry doesn't create it twice
+		eventID, err := h.googleService.CreateCalendarMeeting(token, meeting)
+		if eventID != "" {
+			updates[meeting.ID] = map[string]interface{}{"googleEventId": eventID}
+		}
+		if err != nil {
+			failed = append(failed, models.SyncFailure{ID: meeting.ID, Error: err.Error()})
+		}
+	}
+
+	if err := h.store(c).BatchUpdateMeetings(updates); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Events created but failed to save their IDs", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"synced": len(updates),
+		"failed": failed,
 	})
 }
 
@@ -90,20 +545,548 @@ func (h *MeetingHandler) UpdateMeetingStatus(c *gin.Context) {
 		return
 	}
 
+	meeting, ok := h.loadOwnedMeeting(c, meetingID)
+	if !ok {
+		return
+	}
+
 	var req models.UpdateMeetingStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
 		return
 	}
 
+	if reason := meetingTransitionError(meeting, req.Status, h.config.Clock.Now(), c.Query("force") == "true"); reason != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status transition", "details": reason})
+		return
+	}
+
 	updates := map[string]interface{}{
 		"status": req.Status,
 	}
+	if req.Status == "completed" {
+		updates["completedAt"] = h.config.Clock.Now()
+	}
 
-	if err := h.firebaseService.UpdateMeeting(meetingID, updates); err != nil {
+	if err := h.store(c).UpdateMeeting(meetingID, updates); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update meeting status", "details": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Meeting status updated successfully"})
-}
\ No newline at end of file
+}
+
+func (h *MeetingHandler) UpdateMeetingAttendance(c *gin.Context) {
+	meetingID := c.Param("id")
+	if meetingID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Meeting ID is required"})
+		return
+	}
+
+	var req models.UpdateMeetingAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	meeting, ok := h.loadOwnedMeeting(c, meetingID)
+	if !ok {
+		return
+	}
+
+	// Attendance only makes sense once the meeting has started
+	if meeting.StartTime.After(h.config.Clock.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Attendance can only be recorded after the meeting has started"})
+		return
+	}
+
+	updates := map[string]interface{}{
+		"attended": *req.Attended,
+	}
+
+	if err := h.store(c).UpdateMeeting(meetingID, updates); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update meeting attendance", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Meeting attendance updated successfully"})
+}
+
+// UpdateMeetingNotes sets a meeting's notes and/or action items. Items sent
+// with an existing ID keep any task they were converted into.
+func (h *MeetingHandler) UpdateMeetingNotes(c *gin.Context) {
+	meetingID := c.Param("id")
+	if meetingID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Meeting ID is required"})
+		return
+	}
+
+	var req models.UpdateMeetingNotesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	meeting, ok := h.loadOwnedMeeting(c, meetingID)
+	if !ok {
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.Notes != nil {
+		if strings.TrimSpace(*req.Notes) == "" {
+			updates["notes"] = services.DeleteField
+		} else {
+			updates["notes"] = *req.Notes
+		}
+	}
+	if req.ActionItems != nil {
+		existing := make(map[string]models.ActionItem, len(meeting.ActionItems))
+		for _, item := range meeting.ActionItems {
+			existing[item.ID] = item
+		}
+
+		items := make([]models.ActionItem, 0, len(req.ActionItems))
+		for _, input := range req.ActionItems {
+			item := models.ActionItem{ID: input.ID, Text: input.Text, Done: input.Done}
+			if input.Assignee != nil {
+				assignee := normalizeEmail(*input.Assignee)
+				item.Assignee = &assignee
+			}
+			if previous, found := existing[input.ID]; found && input.ID != "" {
+				item.TaskID = previous.TaskID
+			} else {
+				id, err := services.NewDocumentID()
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update meeting notes", "details": err.Error()})
+					return
+				}
+				item.ID = id
+			}
+			items = append(items, item)
+		}
+
+		if len(items) == 0 {
+			updates["actionItems"] = services.DeleteField
+		} else {
+			updates["actionItems"] = items
+		}
+	}
+
+	if len(updates) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+
+	if err := h.store(c).UpdateMeeting(meetingID, updates); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update meeting notes", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Meeting notes updated successfully"})
+}
+
+// ActionItemToTask turns one of a meeting's action items into a standalone
+// task and links the item to it
+func (h *MeetingHandler) ActionItemToTask(c *gin.Context) {
+	meetingID := c.Param("id")
+	itemID := c.Param("itemId")
+	if meetingID == "" || itemID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Meeting ID and action item ID are required"})
+		return
+	}
+
+	var req models.ActionItemToTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	meeting, ok := h.loadOwnedMeeting(c, meetingID)
+	if !ok {
+		return
+	}
+
+	index := -1
+	for i, item := range meeting.ActionItems {
+		if item.ID == itemID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Action item not found"})
+		return
+	}
+	item := meeting.ActionItems[index]
+	if item.TaskID != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Action item has already been converted to a task", "taskId": *item.TaskID})
+		return
+	}
+
+	priority := "medium"
+	if req.Priority != nil {
+		priority = *req.Priority
+	}
+	description := fmt.Sprintf("From meeting: %s", meeting.Title)
+	task := &models.Task{
+		UserID:      meeting.UserID,
+		Title:       item.Text,
+		Description: &description,
+		Completed:   item.Done,
+		Status:      "todo",
+		Priority:    priority,
+		DueDate:     req.DueDate,
+	}
+	if item.Done {
+		task.Status = "completed"
+	}
+
+	taskID, err := h.store(c).CreateTask(task)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create task", "details": err.Error()})
+		return
+	}
+
+	meeting.ActionItems[index].TaskID = &taskID
+	if err := h.store(c).UpdateMeeting(meetingID, map[string]interface{}{"actionItems": meeting.ActionItems}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Task created but failed to link action item", "details": err.Error(), "id": taskID})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id":      taskID,
+		"message": "Task created from action item",
+	})
+}
+
+// meetingTransitionError explains why a meeting can't move to status at now,
+// or returns "" when it can. Completing a meeting before it ends is allowed
+// with force, for meetings that wrap up early.
+func meetingTransitionError(meeting *models.Meeting, status string, now time.Time, force bool) string {
+	switch status {
+	case "ongoing":
+		if meeting.Status == "cancelled" {
+			return "a cancelled meeting must be rescheduled before it can be ongoing"
+		}
+		if now.Before(meeting.StartTime) {
+			return "a meeting cannot be ongoing before its start time"
+		}
+		if !now.Before(meeting.EndTime) {
+			return "a meeting cannot be ongoing after its end time"
+		}
+	case "completed":
+		if meeting.Status == "cancelled" {
+			return "a cancelled meeting cannot be completed"
+		}
+		if now.Before(meeting.StartTime) {
+			return "a meeting cannot be completed before it starts"
+		}
+		if now.Before(meeting.EndTime) && !force {
+			return "a meeting cannot be completed before its end time; pass ?force=true if it ended early"
+		}
+	}
+	return ""
+}
+
+// loadOwnedMeeting fetches a meeting owned by the current user, writing the
+// ownership policy's error response when that fails
+func (h *MeetingHandler) loadOwnedMeeting(c *gin.Context, meetingID string) (*models.Meeting, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return nil, false
+	}
+
+	userSession := user.(*models.UserSession)
+	meeting, err := h.store(c).GetMeeting(meetingID)
+	if err == nil {
+		err = checkOwnership(meeting, userSession.UserID)
+	}
+	if err != nil {
+		respondResourceError(c, "Meeting", err)
+		return nil, false
+	}
+
+	return meeting, true
+}
+
+// editAttendees adds and removes attendees from a list, keeping its order and
+// skipping duplicates
+func editAttendees(current, add, remove []string) []string {
+	removed := make(map[string]bool, len(remove))
+	for _, email := range remove {
+		removed[normalizeEmail(email)] = true
+	}
+
+	seen := make(map[string]bool)
+	attendees := []string{}
+	for _, email := range append(normalizeEmails(current), normalizeEmails(add)...) {
+		if removed[email] || seen[email] {
+			continue
+		}
+		seen[email] = true
+		attendees = append(attendees, email)
+	}
+	return attendees
+}
+
+// Attendees are stored lowercased so they can be matched with array-contains
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func normalizeEmails(emails []string) []string {
+	if emails == nil {
+		return nil
+	}
+	normalized := make([]string, len(emails))
+	for i, email := range emails {
+		normalized[i] = normalizeEmail(email)
+	}
+	return normalized
+}
+
+// Largest .ics upload accepted by ImportICS
+const maxICSSize = 1 << 20
+
+// ImportICS creates or updates meetings from an uploaded iCalendar file. Events
+// are matched to earlier imports by UID, so re-uploading an edited export
+// updates meetings in place. Recurring events are expanded within the
+// configured recurrence limits, one meeting per occurrence.
+func (h *MeetingHandler) ImportICS(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "An .ics file is required in the file field", "details": err.Error()})
+		return
+	}
+	if header.Size > maxICSSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("File is larger than %d bytes", maxICSSize)})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file", "details": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	events, err := ical.Parse(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid calendar file", "details": err.Error()})
+		return
+	}
+
+	result := &models.ICSImportResult{Errors: []*models.ImportError{}}
+	reject := func(index int, uid, reason string) {
+		if uid != "" {
+			reason = uid + ": " + reason
+		}
+		result.Errors = append(result.Errors, &models.ImportError{Type: "event", Index: index, Error: reason})
+	}
+
+	// Keyed by external ID; an edited instance of a recurring event replaces
+	// the occurrence generated from the series.
+	var order []string
+	imported := make(map[string]*models.Meeting)
+	add := func(externalID string, meeting *models.Meeting) {
+		if _, ok := imported[externalID]; !ok {
+			order = append(order, externalID)
+		}
+		imported[externalID] = meeting
+	}
+
+	limits := recurrence.Limits{MaxOccurrences: h.config.RecurrenceMaxOccurrences, Horizon: h.config.RecurrenceHorizon}
+	for i, event := range events {
+		if event.Err != nil {
+			reject(i, event.UID, event.Err.Error())
+			continue
+		}
+		if event.UID == "" {
+			reject(i, "", "missing UID")
+			continue
+		}
+		if event.AllDay {
+			reject(i, event.UID, "all-day events can't be imported as meetings")
+			continue
+		}
+
+		duration := h.config.MeetingDefaultDuration
+		if !event.End.IsZero() {
+			duration = event.End.Sub(event.Start)
+		} else if event.Duration != 0 {
+			duration = event.Duration
+		}
+		if duration < 0 {
+			reject(i, event.UID, "ends before it starts")
+			continue
+		}
+		if duration > h.config.MeetingMaxDuration {
+			reject(i, event.UID, fmt.Sprintf("lasts longer than %s", h.config.MeetingMaxDuration))
+			continue
+		}
+
+		if event.RecurrenceID != nil {
+			add(occurrenceID(event.UID, *event.RecurrenceID), meetingFromEvent(event, event.Start, duration))
+			continue
+		}
+
+		rule, err := event.Rule()
+		if err != nil {
+			reject(i, event.UID, err.Error())
+			continue
+		}
+		if rule == nil {
+			add(event.UID, meetingFromEvent(event, event.Start, duration))
+			continue
+		}
+
+		occurrences, _, err := recurrence.Expand(event.Start, *rule, event.Start.Location(), limits)
+		if err != nil {
+			reject(i, event.UID, err.Error())
+			continue
+		}
+		for _, start := range occurrences {
+			if containsTime(event.ExDates, start) {
+				continue
+			}
+			add(occurrenceID(event.UID, start), meetingFromEvent(event, start, duration))
+		}
+	}
+
+	existing, err := h.store(c).GetMeetings(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meetings", "details": err.Error()})
+		return
+	}
+	byExternalID := make(map[string]*models.Meeting)
+	for _, meeting := range existing {
+		if meeting.ExternalID != nil {
+			byExternalID[*meeting.ExternalID] = meeting
+		}
+	}
+
+	now := h.config.Clock.Now()
+	var creates []interface{}
+	updates := make(map[string]map[string]interface{})
+	for _, externalID := range order {
+		meeting := imported[externalID]
+		if current, ok := byExternalID[externalID]; ok {
+			updates[current.ID] = importedMeetingUpdates(meeting)
+			continue
+		}
+		meeting.UserID = userSession.UserID
+		meeting.ExternalID = &externalID
+		meeting.CreatedAt = now
+		meeting.UpdatedAt = now
+		creates = append(creates, meeting)
+	}
+
+	if len(updates) > 0 {
+		if err := h.store(c).BatchUpdateMeetings(updates); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update meetings", "details": err.Error()})
+			return
+		}
+		result.Updated = len(updates)
+	}
+	if len(creates) > 0 {
+		ids, err := h.store(c).BatchCreate("meetings", creates)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create meetings", "details": err.Error(), "updated": result.Updated})
+			return
+		}
+		result.Created = len(ids)
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// meetingFromEvent maps one occurrence of an iCalendar event onto a meeting
+func meetingFromEvent(event ical.Event, start time.Time, duration time.Duration) *models.Meeting {
+	meeting := &models.Meeting{
+		Title:       event.Summary,
+		StartTime:   start.UTC(),
+		EndTime:     start.Add(duration).UTC(),
+		Attendees:   normalizeEmails(event.Attendees),
+		MeetingType: "call",
+		Status:      "scheduled",
+	}
+	if meeting.Title == "" {
+		meeting.Title = "Untitled event"
+	}
+	if event.Description != "" {
+		meeting.Description = &event.Description
+	}
+	if event.Location != "" {
+		meeting.Location = &event.Location
+		meeting.MeetingType = "in-person"
+		if strings.HasPrefix(event.Location, "http://") || strings.HasPrefix(event.Location, "https://") {
+			meeting.MeetingType = "video"
+		}
+	}
+	if event.Status == "CANCELLED" {
+		meeting.Status = "cancelled"
+	}
+	meeting.Tentative = event.Status == "TENTATIVE"
+	return meeting
+}
+
+// importedMeetingUpdates overwrites the calendar-owned fields of a meeting
+// imported before. Status only changes when the event was cancelled, so
+// meetings completed here aren't reset to scheduled.
+func importedMeetingUpdates(meeting *models.Meeting) map[string]interface{} {
+	updates := map[string]interface{}{
+		"title":       meeting.Title,
+		"startTime":   meeting.StartTime,
+		"endTime":     meeting.EndTime,
+		"meetingType": meeting.MeetingType,
+	}
+	optional := []struct {
+		field string
+		value interface{}
+		set   bool
+	}{
+		{"description", meeting.Description, meeting.Description != nil},
+		{"location", meeting.Location, meeting.Location != nil},
+		{"attendees", meeting.Attendees, len(meeting.Attendees) > 0},
+	}
+	for _, field := range optional {
+		if field.set {
+			updates[field.field] = field.value
+		} else {
+			updates[field.field] = services.DeleteField
+		}
+	}
+	if meeting.Status == "cancelled" {
+		updates["status"] = meeting.Status
+	}
+	if meeting.Tentative {
+		updates["tentative"] = true
+	} else {
+		updates["tentative"] = services.DeleteField
+	}
+	return updates
+}
+
+// occurrenceID identifies one instance of a recurring event the way
+// RECURRENCE-ID does, so edited instances match their generated occurrence
+func occurrenceID(uid string, start time.Time) string {
+	return uid + "/" + start.UTC().Format("20060102T150405Z")
+}
+
+func containsTime(times []time.Time, t time.Time) bool {
+	for _, candidate := range times {
+		if candidate.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
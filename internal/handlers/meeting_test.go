@@ -0,0 +1,344 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"focusflow-be/internal/models"
+)
+
+func TestDuplicateMeeting(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	body := `{"startTime": "2026-10-20T10:00:00Z", "endTime": "2026-10-20T11:00:00Z"}`
+
+	tests := []struct {
+		name      string
+		meetingID string
+		userID    string
+		want      int
+	}{
+		{"missing meeting", "nope", "user-1", http.StatusNotFound},
+		{"someone else's meeting", "standup", "user-2", http.StatusNotFound},
+		{"own meeting", "standup", "user-1", http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.meetings["standup"] = &models.Meeting{ID: "standup", UserID: "user-1", Title: "Standup", MeetingType: "video"}
+			h := NewMeetingHandler(store, nil, nil, testConfig(now))
+
+			w := serve(h.DuplicateMeeting, http.MethodPost, "/meetings/:id/duplicate", "/meetings/"+tt.meetingID+"/duplicate", body, tt.userID)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if created := len(store.created) > 0; created != (tt.want == http.StatusCreated) {
+				t.Errorf("meeting created = %v, want %v", created, !created)
+			}
+		})
+	}
+}
+
+func TestUpdateMeetingTimes(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"both times valid", `{"startTime": "2026-10-20T14:00:00Z", "endTime": "2026-10-20T15:00:00Z"}`, http.StatusOK},
+		{"end only, after stored start", `{"endTime": "2026-10-20T11:30:00Z"}`, http.StatusOK},
+		{"end only, before stored start", `{"endTime": "2026-10-20T09:00:00Z"}`, http.StatusBadRequest},
+		{"end only, too long", `{"endTime": "2026-10-22T10:00:00Z"}`, http.StatusBadRequest},
+		{"start only, before stored end", `{"startTime": "2026-10-20T10:30:00Z"}`, http.StatusOK},
+		{"start only, after stored end", `{"startTime": "2026-10-20T12:00:00Z"}`, http.StatusBadRequest},
+		{"start only, years in the past", `{"startTime": "2020-10-20T10:00:00Z"}`, http.StatusBadRequest},
+		{"no times", `{"title": "Planning"}`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.meetings["planning"] = &models.Meeting{
+				ID:        "planning",
+				UserID:    "user-1",
+				StartTime: time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(2026, 10, 20, 11, 0, 0, 0, time.UTC),
+			}
+			h := NewMeetingHandler(store, nil, nil, testConfig(now))
+
+			w := serve(h.UpdateMeeting, http.MethodPut, "/meetings/:id", "/meetings/planning", tt.body, "user-1")
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if written := len(store.updates) > 0; written != (tt.want == http.StatusOK) {
+				t.Errorf("update written = %v, want %v", written, !written)
+			}
+		})
+	}
+}
+
+func TestFindMeetingConflicts(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 10, 20, hour, minute, 0, 0, time.UTC)
+	}
+	minutes := func(n int) *int { return &n }
+
+	tests := []struct {
+		name      string
+		other     models.Meeting
+		candidate models.Meeting
+		want      []string
+	}{
+		{
+			name:      "overlapping",
+			other:     models.Meeting{StartTime: at(10, 0), EndTime: at(11, 0), Status: "scheduled"},
+			candidate: models.Meeting{StartTime: at(10, 30), EndTime: at(11, 30)},
+			want:      []string{"other"},
+		},
+		{
+			name:      "back to back",
+			other:     models.Meeting{StartTime: at(10, 0), EndTime: at(11, 0), Status: "scheduled"},
+			candidate: models.Meeting{StartTime: at(11, 0), EndTime: at(12, 0)},
+			want:      []string{},
+		},
+		{
+			name:      "other's buffer reaches the candidate",
+			other:     models.Meeting{StartTime: at(10, 0), EndTime: at(11, 0), BufferAfter: minutes(30), Status: "scheduled"},
+			candidate: models.Meeting{StartTime: at(11, 15), EndTime: at(12, 0)},
+			want:      []string{"other"},
+		},
+		{
+			name:      "candidate's buffer reaches the other",
+			other:     models.Meeting{StartTime: at(10, 0), EndTime: at(11, 0), Status: "scheduled"},
+			candidate: models.Meeting{StartTime: at(11, 15), EndTime: at(12, 0), BufferBefore: minutes(15)},
+			want:      []string{},
+		},
+		{
+			name:      "candidate's buffer overlaps the other",
+			other:     models.Meeting{StartTime: at(10, 0), EndTime: at(11, 0), Status: "scheduled"},
+			candidate: models.Meeting{StartTime: at(11, 15), EndTime: at(12, 0), BufferBefore: minutes(20)},
+			want:      []string{"other"},
+		},
+		{
+			name:      "cancelled meetings don't conflict",
+			other:     models.Meeting{StartTime: at(10, 0), EndTime: at(11, 0), Status: "cancelled"},
+			candidate: models.Meeting{StartTime: at(10, 30), EndTime: at(11, 30)},
+			want:      []string{},
+		},
+		{
+			name:      "completed meetings don't conflict",
+			other:     models.Meeting{StartTime: at(10, 0), EndTime: at(11, 0), Status: "completed"},
+			candidate: models.Meeting{StartTime: at(10, 30), EndTime: at(11, 30)},
+			want:      []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.other.ID = "other"
+			got := findMeetingConflicts([]*models.Meeting{&tt.other}, &tt.candidate, true)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("conflicts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindMeetingConflictsTentative(t *testing.T) {
+	start := time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC)
+	meeting := func(tentative bool) *models.Meeting {
+		return &models.Meeting{ID: "other", StartTime: start, EndTime: start.Add(time.Hour), Status: "scheduled", Tentative: tentative}
+	}
+
+	tests := []struct {
+		name             string
+		otherTentative   bool
+		tentative        bool
+		includeTentative bool
+		want             []string
+	}{
+		{"both confirmed", false, false, false, []string{"other"}},
+		{"tentative other skipped", true, false, false, []string{}},
+		{"tentative candidate skipped", false, true, false, []string{}},
+		{"tentative other included", true, false, true, []string{"other"}},
+		{"tentative candidate included", false, true, true, []string{"other"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidate := meeting(tt.tentative)
+			candidate.ID = "candidate"
+			got := findMeetingConflicts([]*models.Meeting{meeting(tt.otherTentative)}, candidate, tt.includeTentative)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("conflicts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeetingTransitionError(t *testing.T) {
+	start := time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		current string
+		status  string
+		now     time.Time
+		force   bool
+		wantErr bool
+	}{
+		{"ongoing during the meeting", "scheduled", "ongoing", start.Add(30 * time.Minute), false, false},
+		{"ongoing at the start", "scheduled", "ongoing", start, false, false},
+		{"ongoing before the start", "scheduled", "ongoing", start.Add(-time.Minute), false, true},
+		{"ongoing at the end", "scheduled", "ongoing", end, false, true},
+		{"ongoing when cancelled", "cancelled", "ongoing", start.Add(30 * time.Minute), false, true},
+		{"completed after the end", "ongoing", "completed", end.Add(time.Minute), false, false},
+		{"completed at the end", "ongoing", "completed", end, false, false},
+		{"completed early", "ongoing", "completed", start.Add(30 * time.Minute), false, true},
+		{"completed early with force", "ongoing", "completed", start.Add(30 * time.Minute), true, false},
+		{"completed before the start with force", "scheduled", "completed", start.Add(-time.Minute), true, true},
+		{"completed when cancelled", "cancelled", "completed", end.Add(time.Minute), true, true},
+		{"cancelled any time", "scheduled", "cancelled", start.Add(-time.Hour), false, false},
+		{"scheduled again", "cancelled", "scheduled", end.Add(time.Hour), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meeting := &models.Meeting{StartTime: start, EndTime: end, Status: tt.current}
+			reason := meetingTransitionError(meeting, tt.status, tt.now, tt.force)
+			if (reason != "") != tt.wantErr {
+				t.Errorf("reason = %q, want error %v", reason, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEditAttendees(t *testing.T) {
+	tests := []struct {
+		name    string
+		current []string
+		add     []string
+		remove  []string
+		want    []string
+	}{
+		{"add", []string{"a@example.com"}, []string{"b@example.com"}, nil, []string{"a@example.com", "b@example.com"}},
+		{"remove", []string{"a@example.com", "b@example.com"}, nil, []string{"a@example.com"}, []string{"b@example.com"}},
+		{"add existing", []string{"a@example.com"}, []string{"A@Example.com "}, nil, []string{"a@example.com"}},
+		{"remove ignores case", []string{"a@example.com"}, nil, []string{" A@EXAMPLE.COM"}, []string{}},
+		{"remove wins over add", nil, []string{"a@example.com"}, []string{"a@example.com"}, []string{}},
+		{"remove missing", []string{"a@example.com"}, nil, []string{"z@example.com"}, []string{"a@example.com"}},
+		{"duplicates in current", []string{"a@example.com", "A@example.com"}, nil, nil, []string{"a@example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := editAttendees(tt.current, tt.add, tt.remove)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("attendees = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateMeetingAttendance(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		meetingID string
+		body      string
+		want      int
+		wantSet   interface{}
+	}{
+		{"mark attended", "past", `{"attended": true}`, http.StatusOK, true},
+		{"mark missed", "past", `{"attended": false}`, http.StatusOK, false},
+		{"meeting in progress", "started", `{"attended": true}`, http.StatusOK, true},
+		{"meeting not started", "future", `{"attended": true}`, http.StatusBadRequest, nil},
+		{"attended missing", "past", `{}`, http.StatusBadRequest, nil},
+		{"someone else's meeting", "theirs", `{"attended": true}`, http.StatusNotFound, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.meetings["past"] = &models.Meeting{ID: "past", UserID: "user-1", StartTime: now.Add(-2 * time.Hour)}
+			store.meetings["started"] = &models.Meeting{ID: "started", UserID: "user-1", StartTime: now}
+			store.meetings["future"] = &models.Meeting{ID: "future", UserID: "user-1", StartTime: now.Add(time.Minute)}
+			store.meetings["theirs"] = &models.Meeting{ID: "theirs", UserID: "user-2", StartTime: now.Add(-2 * time.Hour)}
+			h := NewMeetingHandler(store, nil, nil, testConfig(now))
+
+			w := serve(h.UpdateMeetingAttendance, http.MethodPatch, "/meetings/:id/attendance", "/meetings/"+tt.meetingID+"/attendance", tt.body, "user-1")
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.wantSet == nil {
+				if len(store.updates) != 0 {
+					t.Errorf("updates = %v, want none", store.updates)
+				}
+				return
+			}
+			if len(store.updates) != 1 || store.updates[0]["attended"] != tt.wantSet {
+				t.Errorf("updates = %v, want attended = %v", store.updates, tt.wantSet)
+			}
+		})
+	}
+}
+
+func TestRescheduleMeeting(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	start := time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		meetingID     string
+		userID        string
+		body          string
+		want          int
+		wantStart     time.Time
+		wantEnd       time.Time
+		wantConflicts []string
+	}{
+		{"new start keeps the length", "standup", "user-1", `{"startTime": "2026-10-21T10:00:00Z"}`, http.StatusOK, start.AddDate(0, 0, 1), start.AddDate(0, 0, 1).Add(30 * time.Minute), nil},
+		{"new start and end", "standup", "user-1", `{"startTime": "2026-10-21T10:00:00Z", "endTime": "2026-10-21T11:00:00Z"}`, http.StatusOK, start.AddDate(0, 0, 1), start.AddDate(0, 0, 1).Add(time.Hour), nil},
+		{"onto another meeting", "standup", "user-1", `{"startTime": "2026-10-22T14:15:00Z"}`, http.StatusOK, time.Date(2026, 10, 22, 14, 15, 0, 0, time.UTC), time.Date(2026, 10, 22, 14, 45, 0, 0, time.UTC), []string{"review"}},
+		{"end before start", "standup", "user-1", `{"startTime": "2026-10-21T10:00:00Z", "endTime": "2026-10-21T09:00:00Z"}`, http.StatusBadRequest, time.Time{}, time.Time{}, nil},
+		{"start missing", "standup", "user-1", `{}`, http.StatusBadRequest, time.Time{}, time.Time{}, nil},
+		{"completed meeting", "done", "user-1", `{"startTime": "2026-10-21T10:00:00Z"}`, http.StatusConflict, time.Time{}, time.Time{}, nil},
+		{"cancelled meeting", "called-off", "user-1", `{"startTime": "2026-10-21T10:00:00Z"}`, http.StatusConflict, time.Time{}, time.Time{}, nil},
+		{"someone else's meeting", "standup", "user-2", `{"startTime": "2026-10-21T10:00:00Z"}`, http.StatusNotFound, time.Time{}, time.Time{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.meetings["standup"] = &models.Meeting{ID: "standup", UserID: "user-1", Status: "scheduled", StartTime: start, EndTime: start.Add(30 * time.Minute), RescheduleCount: 2}
+			store.meetings["review"] = &models.Meeting{ID: "review", UserID: "user-1", Status: "scheduled", StartTime: time.Date(2026, 10, 22, 14, 0, 0, 0, time.UTC), EndTime: time.Date(2026, 10, 22, 15, 0, 0, 0, time.UTC)}
+			store.meetings["done"] = &models.Meeting{ID: "done", UserID: "user-1", Status: "completed", StartTime: start, EndTime: start.Add(time.Hour)}
+			store.meetings["called-off"] = &models.Meeting{ID: "called-off", UserID: "user-1", Status: "cancelled", StartTime: start, EndTime: start.Add(time.Hour)}
+			h := NewMeetingHandler(store, nil, nil, testConfig(now))
+
+			w := serve(h.RescheduleMeeting, http.MethodPatch, "/meetings/:id/reschedule", "/meetings/"+tt.meetingID+"/reschedule", tt.body, tt.userID)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want != http.StatusOK {
+				if len(store.updates) != 0 {
+					t.Errorf("updates = %v, want none", store.updates)
+				}
+				return
+			}
+
+			if len(store.updates) != 1 {
+				t.Fatalf("updates = %v, want one", store.updates)
+			}
+			update := store.updates[0]
+			if !update["startTime"].(time.Time).Equal(tt.wantStart) || !update["endTime"].(time.Time).Equal(tt.wantEnd) || update["rescheduleCount"] != 3 {
+				t.Errorf("update = %v, want %v to %v, third reschedule", update, tt.wantStart, tt.wantEnd)
+			}
+			var response struct {
+				Conflicts []string `json:"conflicts"`
+			}
+			decode(t, w, &response)
+			if !slices.Equal(response.Conflicts, tt.wantConflicts) {
+				t.Errorf("conflicts = %v, want %v", response.Conflicts, tt.wantConflicts)
+			}
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"focusflow-be/internal/clock"
+	"focusflow-be/internal/config"
+	"focusflow-be/internal/models"
+	"focusflow-be/internal/services"
+)
+
+// TestMain registers the custom binding tags, as main does, before any
+// request body is bound
+func TestMain(m *testing.M) {
+	if err := RegisterValidators(); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// mockStore keeps items in memory. It embeds services.Store so tests only
+// implement what the handler under test calls; anything else panics.
+type mockStore struct {
+	services.Store
+
+	users            map[string]*models.UserSession
+	tasks            map[string]*models.Task
+	meetings         map[string]*models.Meeting
+	reminders        map[string]*models.Reminder
+	snapshots        map[string]*models.OverviewSnapshot // by token
+	created          []*models.Meeting
+	createdReminders []*models.Reminder
+	updates          []map[string]interface{}            // every update written, in order
+	batches          [][]interface{}                     // items passed to BatchCreate, one call each
+	taskBatch        []map[string]map[string]interface{} // every BatchUpdateTasks call
+	reminderBatch    []map[string]map[string]interface{} // every BatchUpdateReminders call
+	badgeDays        []time.Time                         // dayStart of every badge count
+	completed        [][2]time.Time                      // from and to of every completed-tasks query
+
+	// Errors returned when listing or creating in a collection, keyed by its name
+	fail map[string]error
+}
+
+func newMockStore() *mockStore {
+	return &mockStore{
+		users:     make(map[string]*models.UserSession),
+		tasks:     make(map[string]*models.Task),
+		meetings:  make(map[string]*models.Meeting),
+		reminders: make(map[string]*models.Reminder),
+		snapshots: make(map[string]*models.OverviewSnapshot),
+		fail:      make(map[string]error),
+	}
+}
+
+func (m *mockStore) WithContext(context.Context) services.Store {
+	return m
+}
+
+func (m *mockStore) GetUser(userID string) (*models.UserSession, error) {
+	user, ok := m.users[userID]
+	if !ok {
+		return nil, services.ErrNotFound
+	}
+	return user, nil
+}
+
+func (m *mockStore) GetTasks(userID string) ([]*models.Task, error) {
+	if err := m.fail["tasks"]; err != nil {
+		return nil, err
+	}
+	tasks := []*models.Task{}
+	for _, task := range m.tasks {
+		if task.UserID == userID {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (m *mockStore) GetMeetings(userID string) ([]*models.Meeting, error) {
+	if err := m.fail["meetings"]; err != nil {
+		return nil, err
+	}
+	meetings := []*models.Meeting{}
+	for _, meeting := range m.meetings {
+		if meeting.UserID == userID {
+			meetings = append(meetings, meeting)
+		}
+	}
+	return meetings, nil
+}
+
+func (m *mockStore) GetReminders(userID string) ([]*models.Reminder, error) {
+	if err := m.fail["reminders"]; err != nil {
+		return nil, err
+	}
+	reminders := []*models.Reminder{}
+	for _, reminder := range m.reminders {
+		if reminder.UserID == userID {
+			reminders = append(reminders, reminder)
+		}
+	}
+	return reminders, nil
+}
+
+// GetTasksCompletedBetween returns the user's tasks completed in [from, to],
+// oldest first, starting after the cursor
+func (m *mockStore) GetTasksCompletedBetween(userID string, from, to time.Time, limit int, after *services.PageCursor) ([]*models.Task, error) {
+	m.completed = append(m.completed, [2]time.Time{from, to})
+	var tasks []*models.Task
+	for _, task := range m.tasks {
+		if task.UserID != userID || task.CompletedAt == nil || task.CompletedAt.Before(from) || task.CompletedAt.After(to) {
+			continue
+		}
+		if after != nil && !task.CompletedAt.After(after.Value) {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].CompletedAt.Before(*tasks[j].CompletedAt) })
+	if limit > 0 && len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+	return tasks, nil
+}
+
+func (m *mockStore) CreateTask(task *models.Task) (string, error) {
+	task.ID = fmt.Sprintf("task-%d", len(m.tasks)+1)
+	m.tasks[task.ID] = task
+	return task.ID, nil
+}
+
+func (m *mockStore) GetTask(taskID string) (*models.Task, error) {
+	task, ok := m.tasks[taskID]
+	if !ok {
+		return nil, services.ErrNotFound
+	}
+	return task, nil
+}
+
+func (m *mockStore) UpdateTask(taskID string, updates map[string]interface{}) error {
+	if _, ok := m.tasks[taskID]; !ok {
+		return services.ErrNotFound
+	}
+	m.updates = append(m.updates, updates)
+	return nil
+}
+
+func (m *mockStore) BatchUpdateTasks(updates map[string]map[string]interface{}) error {
+	for taskID := range updates {
+		if _, ok := m.tasks[taskID]; !ok {
+			return services.ErrNotFound
+		}
+	}
+	m.taskBatch = append(m.taskBatch, updates)
+	return nil
+}
+
+func (m *mockStore) GetMeeting(meetingID string) (*models.Meeting, error) {
+	meeting, ok := m.meetings[meetingID]
+	if !ok {
+		return nil, services.ErrNotFound
+	}
+	return meeting, nil
+}
+
+func (m *mockStore) CreateMeeting(meeting *models.Meeting) (string, error) {
+	meeting.ID = fmt.Sprintf("meeting-%d", len(m.created)+1)
+	m.created = append(m.created, meeting)
+	m.meetings[meeting.ID] = meeting
+	return meeting.ID, nil
+}
+
+func (m *mockStore) UpdateMeeting(meetingID string, updates map[string]interface{}) error {
+	if _, ok := m.meetings[meetingID]; !ok {
+		return services.ErrNotFound
+	}
+	m.updates = append(m.updates, updates)
+	return nil
+}
+
+func (m *mockStore) BatchDeleteTasks(tasks []*models.Task) error {
+	for _, task := range tasks {
+		delete(m.tasks, task.ID)
+	}
+	return nil
+}
+
+func (m *mockStore) BatchDeleteReminders(reminders []*models.Reminder) error {
+	for _, reminder := range reminders {
+		delete(m.reminders, reminder.ID)
+	}
+	return nil
+}
+
+func (m *mockStore) GetReminder(reminderID string) (*models.Reminder, error) {
+	reminder, ok := m.reminders[reminderID]
+	if !ok {
+		return nil, services.ErrNotFound
+	}
+	return reminder, nil
+}
+
+func (m *mockStore) CreateReminder(reminder *models.Reminder) (string, error) {
+	reminder.ID = fmt.Sprintf("reminder-%d", len(m.createdReminders)+1)
+	m.createdReminders = append(m.createdReminders, reminder)
+	m.reminders[reminder.ID] = reminder
+	return reminder.ID, nil
+}
+
+func (m *mockStore) UpdateReminder(reminderID string, updates map[string]interface{}) error {
+	if _, ok := m.reminders[reminderID]; !ok {
+		return services.ErrNotFound
+	}
+	m.updates = append(m.updates, updates)
+	return nil
+}
+
+func (m *mockStore) BatchUpdateReminders(updates map[string]map[string]interface{}) error {
+	for reminderID := range updates {
+		if _, ok := m.reminders[reminderID]; !ok {
+			return services.ErrNotFound
+		}
+	}
+	m.reminderBatch = append(m.reminderBatch, updates)
+	return nil
+}
+
+func (m *mockStore) BatchCreate(collection string, items []interface{}) ([]string, error) {
+	if err := m.fail[collection]; err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(items))
+	for i := range items {
+		ids[i] = fmt.Sprintf("%s-new-%d", collection, i+1)
+	}
+	m.batches = append(m.batches, items)
+	return ids, nil
+}
+
+func (m *mockStore) CreateOverviewSnapshot(snapshot *models.OverviewSnapshot) (string, error) {
+	snapshot.Token = fmt.Sprintf("token-%d", len(m.snapshots)+1)
+	m.snapshots[snapshot.Token] = snapshot
+	return snapshot.Token, nil
+}
+
+func (m *mockStore) GetOverviewSnapshot(token string) (*models.OverviewSnapshot, error) {
+	snapshot, ok := m.snapshots[token]
+	if !ok {
+		return nil, services.ErrNotFound
+	}
+	return snapshot, nil
+}
+
+func (m *mockStore) GetBadgeCounts(userID string, dayStart, dayEnd, tasksDueBefore, remindersDueBefore time.Time) (*models.BadgeCounts, error) {
+	m.badgeDays = append(m.badgeDays, dayStart)
+
+	var counts models.BadgeCounts
+	for _, task := range m.tasks {
+		if task.UserID == userID && task.Status != "completed" && task.DueDate != nil && task.DueDate.Before(tasksDueBefore) {
+			counts.Overdue++
+		}
+	}
+	for _, reminder := range m.reminders {
+		if reminder.UserID == userID && !reminder.IsCompleted && reminder.ReminderTime.Before(remindersDueBefore) {
+			counts.Overdue++
+		}
+	}
+	return &counts, nil
+}
+
+// testConfig is the configuration handlers get in tests, with the clock fixed
+// at now
+func testConfig(now time.Time) *config.Config {
+	return &config.Config{
+		Clock:                  clock.NewFake(now),
+		MeetingMinDuration:     time.Minute,
+		MeetingMaxDuration:     24 * time.Hour,
+		MeetingPastTolerance:   24 * time.Hour,
+		MeetingDefaultDuration: 30 * time.Minute,
+	}
+}
+
+// serve runs handler for one request made as userID and returns the recorder
+func serve(handler gin.HandlerFunc, method, route, path, body, userID string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Handle(method, route, func(c *gin.Context) {
+		c.Set("user", &models.UserSession{UserID: userID})
+		handler(c)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
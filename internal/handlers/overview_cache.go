@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"focusflow-be/internal/clock"
+	"focusflow-be/internal/models"
+)
+
+// overviewCache keeps each user's computed overview for ttl so dashboard
+// polling doesn't rescan every task, meeting and reminder. A nil cache is
+// disabled: lookups miss and invalidation does nothing.
+type overviewCache struct {
+	ttl   time.Duration
+	clock clock.Clock
+
+	mu      sync.Mutex
+	entries map[string]cachedOverview // userID -> overview
+}
+
+type cachedOverview struct {
+	overview models.Overview
+	expiry   time.Time
+}
+
+func newOverviewCache(ttl time.Duration, clk clock.Clock) *overviewCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &overviewCache{
+		ttl:     ttl,
+		clock:   clk,
+		entries: make(map[string]cachedOverview),
+	}
+}
+
+func (o *overviewCache) get(userID string) (models.Overview, bool) {
+	if o == nil {
+		return models.Overview{}, false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entry, ok := o.entries[userID]
+	if !ok {
+		return models.Overview{}, false
+	}
+	if !o.clock.Now().Before(entry.expiry) {
+		delete(o.entries, userID)
+		return models.Overview{}, false
+	}
+	return entry.overview, true
+}
+
+func (o *overviewCache) put(userID string, overview models.Overview) {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	// Users who stopped polling would otherwise stay in the map for good
+	now := o.clock.Now()
+	for id, entry := range o.entries {
+		if !now.Before(entry.expiry) {
+			delete(o.entries, id)
+		}
+	}
+	o.entries[userID] = cachedOverview{overview: overview, expiry: now.Add(o.ttl)}
+}
+
+// forget drops userID's overview, or every user's when userID is empty.
+func (o *overviewCache) forget(userID string) {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if userID == "" {
+		o.entries = make(map[string]cachedOverview)
+		return
+	}
+	delete(o.entries, userID)
+}
+
+// InvalidateOverviews returns middleware that drops cached overviews after
+// any successful write. Writes made with a user's token only touch that
+// user's items; admin writes such as the reminder carry-forward span users,
+// so they clear the whole cache.
+func (h *DashboardHandler) InvalidateOverviews() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return
+		}
+		if c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+
+		if user, exists := c.Get("user"); exists {
+			h.overviews.forget(user.(*models.UserSession).UserID)
+			return
+		}
+		h.overviews.forget("")
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"focusflow-be/internal/models"
+	"focusflow-be/internal/services"
+)
+
+// ShareOverview freezes the current overview into a snapshot anyone with the
+// returned link can read until it expires. Only the counts are copied, so
+// the link reveals nothing about the user or their items. A partial overview
+// isn't shared, as its zeroed counts would read as real ones.
+func (h *DashboardHandler) ShareOverview(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	var req models.ShareOverviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	ttl := h.config.OverviewShareTTL
+	if req.ExpiresIn != nil {
+		expiresIn, err := parseRelativeDuration(*req.ExpiresIn)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expiresIn, expected e.g. 12h, 3d or 1w", "details": err.Error()})
+			return
+		}
+		if expiresIn > ttl {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("expiresIn may be at most %gh", ttl.Hours())})
+			return
+		}
+		ttl = expiresIn
+	}
+
+	overview, failed := h.computeOverview(c, userSession.UserID)
+	if len(failed) > 0 {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to load all collections, so the overview wasn't shared", "errors": failed})
+		return
+	}
+
+	now := h.config.Clock.Now()
+	snapshot := &models.OverviewSnapshot{
+		UserID: userSession.UserID,
+		Overview: models.Overview{
+			Tasks:     overview.Tasks,
+			Meetings:  overview.Meetings,
+			Reminders: overview.Reminders,
+		},
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	token, err := h.store(c).CreateOverviewSnapshot(snapshot)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to share overview", "details": err.Error()})
+		return
+	}
+
+	// Railway always serves over HTTPS, as in the OAuth callback
+	path := "/shared/overview/" + token
+	c.JSON(http.StatusCreated, gin.H{
+		"token":     token,
+		"path":      path,
+		"url":       "https://" + c.Request.Host + path,
+		"createdAt": snapshot.CreatedAt,
+		"expiresAt": snapshot.ExpiresAt,
+	})
+}
+
+// GetSharedOverview serves a shared snapshot without authentication. Unknown
+// tokens are 404 and expired ones 410, whether or not Firestore has deleted
+// them yet.
+func (h *DashboardHandler) GetSharedOverview(c *gin.Context) {
+	snapshot, err := h.store(c).GetOverviewSnapshot(c.Param("token"))
+	if errors.Is(err, services.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shared overview not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch shared overview", "details": err.Error()})
+		return
+	}
+	if !h.config.Clock.Now().Before(snapshot.ExpiresAt) {
+		c.JSON(http.StatusGone, gin.H{"error": "Shared overview has expired"})
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshot)
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"focusflow-be/internal/clock"
+	"focusflow-be/internal/models"
+)
+
+func TestShareOverview(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	yesterday := now.AddDate(0, 0, -1)
+
+	tests := []struct {
+		name        string
+		body        string
+		fail        string // collection that fails to load
+		want        int
+		wantExpires time.Time
+	}{
+		{"default TTL", "", "", http.StatusCreated, now.Add(72 * time.Hour)},
+		{"shorter expiry", `{"expiresIn": "12h"}`, "", http.StatusCreated, now.Add(12 * time.Hour)},
+		{"expiry past the TTL", `{"expiresIn": "1w"}`, "", http.StatusBadRequest, time.Time{}},
+		{"invalid expiry", `{"expiresIn": "soon"}`, "", http.StatusBadRequest, time.Time{}},
+		{"partial overview", "", "meetings", http.StatusServiceUnavailable, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.tasks["late"] = &models.Task{ID: "late", UserID: "user-1", Title: "Confidential merger memo", Status: "todo", Priority: "high", DueDate: &yesterday}
+			store.tasks["done"] = &models.Task{ID: "done", UserID: "user-1", Title: "Payroll", Status: "completed"}
+			if tt.fail != "" {
+				store.fail[tt.fail] = errors.New("unavailable")
+			}
+			cfg := testConfig(now)
+			cfg.OverviewShareTTL = 72 * time.Hour
+			h := NewDashboardHandler(store, nil, cfg)
+
+			w := serve(h.ShareOverview, http.MethodPost, "/dashboard/overview/share", "/dashboard/overview/share", tt.body, "user-1")
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want != http.StatusCreated {
+				if len(store.snapshots) != 0 {
+					t.Errorf("snapshots = %v, want none", store.snapshots)
+				}
+				return
+			}
+
+			var body struct {
+				Token     string    `json:"token"`
+				Path      string    `json:"path"`
+				ExpiresAt time.Time `json:"expiresAt"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			snapshot, ok := store.snapshots[body.Token]
+			if !ok {
+				t.Fatalf("token %q not stored", body.Token)
+			}
+			if body.Path != "/shared/overview/"+body.Token || !body.ExpiresAt.Equal(tt.wantExpires) || !snapshot.ExpiresAt.Equal(tt.wantExpires) {
+				t.Errorf("path %s expiring %v (stored %v), want /shared/overview/%s expiring %v", body.Path, body.ExpiresAt, snapshot.ExpiresAt, body.Token, tt.wantExpires)
+			}
+			if snapshot.UserID != "user-1" || !snapshot.CreatedAt.Equal(now) {
+				t.Errorf("snapshot by %q at %v, want user-1 at %v", snapshot.UserID, snapshot.CreatedAt, now)
+			}
+			if got := snapshot.Overview.Tasks; got.Total != 2 || got.Completed != 1 || got.Overdue != 1 {
+				t.Errorf("task counts = %+v, want 2 total, 1 completed, 1 overdue", got)
+			}
+		})
+	}
+}
+
+func TestGetSharedOverview(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	store := newMockStore()
+	store.snapshots["shared"] = &models.OverviewSnapshot{
+		Token:     "shared",
+		UserID:    "user-1",
+		Overview:  models.Overview{Tasks: models.TaskOverview{Total: 4, Overdue: 1}},
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+	cfg := testConfig(now)
+	h := NewDashboardHandler(store, nil, cfg)
+	get := func(token string) (int, map[string]interface{}) {
+		// The handler never looks at the user: the link is public
+		w := serve(h.GetSharedOverview, http.MethodGet, "/shared/overview/:token", "/shared/overview/"+token, "", "")
+		var body map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		return w.Code, body
+	}
+
+	code, body := get("shared")
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %v", code, http.StatusOK, body)
+	}
+	for key := range body {
+		if key != "overview" && key != "createdAt" && key != "expiresAt" {
+			t.Errorf("shared overview has %q, want only the counts and times", key)
+		}
+	}
+	if tasks := body["overview"].(map[string]interface{})["tasks"].(map[string]interface{}); tasks["total"] != 4.0 {
+		t.Errorf("tasks = %v, want the snapshot's counts", tasks)
+	}
+
+	// Expired snapshots are gone even before Firestore's TTL deletes them
+	cfg.Clock.(*clock.Fake).Advance(time.Hour)
+	if code, _ := get("shared"); code != http.StatusGone {
+		t.Errorf("at expiry: status = %d, want %d", code, http.StatusGone)
+	}
+	if code, _ := get("unknown"); code != http.StatusNotFound {
+		t.Errorf("unknown token: status = %d, want %d", code, http.StatusNotFound)
+	}
+}
@@ -1,24 +1,31 @@
 package handlers
 
 import (
+	"log"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"focusflow-be/internal/config"
 	"focusflow-be/internal/models"
+	"focusflow-be/internal/recurrence"
 	"focusflow-be/internal/services"
 )
 
 type ReminderHandler struct {
-	firebaseService *services.FirebaseService
-	authService     *services.AuthService
+	requestStore
+	authService   *services.AuthService
+	googleService *services.GoogleService
+	config        *config.Config
 }
 
-func NewReminderHandler(firebaseService *services.FirebaseService, authService *services.AuthService) *ReminderHandler {
+func NewReminderHandler(firebaseService services.Store, authService *services.AuthService, googleService *services.GoogleService, cfg *config.Config) *ReminderHandler {
 	return &ReminderHandler{
-		firebaseService: firebaseService,
-		authService:     authService,
+		requestStore:  requestStore{firebaseService},
+		authService:   authService,
+		googleService: googleService,
+		config:        cfg,
 	}
 }
 
@@ -30,13 +37,13 @@ func (h *ReminderHandler) GetReminders(c *gin.Context) {
 	}
 
 	userSession := user.(*models.UserSession)
-	reminders, err := h.firebaseService.GetReminders(userSession.UserID)
+	reminders, err := h.store(c).GetReminders(userSession.UserID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reminders", "details": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, reminders)
+	respondData(c, http.StatusOK, reminders)
 }
 
 func (h *ReminderHandler) CreateReminder(c *gin.Context) {
@@ -62,9 +69,38 @@ func (h *ReminderHandler) CreateReminder(c *gin.Context) {
 		ReminderType: req.ReminderType,
 		IsCompleted:  false,
 		Priority:     req.Priority,
+		Recurrence:   req.Recurrence,
+		TaskID:       req.TaskID,
 	}
 
-	reminderID, err := h.firebaseService.CreateReminder(reminder)
+	if req.Recurrence != nil {
+		if err := req.Recurrence.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if h.isPastReminderTime(req.ReminderTime) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reminderTime is in the past"})
+		return
+	}
+
+	if req.TaskID != nil {
+		if req.ReminderType != "task" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "taskId is only allowed on task reminders"})
+			return
+		}
+		task, err := h.store(c).GetTask(*req.TaskID)
+		if err == nil {
+			err = checkOwnership(task, userSession.UserID)
+		}
+		if err != nil {
+			respondResourceError(c, "Task", err)
+			return
+		}
+	}
+
+	reminderID, err := h.store(c).CreateReminder(reminder)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create reminder", "details": err.Error()})
 		return
@@ -76,6 +112,66 @@ func (h *ReminderHandler) CreateReminder(c *gin.Context) {
 	})
 }
 
+// isPastReminderTime reports whether t is too far in the past for a reminder
+// to fire. A reminder set for a moment ago, within ReminderPastTolerance, is
+// still accepted and fires straight away.
+func (h *ReminderHandler) isPastReminderTime(t time.Time) bool {
+	return t.Before(h.config.Clock.Now().Add(-h.config.ReminderPastTolerance))
+}
+
+func (h *ReminderHandler) UpdateReminder(c *gin.Context) {
+	reminderID := c.Param("id")
+	if reminderID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Reminder ID is required"})
+		return
+	}
+
+	if _, ok := h.loadOwnedReminder(c, reminderID); !ok {
+		return
+	}
+
+	var req models.UpdateReminderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.Title != nil {
+		updates["title"] = *req.Title
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.ReminderTime != nil {
+		if h.isPastReminderTime(*req.ReminderTime) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "reminderTime is in the past"})
+			return
+		}
+		updates["reminderTime"] = *req.ReminderTime
+	}
+	if req.ReminderType != nil {
+		updates["reminderType"] = *req.ReminderType
+	}
+	if req.Priority != nil {
+		updates["priority"] = *req.Priority
+	}
+	if err := applyClearFields(updates, req.ClearFields); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.store(c).UpdateReminder(reminderID, updates); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reminder", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reminder updated successfully"})
+}
+
+// CompleteReminder marks a reminder done. For a repeating reminder this ends
+// the series: no further occurrence is created. Use DismissReminder to
+// acknowledge one occurrence and keep the series going.
 func (h *ReminderHandler) CompleteReminder(c *gin.Context) {
 	reminderID := c.Param("id")
 	if reminderID == "" {
@@ -83,15 +179,281 @@ func (h *ReminderHandler) CompleteReminder(c *gin.Context) {
 		return
 	}
 
+	if _, ok := h.loadOwnedReminder(c, reminderID); !ok {
+		return
+	}
+
 	updates := map[string]interface{}{
 		"isCompleted": true,
-		"completedAt": time.Now(),
+		"completedAt": h.config.Clock.Now(),
 	}
 
-	if err := h.firebaseService.UpdateReminder(reminderID, updates); err != nil {
+	if err := h.store(c).UpdateReminder(reminderID, updates); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete reminder", "details": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Reminder marked as completed"})
-}
\ No newline at end of file
+}
+
+// DismissReminder acknowledges the current occurrence of a reminder. It's
+// marked done like on complete, but a repeating reminder also gets its next
+// occurrence created, unless this was the last one. The series repeats on the
+// wall clock of the owner's time zone.
+func (h *ReminderHandler) DismissReminder(c *gin.Context) {
+	reminderID := c.Param("id")
+	if reminderID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Reminder ID is required"})
+		return
+	}
+
+	reminder, ok := h.loadOwnedReminder(c, reminderID)
+	if !ok {
+		return
+	}
+	if reminder.IsCompleted {
+		c.JSON(http.StatusConflict, gin.H{"error": "Reminder is already completed"})
+		return
+	}
+
+	var next *models.Reminder
+	if reminder.Recurrence != nil {
+		loc := time.UTC
+		if profile, err := h.store(c).GetUser(reminder.UserID); err == nil {
+			if userLoc, err := recurrence.LoadLocation(profile.Timezone); err == nil {
+				loc = userLoc
+			}
+		}
+
+		nextTime, rest, ok, err := recurrence.Next(reminder.ReminderTime, *reminder.Recurrence, loc)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Reminder has an invalid recurrence", "details": err.Error()})
+			return
+		}
+		if ok {
+			next = &models.Reminder{
+				UserID:       reminder.UserID,
+				Title:        reminder.Title,
+				Description:  reminder.Description,
+				ReminderTime: nextTime,
+				ReminderType: reminder.ReminderType,
+				Priority:     reminder.Priority,
+				Recurrence:   &rest,
+			}
+		}
+	}
+
+	updates := map[string]interface{}{
+		"isCompleted": true,
+		"completedAt": h.config.Clock.Now(),
+	}
+	if err := h.store(c).UpdateReminder(reminderID, updates); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to dismiss reminder", "details": err.Error()})
+		return
+	}
+
+	response := gin.H{"message": "Reminder dismissed"}
+	if next != nil {
+		nextID, err := h.store(c).CreateReminder(next)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Reminder dismissed but failed to create the next occurrence", "details": err.Error()})
+			return
+		}
+		response["next"] = gin.H{"id": nextID, "reminderTime": next.ReminderTime}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CompleteReminders marks several reminders completed in one batched write.
+// IDs the caller doesn't own are reported as not found, and reminders that
+// are already completed are left untouched.
+func (h *ReminderHandler) CompleteReminders(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	var req models.BulkReminderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	// Read every reminder in one round-trip; IDs missing from the result don't exist
+	reminders, err := h.store(c).GetRemindersByIDs(req.IDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reminders", "details": err.Error()})
+		return
+	}
+	byID := make(map[string]*models.Reminder, len(reminders))
+	for _, reminder := range reminders {
+		byID[reminder.ID] = reminder
+	}
+
+	now := h.config.Clock.Now()
+	results := make([]*models.BulkResult, 0, len(req.IDs))
+	updates := make(map[string]map[string]interface{})
+	for _, reminderID := range req.IDs {
+		result := &models.BulkResult{ID: reminderID}
+		results = append(results, result)
+
+		if _, seen := updates[reminderID]; seen {
+			result.Status = "completed"
+			continue
+		}
+
+		reminder := byID[reminderID]
+		switch {
+		case checkOwnership(reminder, userSession.UserID) != nil:
+			result.Status = "not-found"
+		case reminder.IsCompleted:
+			result.Status = "already-completed"
+		default:
+			result.Status = "completed"
+			updates[reminderID] = map[string]interface{}{
+				"isCompleted": true,
+				"completedAt": now,
+			}
+		}
+	}
+
+	if err := h.store(c).BatchUpdateReminders(updates); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete reminders", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Reminders processed",
+		"completed": len(updates),
+		"results":   results,
+	})
+}
+
+// RescheduleOverdue moves every pending reminder past its time (beyond
+// OVERDUE_GRACE) forward by shift, or all to one absolute time, in a single
+// batched write. Calendar events of the moved reminders are moved after the
+// write; failures there are reported but don't undo it.
+func (h *ReminderHandler) RescheduleOverdue(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	var req models.RescheduleRemindersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	// Exactly one rescheduling mode must be supplied
+	if (req.Shift == nil) == (req.To == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Provide exactly one of shift or to"})
+		return
+	}
+
+	var shift time.Duration
+	if req.Shift != nil {
+		var err error
+		if shift, err = parseRelativeDuration(*req.Shift); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shift, expected e.g. 24h, 2d or 1w", "details": err.Error()})
+			return
+		}
+	}
+	if req.To != nil && h.isPastReminderTime(*req.To) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to is in the past"})
+		return
+	}
+
+	reminders, err := h.store(c).GetReminders(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reminders", "details": err.Error()})
+		return
+	}
+
+	now := h.config.Clock.Now()
+	updates := make(map[string]map[string]interface{})
+	rescheduled := []string{}
+	var moved []*models.Reminder
+	for _, reminder := range reminders {
+		if reminder.IsCompleted || !reminder.ReminderTime.Add(h.config.OverdueGrace).Before(now) {
+			continue
+		}
+
+		newTime := reminder.ReminderTime.Add(shift)
+		if req.To != nil {
+			newTime = *req.To
+		}
+
+		updates[reminder.ID] = map[string]interface{}{"reminderTime": newTime}
+		rescheduled = append(rescheduled, reminder.ID)
+		if reminder.GoogleEventID != nil {
+			reminder.ReminderTime = newTime
+			moved = append(moved, reminder)
+		}
+	}
+
+	if err := h.store(c).BatchUpdateReminders(updates); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reschedule reminders", "details": err.Error()})
+		return
+	}
+
+	response := gin.H{
+		"rescheduled": rescheduled,
+		"message":     "Overdue reminders rescheduled successfully",
+	}
+	if failed := h.moveCalendarReminders(c, userSession.UserID, moved); len(failed) > 0 {
+		response["calendarErrors"] = failed
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// moveCalendarReminders moves the calendar events of reminders whose time has
+// changed to their new ReminderTime, returning the ones that failed
+func (h *ReminderHandler) moveCalendarReminders(c *gin.Context, userID string, reminders []*models.Reminder) []models.SyncFailure {
+	if len(reminders) == 0 {
+		return nil
+	}
+
+	var failed []models.SyncFailure
+	profile, err := h.store(c).GetUser(userID)
+	for _, reminder := range reminders {
+		if err == nil {
+			start := reminder.ReminderTime
+			err = h.googleService.MoveCalendarEvent(h.googleService.UserToken(profile), *reminder.GoogleEventID, start, start.Add(services.ReminderEventLength), "none")
+		}
+		if err != nil {
+			log.Printf("⚠️ Failed to move calendar event for reminder %s: %v", reminder.ID, err)
+			failed = append(failed, models.SyncFailure{ID: reminder.ID, Error: err.Error()})
+		}
+	}
+	return failed
+}
+
+// loadOwnedReminder fetches a reminder owned by the current user, writing the
+// ownership policy's error response when that fails
+func (h *ReminderHandler) loadOwnedReminder(c *gin.Context, reminderID string) (*models.Reminder, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return nil, false
+	}
+
+	userSession := user.(*models.UserSession)
+	reminder, err := h.store(c).GetReminder(reminderID)
+	if err == nil {
+		err = checkOwnership(reminder, userSession.UserID)
+	}
+	if err != nil {
+		respondResourceError(c, "Reminder", err)
+		return nil, false
+	}
+
+	return reminder, true
+}
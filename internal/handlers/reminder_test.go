@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"focusflow-be/internal/models"
+	"focusflow-be/internal/recurrence"
+)
+
+func TestDismissVersusComplete(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		action     string
+		reminderID string
+		userID     string
+		want       int
+		wantNext   *recurrence.Rule // rule of the created occurrence, nil for none
+	}{
+		{"dismiss continues the series", "dismiss", "daily", "user-1", http.StatusOK, &recurrence.Rule{Frequency: recurrence.Daily, Count: 2}},
+		{"complete ends the series", "complete", "daily", "user-1", http.StatusOK, nil},
+		{"dismiss the last occurrence", "dismiss", "last", "user-1", http.StatusOK, nil},
+		{"dismiss a one-off", "dismiss", "once", "user-1", http.StatusOK, nil},
+		{"dismiss an already completed reminder", "dismiss", "done", "user-1", http.StatusConflict, nil},
+		{"dismiss someone else's reminder", "dismiss", "daily", "user-2", http.StatusNotFound, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.reminders["daily"] = &models.Reminder{ID: "daily", UserID: "user-1", Title: "Stand up", ReminderTime: due, Recurrence: &recurrence.Rule{Frequency: recurrence.Daily, Count: 3}}
+			store.reminders["last"] = &models.Reminder{ID: "last", UserID: "user-1", Title: "Stand up", ReminderTime: due, Recurrence: &recurrence.Rule{Frequency: recurrence.Daily, Count: 1}}
+			store.reminders["once"] = &models.Reminder{ID: "once", UserID: "user-1", Title: "Call", ReminderTime: due}
+			store.reminders["done"] = &models.Reminder{ID: "done", UserID: "user-1", Title: "Call", ReminderTime: due, IsCompleted: true}
+			h := NewReminderHandler(store, nil, nil, testConfig(now))
+
+			handler := h.DismissReminder
+			if tt.action == "complete" {
+				handler = h.CompleteReminder
+			}
+			w := serve(handler, http.MethodPatch, "/reminders/:id/"+tt.action, "/reminders/"+tt.reminderID+"/"+tt.action, "", tt.userID)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want != http.StatusOK {
+				if len(store.updates) != 0 || len(store.createdReminders) != 0 {
+					t.Errorf("updates = %v, created = %v; want neither", store.updates, store.createdReminders)
+				}
+				return
+			}
+
+			if len(store.updates) != 1 || store.updates[0]["isCompleted"] != true {
+				t.Errorf("updates = %v, want the occurrence marked done", store.updates)
+			}
+			if tt.wantNext == nil {
+				if len(store.createdReminders) != 0 {
+					t.Errorf("created %+v, want no next occurrence", store.createdReminders[0])
+				}
+				return
+			}
+			if len(store.createdReminders) != 1 {
+				t.Fatalf("created %d reminders, want the next occurrence", len(store.createdReminders))
+			}
+			next := store.createdReminders[0]
+			if !next.ReminderTime.Equal(due.AddDate(0, 0, 1)) || next.IsCompleted || next.Recurrence == nil || *next.Recurrence != *tt.wantNext {
+				t.Errorf("next occurrence = %+v (rule %+v), want a day later with %+v", next, next.Recurrence, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestReminderTimeInPast(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		time      time.Time
+		tolerance time.Duration
+		want      bool // accepted
+	}{
+		{"future", now.Add(time.Hour), time.Minute, true},
+		{"now", now, time.Minute, true},
+		{"a moment ago, within tolerance", now.Add(-30 * time.Second), time.Minute, true},
+		{"right at the tolerance", now.Add(-time.Minute), time.Minute, true},
+		{"past the tolerance", now.Add(-2 * time.Minute), time.Minute, false},
+		{"yesterday", now.AddDate(0, 0, -1), time.Minute, false},
+		{"a moment ago, no tolerance", now.Add(-time.Second), 0, false},
+		{"now, no tolerance", now, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := tt.time.Format(time.RFC3339)
+			requests := []struct {
+				action string
+				serve  func(h *ReminderHandler) *httptest.ResponseRecorder
+				ok     int
+			}{
+				{"create", func(h *ReminderHandler) *httptest.ResponseRecorder {
+					body := `{"title": "Call", "reminderTime": "` + value + `", "reminderType": "personal", "priority": "low"}`
+					return serve(h.CreateReminder, http.MethodPost, "/reminders", "/reminders", body, "user-1")
+				}, http.StatusCreated},
+				{"update", func(h *ReminderHandler) *httptest.ResponseRecorder {
+					body := `{"reminderTime": "` + value + `"}`
+					return serve(h.UpdateReminder, http.MethodPut, "/reminders/:id", "/reminders/call", body, "user-1")
+				}, http.StatusOK},
+			}
+			for _, r := range requests {
+				store := newMockStore()
+				store.reminders["call"] = &models.Reminder{ID: "call", UserID: "user-1", Title: "Call", ReminderTime: now.Add(time.Hour)}
+				cfg := testConfig(now)
+				cfg.ReminderPastTolerance = tt.tolerance
+				h := NewReminderHandler(store, nil, nil, cfg)
+
+				want := http.StatusBadRequest
+				if tt.want {
+					want = r.ok
+				}
+				w := r.serve(h)
+				if w.Code != want {
+					t.Errorf("%s: status = %d, want %d: %s", r.action, w.Code, want, w.Body.String())
+				}
+				if written := len(store.createdReminders)+len(store.updates) > 0; written != tt.want {
+					t.Errorf("%s: written = %v, want %v", r.action, written, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRescheduleOverdueReminders(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	eventID := "event-1"
+	to := now.Add(3 * time.Hour)
+
+	tests := []struct {
+		name     string
+		body     string
+		want     int
+		wantTime map[string]time.Time // new time of each rescheduled reminder
+	}{
+		{"shift by hours", `{"shift": "24h"}`, http.StatusOK, map[string]time.Time{
+			"overdue": now.Add(-2 * time.Hour).Add(24 * time.Hour),
+			"linked":  now.Add(-3 * time.Hour).Add(24 * time.Hour),
+		}},
+		{"shift by days", `{"shift": "2d"}`, http.StatusOK, map[string]time.Time{
+			"overdue": now.Add(-2 * time.Hour).AddDate(0, 0, 2),
+			"linked":  now.Add(-3 * time.Hour).AddDate(0, 0, 2),
+		}},
+		{"absolute time", `{"to": "` + to.Format(time.RFC3339) + `"}`, http.StatusOK, map[string]time.Time{
+			"overdue": to,
+			"linked":  to,
+		}},
+		{"invalid shift", `{"shift": "soon"}`, http.StatusBadRequest, nil},
+		{"negative shift", `{"shift": "-1h"}`, http.StatusBadRequest, nil},
+		{"both modes", `{"shift": "1h", "to": "` + to.Format(time.RFC3339) + `"}`, http.StatusBadRequest, nil},
+		{"neither mode", `{}`, http.StatusBadRequest, nil},
+		{"absolute time in the past", `{"to": "` + now.Add(-time.Hour).Format(time.RFC3339) + `"}`, http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.reminders["overdue"] = &models.Reminder{ID: "overdue", UserID: "user-1", ReminderTime: now.Add(-2 * time.Hour)}
+			store.reminders["linked"] = &models.Reminder{ID: "linked", UserID: "user-1", ReminderTime: now.Add(-3 * time.Hour), GoogleEventID: &eventID}
+			store.reminders["within-grace"] = &models.Reminder{ID: "within-grace", UserID: "user-1", ReminderTime: now.Add(-5 * time.Minute)}
+			store.reminders["done"] = &models.Reminder{ID: "done", UserID: "user-1", ReminderTime: now.Add(-2 * time.Hour), IsCompleted: true}
+			store.reminders["upcoming"] = &models.Reminder{ID: "upcoming", UserID: "user-1", ReminderTime: now.Add(time.Hour)}
+			store.reminders["other-user"] = &models.Reminder{ID: "other-user", UserID: "user-2", ReminderTime: now.Add(-2 * time.Hour)}
+			cfg := testConfig(now)
+			cfg.OverdueGrace = 15 * time.Minute
+			h := NewReminderHandler(store, nil, nil, cfg)
+
+			w := serve(h.RescheduleOverdue, http.MethodPost, "/reminders/reschedule-overdue", "/reminders/reschedule-overdue", tt.body, "user-1")
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want != http.StatusOK {
+				if len(store.reminderBatch) != 0 {
+					t.Errorf("batches = %v, want none", store.reminderBatch)
+				}
+				return
+			}
+
+			// One batched write covering every overdue reminder
+			if len(store.reminderBatch) != 1 || len(store.reminderBatch[0]) != len(tt.wantTime) {
+				t.Fatalf("batches = %v, want one with %d reminders", store.reminderBatch, len(tt.wantTime))
+			}
+			for id, want := range tt.wantTime {
+				got, ok := store.reminderBatch[0][id]["reminderTime"].(time.Time)
+				if !ok || !got.Equal(want) {
+					t.Errorf("%s moved to %v, want %v", id, got, want)
+				}
+			}
+
+			var body struct {
+				Rescheduled    []string             `json:"rescheduled"`
+				CalendarErrors []models.SyncFailure `json:"calendarErrors"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			slices.Sort(body.Rescheduled)
+			if want := []string{"linked", "overdue"}; !slices.Equal(body.Rescheduled, want) {
+				t.Errorf("rescheduled = %v, want %v", body.Rescheduled, want)
+			}
+			// user-1 has no stored profile, so the linked event can't be moved;
+			// only reminders with an event are attempted, and the write stands
+			if len(body.CalendarErrors) != 1 || body.CalendarErrors[0].ID != "linked" {
+				t.Errorf("calendar errors = %+v, want one for linked", body.CalendarErrors)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"focusflow-be/internal/config"
+	"focusflow-be/internal/models"
+	"focusflow-be/internal/services"
+)
+
+type SyncHandler struct {
+	requestStore
+	authService *services.AuthService
+	config      *config.Config
+}
+
+func NewSyncHandler(firebaseService services.Store, authService *services.AuthService, cfg *config.Config) *SyncHandler {
+	return &SyncHandler{
+		requestStore: requestStore{firebaseService},
+		authService:  authService,
+		config:       cfg,
+	}
+}
+
+// GetChanges serves incremental sync. With ?since= (RFC3339) it returns the
+// items changed at or after that time plus tombstones for deleted ones;
+// without it, everything. The response's "since" is the value to send next
+// time. Items changed right at the boundary may be sent twice, so clients
+// should apply changes idempotently.
+func (h *SyncHandler) GetChanges(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	// Stored timestamps have second precision, so the next window starts at
+	// the current second rather than the current instant
+	next := h.config.Clock.Now().UTC().Truncate(time.Second)
+
+	var changes *models.SyncChanges
+	if value := c.Query("since"); value != "" {
+		since, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid since parameter, expected RFC3339", "details": err.Error()})
+			return
+		}
+
+		changes, err = h.store(c).GetChangesSince(userSession.UserID, since)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch changes", "details": err.Error()})
+			return
+		}
+	} else {
+		var err error
+		changes, err = h.fullSync(c, userSession.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch changes", "details": err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tasks":     changes.Tasks,
+		"meetings":  changes.Meetings,
+		"reminders": changes.Reminders,
+		"deleted":   changes.Deleted,
+		"since":     next,
+	})
+}
+
+// fullSync returns every item the user has; there is nothing to delete yet on
+// a client that has never synced
+func (h *SyncHandler) fullSync(c *gin.Context, userID string) (*models.SyncChanges, error) {
+	tasks, err := h.store(c).GetTasks(userID)
+	if err != nil {
+		return nil, err
+	}
+	meetings, err := h.store(c).GetMeetings(userID)
+	if err != nil {
+		return nil, err
+	}
+	reminders, err := h.store(c).GetReminders(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SyncChanges{
+		Tasks:     tasks,
+		Meetings:  meetings,
+		Reminders: reminders,
+		Deleted:   []*models.Tombstone{},
+	}, nil
+}
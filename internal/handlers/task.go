@@ -1,27 +1,45 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"focusflow-be/internal/config"
 	"focusflow-be/internal/models"
+	"focusflow-be/internal/quickadd"
+	"focusflow-be/internal/recurrence"
 	"focusflow-be/internal/services"
 )
 
 type TaskHandler struct {
-	firebaseService *services.FirebaseService
-	authService     *services.AuthService
+	requestStore
+	authService   *services.AuthService
+	googleService *services.GoogleService
+	config        *config.Config
 }
 
-func NewTaskHandler(firebaseService *services.FirebaseService, authService *services.AuthService) *TaskHandler {
+func NewTaskHandler(firebaseService services.Store, authService *services.AuthService, googleService *services.GoogleService, cfg *config.Config) *TaskHandler {
 	return &TaskHandler{
-		firebaseService: firebaseService,
-		authService:     authService,
+		requestStore:  requestStore{firebaseService},
+		authService:   authService,
+		googleService: googleService,
+		config:        cfg,
 	}
 }
 
+// GetTasks lists the tasks the caller can see. ?owner=<userId> narrows the list
+// to one owner; it filters the visible tasks rather than querying that
+// owner's, so it never reveals tasks the caller has no access to. Tasks can't
+// be shared yet, so today only the caller's own ID matches anything.
 func (h *TaskHandler) GetTasks(c *gin.Context) {
 	user, exists := c.Get("user")
 	if !exists {
@@ -30,13 +48,342 @@ func (h *TaskHandler) GetTasks(c *gin.Context) {
 	}
 
 	userSession := user.(*models.UserSession)
-	tasks, err := h.firebaseService.GetTasks(userSession.UserID)
+	tasks, err := h.store(c).GetTasks(userSession.UserID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks", "details": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, tasks)
+	if owner := c.Query("owner"); owner != "" {
+		owned := []*models.Task{}
+		for _, task := range tasks {
+			if task.UserID == owner {
+				owned = append(owned, task)
+			}
+		}
+		tasks = owned
+	}
+
+	// ?meta.<key>=<value> keeps tasks whose metadata has that value; repeat a
+	// key to accept any of several values
+	for param, values := range c.Request.URL.Query() {
+		key, ok := strings.CutPrefix(param, "meta.")
+		if !ok {
+			continue
+		}
+		matching := []*models.Task{}
+		for _, task := range tasks {
+			if value, ok := task.Metadata[key]; ok && containsString(values, value) {
+				matching = append(matching, task)
+			}
+		}
+		tasks = matching
+	}
+
+	respondData(c, http.StatusOK, tasks)
+}
+
+func (h *TaskHandler) GetTasksDueSoon(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	within, err := parseRelativeDuration(c.DefaultQuery("within", "7d"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid within parameter, expected e.g. 3d, 1w or 12h", "details": err.Error()})
+		return
+	}
+
+	loc, err := requestLocation(c, h.store(c), userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tz parameter, expected an IANA time zone", "details": err.Error()})
+		return
+	}
+
+	// Every task not yet overdue is due soon, including those still in grace
+	now := h.config.Clock.Now()
+	tasks, err := h.store(c).GetTasksDueBetween(userSession.UserID, overdueTasksBefore(now, loc, h.config.OverdueGrace), now.Add(within))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks", "details": err.Error()})
+		return
+	}
+
+	dueSoon := []*models.Task{}
+	for _, task := range tasks {
+		if task.Status != "completed" {
+			dueSoon = append(dueSoon, task)
+		}
+	}
+
+	respondData(c, http.StatusOK, dueSoon)
+}
+
+// GetCompletedTasks lists tasks completed between ?from= and ?to= (RFC3339),
+// oldest first, for standup prep. The range defaults to the last seven days.
+// With ?limit= the list is paged; a full page sets X-Next-Cursor, which is
+// passed back as ?cursor= for the next one.
+func (h *TaskHandler) GetCompletedTasks(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	to := h.config.Clock.Now()
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to parameter, expected RFC3339", "details": err.Error()})
+			return
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -7)
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from parameter, expected RFC3339", "details": err.Error()})
+			return
+		}
+		from = parsed
+	}
+	if from.After(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return
+	}
+
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxPageSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid limit parameter, expected 1-%d", maxPageSize)})
+			return
+		}
+		limit = parsed
+	}
+	var after *services.PageCursor
+	if token := c.Query("cursor"); token != "" {
+		cursor, err := services.DecodeCursor(token, cursorKey(h.config))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor parameter", "details": err.Error()})
+			return
+		}
+		after = cursor
+	}
+
+	tasks, err := h.store(c).GetTasksCompletedBetween(userSession.UserID, from, to, limit, after)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks", "details": err.Error()})
+		return
+	}
+
+	if limit > 0 && len(tasks) == limit {
+		last := tasks[len(tasks)-1]
+		next, err := services.EncodeCursor(services.PageCursor{Value: *last.CompletedAt, ID: last.ID}, cursorKey(h.config))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build cursor", "details": err.Error()})
+			return
+		}
+		c.Header(nextCursorHeader, next)
+	}
+
+	respondData(c, http.StatusOK, tasks)
+}
+
+func (h *TaskHandler) GetTaskBoard(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+	tasks, err := h.store(c).GetTasks(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks", "details": err.Error()})
+		return
+	}
+
+	board := map[string][]*models.Task{
+		"todo":        {},
+		"in-progress": {},
+		"completed":   {},
+	}
+	for _, task := range tasks {
+		if column, ok := board[task.Status]; ok {
+			board[task.Status] = append(column, task)
+		}
+	}
+
+	// Manually ordered tasks first, then the rest oldest first
+	for _, column := range board {
+		sort.SliceStable(column, func(i, j int) bool {
+			a, b := column[i], column[j]
+			if (a.Order == nil) != (b.Order == nil) {
+				return a.Order != nil
+			}
+			if a.Order != nil && *a.Order != *b.Order {
+				return *a.Order < *b.Order
+			}
+			return a.CreatedAt.Before(b.CreatedAt)
+		})
+	}
+
+	c.JSON(http.StatusOK, board)
+}
+
+// GetTaskBacklog lists open tasks without a due date, which the calendar and
+// gantt views leave out. The most urgent come first, oldest first within a
+// priority.
+func (h *TaskHandler) GetTaskBacklog(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+	tasks, err := h.store(c).GetTasks(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks", "details": err.Error()})
+		return
+	}
+
+	backlog := []*models.Task{}
+	for _, task := range tasks {
+		if task.Status != "completed" && task.DueDate == nil {
+			backlog = append(backlog, task)
+		}
+	}
+
+	sort.SliceStable(backlog, func(i, j int) bool {
+		a, b := backlog[i], backlog[j]
+		if rankA, rankB := models.PriorityRank(a.Priority), models.PriorityRank(b.Priority); rankA != rankB {
+			return rankA > rankB
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+
+	respondData(c, http.StatusOK, backlog)
+}
+
+// GetTaskAgenda groups open tasks for an agenda list: one bucket per day
+// between ?from= and ?to= (YYYY-MM-DD, inclusive; default the next seven
+// days) that has tasks due, plus "overdue" for tasks whose due day has passed
+// and "noDate" for tasks without one. Days are taken in the ?tz= zone, then
+// the user's stored time zone, then UTC. Each bucket is sorted by priority,
+// then due time, then age.
+func (h *TaskHandler) GetTaskAgenda(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	loc, err := requestLocation(c, h.store(c), userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tz parameter, expected an IANA time zone", "details": err.Error()})
+		return
+	}
+
+	now := h.config.Clock.Now().In(loc)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if value := c.Query("from"); value != "" {
+		if from, err = time.ParseInLocation("2006-01-02", value, loc); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from parameter, expected YYYY-MM-DD", "details": err.Error()})
+			return
+		}
+	}
+	to := from.AddDate(0, 0, 6)
+	if value := c.Query("to"); value != "" {
+		if to, err = time.ParseInLocation("2006-01-02", value, loc); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to parameter, expected YYYY-MM-DD", "details": err.Error()})
+			return
+		}
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return
+	}
+	if to.After(from.AddDate(0, 0, maxAgendaDays-1)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Range is limited to %d days", maxAgendaDays)})
+		return
+	}
+
+	tasks, err := h.store(c).GetTasks(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks", "details": err.Error()})
+		return
+	}
+
+	overdue, days, noDate := taskAgenda(tasks, from, to, now, h.config.OverdueGrace)
+	c.JSON(http.StatusOK, gin.H{
+		"from":     from.Format("2006-01-02"),
+		"to":       to.Format("2006-01-02"),
+		"timezone": loc.String(),
+		"overdue":  overdue,
+		"days":     days,
+		"noDate":   noDate,
+	})
+}
+
+const maxAgendaDays = 92
+
+// taskAgenda buckets the open tasks, taking days in now's location. An
+// overdue task (see isOverdue) is taken out of its day even when the day is in
+// range.
+func taskAgenda(tasks []*models.Task, from, to, now time.Time, grace time.Duration) ([]*models.Task, []models.AgendaDay, []*models.Task) {
+	loc := now.Location()
+	first, last := from.Format("2006-01-02"), to.Format("2006-01-02")
+
+	overdue, noDate := []*models.Task{}, []*models.Task{}
+	byDay := make(map[string][]*models.Task)
+	for _, task := range tasks {
+		switch {
+		case task.Status == "completed":
+		case task.DueDate == nil:
+			noDate = append(noDate, task)
+		case isOverdue(task, now, loc, grace):
+			overdue = append(overdue, task)
+		default:
+			if day := task.DueDate.In(loc).Format("2006-01-02"); day >= first && day <= last {
+				byDay[day] = append(byDay[day], task)
+			}
+		}
+	}
+
+	days := []models.AgendaDay{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		if dayTasks, ok := byDay[date]; ok {
+			sortAgendaTasks(dayTasks)
+			days = append(days, models.AgendaDay{Date: date, Tasks: dayTasks})
+		}
+	}
+	sortAgendaTasks(overdue)
+	sortAgendaTasks(noDate)
+	return overdue, days, noDate
+}
+
+func sortAgendaTasks(tasks []*models.Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if rankA, rankB := models.PriorityRank(a.Priority), models.PriorityRank(b.Priority); rankA != rankB {
+			return rankA > rankB
+		}
+		if a.DueDate != nil && b.DueDate != nil && !a.DueDate.Equal(*b.DueDate) {
+			return a.DueDate.Before(*b.DueDate)
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
 }
 
 func (h *TaskHandler) CreateTask(c *gin.Context) {
@@ -53,6 +400,10 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
 		return
 	}
+	if err := models.ValidateMetadata(req.Metadata); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid metadata", "details": err.Error()})
+		return
+	}
 
 	task := &models.Task{
 		UserID:         userSession.UserID,
@@ -61,23 +412,152 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 		Completed:      false,
 		Status:         "todo",
 		Priority:       req.Priority,
+		Tags:           req.Tags,
 		StartDate:      req.StartDate,
 		DueDate:        req.DueDate,
 		EstimatedHours: req.EstimatedHours,
+		Order:          req.Order,
+		Metadata:       req.Metadata,
 	}
 
-	taskID, err := h.firebaseService.CreateTask(task)
+	var warn warnings
+	if req.DueDate != nil && req.DueDate.Before(h.config.Clock.Now()) {
+		warn.add("dueDate is in the past")
+	}
+	if req.StartDate != nil && req.DueDate != nil && req.DueDate.Before(*req.StartDate) {
+		warn.add("dueDate is before startDate")
+	}
+	if warn.rejectIfStrict(c) {
+		return
+	}
+
+	if (h.config.TaskDuplicateCheck || c.Query("checkDuplicate") == "true") && c.Query("force") != "true" {
+		existing, err := h.store(c).GetTasks(userSession.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check for duplicate tasks", "details": err.Error()})
+			return
+		}
+		if duplicate := findDuplicateTask(existing, req.Title); duplicate != nil {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":      "An open task with this title already exists; pass ?force=true to create it anyway",
+				"existingId": duplicate.ID,
+			})
+			return
+		}
+	}
+
+	taskID, err := h.store(c).CreateTask(task)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create task", "details": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
+	c.JSON(http.StatusCreated, warn.attach(gin.H{
 		"id":      taskID,
 		"message": "Task created successfully",
+	}))
+}
+
+// QuickAddTask creates a task from one line of free text such as
+// "Submit report tomorrow 5pm #work !high". Dates are read in the user's time
+// zone, and the response says how the text was understood so the client can
+// confirm it. Tasks without a !priority get "medium".
+func (h *TaskHandler) QuickAddTask(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	var req models.QuickAddTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	loc := time.UTC
+	if profile, err := h.store(c).GetUser(userSession.UserID); err == nil {
+		if userLoc, err := recurrence.LoadLocation(profile.Timezone); err == nil {
+			loc = userLoc
+		}
+	}
+
+	parsed := quickadd.Parse(req.Text, h.config.Clock.Now().In(loc))
+	if parsed.Title == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "text has no title left once the date, tags and priority are taken out"})
+		return
+	}
+
+	priority := parsed.Priority
+	if priority == "" {
+		priority = "medium"
+	}
+	task := &models.Task{
+		UserID:   userSession.UserID,
+		Title:    parsed.Title,
+		Status:   "todo",
+		Priority: priority,
+		Tags:     parsed.Tags,
+		DueDate:  parsed.Due,
+	}
+
+	taskID, err := h.store(c).CreateTask(task)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create task", "details": err.Error()})
+		return
+	}
+	task.ID = taskID
+
+	c.JSON(http.StatusCreated, gin.H{
+		"task": task,
+		"parsed": models.QuickAddInterpretation{
+			Title:    parsed.Title,
+			DueDate:  parsed.Due,
+			DateText: parsed.DateText,
+			Tags:     parsed.Tags,
+			Priority: parsed.Priority,
+			Timezone: loc.String(),
+		},
 	})
 }
 
+// taskTransitionError explains why a task can't move to status, or returns ""
+// when it can. Completing a task that was never started is fine; completing
+// one that is blocked needs force.
+func taskTransitionError(task *models.Task, status string, force bool) string {
+	if status == "completed" && task.Blocked && !force {
+		return "a blocked task cannot be completed; unblock it first or pass ?force=true"
+	}
+	return ""
+}
+
+// findDuplicateTask returns an incomplete task whose title matches title once
+// both are trimmed and lowercased
+func findDuplicateTask(tasks []*models.Task, title string) *models.Task {
+	normalized := normalizeTitle(title)
+	for _, task := range tasks {
+		if task.Status != "completed" && normalizeTitle(task.Title) == normalized {
+			return task
+		}
+	}
+	return nil
+}
+
+func normalizeTitle(title string) string {
+	return strings.ToLower(strings.TrimSpace(title))
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
 func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	taskID := c.Param("id")
 	if taskID == "" {
@@ -85,11 +565,27 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 		return
 	}
 
+	task, ok := h.loadOwnedTask(c, taskID)
+	if !ok {
+		return
+	}
+
 	var req models.UpdateTaskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
 		return
 	}
+	if err := models.ValidateMetadata(req.Metadata); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid metadata", "details": err.Error()})
+		return
+	}
+
+	if req.Status != nil {
+		if reason := taskTransitionError(task, *req.Status, c.Query("force") == "true"); reason != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status transition", "details": reason})
+			return
+		}
+	}
 
 	updates := make(map[string]interface{})
 	if req.Title != nil {
@@ -102,10 +598,18 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 		updates["priority"] = *req.Priority
 	}
 	if req.Status != nil {
-		updates["status"] = *req.Status
-		if *req.Status == "completed" {
-			updates["completed"] = true
+		switch {
+		case *req.Status == "completed" && task.Status != "completed":
+			for key, value := range h.completionUpdates(task) {
+				updates[key] = value
+			}
+		case *req.Status != "completed" && task.Status == "completed":
+			// Reopened: drop the completion so it leaves the completed lists
+			updates["completed"] = false
+			updates["completedAt"] = services.DeleteField
+			updates["previousStatus"] = services.DeleteField
 		}
+		updates["status"] = *req.Status
 	}
 	if req.StartDate != nil {
 		updates["startDate"] = *req.StartDate
@@ -119,11 +623,24 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	if req.ActualHours != nil {
 		updates["actualHours"] = *req.ActualHours
 	}
+	if req.Order != nil {
+		updates["order"] = *req.Order
+	}
+	if req.Metadata != nil {
+		updates["metadata"] = req.Metadata
+	}
+	if err := applyClearFields(updates, req.ClearFields); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	if err := h.firebaseService.UpdateTask(taskID, updates); err != nil {
+	if err := h.store(c).UpdateTask(taskID, updates); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update task", "details": err.Error()})
 		return
 	}
+	if req.Status != nil && *req.Status == "completed" {
+		h.completeLinkedReminders(c, task.UserID, taskID)
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Task updated successfully"})
 }
@@ -135,14 +652,63 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 		return
 	}
 
-	if err := h.firebaseService.DeleteTask(taskID); err != nil {
+	task, ok := h.loadOwnedTask(c, taskID)
+	if !ok {
+		return
+	}
+
+	if err := h.store(c).DeleteTask(task); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete task", "details": err.Error()})
 		return
 	}
+	h.deleteLinkedReminders(c, task.UserID, taskID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Task deleted successfully"})
 }
 
+// DeleteCompletedTasks removes all of the caller's completed tasks at once,
+// with their reminders. Deletes are permanent like DELETE /tasks/:id. There is
+// no soft-delete mode: nothing has a trash to restore from, and hiding
+// soft-deleted tasks would mean filtering them out of every task query, count
+// and sync feed. Syncing clients learn about the deletes from tombstones.
+func (h *TaskHandler) DeleteCompletedTasks(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	tasks, err := h.store(c).GetTasks(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks", "details": err.Error()})
+		return
+	}
+
+	var completed []*models.Task
+	for _, task := range tasks {
+		if task.Status == "completed" && task.UserID == userSession.UserID {
+			completed = append(completed, task)
+		}
+	}
+
+	if err := h.store(c).BatchDeleteTasks(completed); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete completed tasks", "details": err.Error()})
+		return
+	}
+	taskIDs := make([]string, len(completed))
+	for i, task := range completed {
+		taskIDs[i] = task.ID
+	}
+	h.deleteLinkedReminders(c, userSession.UserID, taskIDs...)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Completed tasks deleted",
+		"deleted": len(completed),
+	})
+}
+
 func (h *TaskHandler) StartTask(c *gin.Context) {
 	taskID := c.Param("id")
 	if taskID == "" {
@@ -150,12 +716,16 @@ func (h *TaskHandler) StartTask(c *gin.Context) {
 		return
 	}
 
+	if _, ok := h.loadOwnedTask(c, taskID); !ok {
+		return
+	}
+
 	updates := map[string]interface{}{
 		"status":    "in-progress",
-		"startedAt": time.Now(),
+		"startedAt": h.config.Clock.Now(),
 	}
 
-	if err := h.firebaseService.UpdateTask(taskID, updates); err != nil {
+	if err := h.store(c).UpdateTask(taskID, updates); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start task", "details": err.Error()})
 		return
 	}
@@ -163,6 +733,71 @@ func (h *TaskHandler) StartTask(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Task started successfully"})
 }
 
+// BlockTask flags a task as stuck without touching its status, so unblocking
+// returns it to wherever it was
+func (h *TaskHandler) BlockTask(c *gin.Context) {
+	taskID := c.Param("id")
+	if taskID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Task ID is required"})
+		return
+	}
+
+	task, ok := h.loadOwnedTask(c, taskID)
+	if !ok {
+		return
+	}
+
+	if task.Status == "completed" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Completed tasks cannot be blocked"})
+		return
+	}
+
+	var req models.BlockTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	updates := map[string]interface{}{
+		"blocked":     true,
+		"blockReason": services.DeleteField,
+	}
+	if req.Reason != nil && *req.Reason != "" {
+		updates["blockReason"] = *req.Reason
+	}
+
+	if err := h.store(c).UpdateTask(taskID, updates); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to block task", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Task blocked successfully"})
+}
+
+func (h *TaskHandler) UnblockTask(c *gin.Context) {
+	taskID := c.Param("id")
+	if taskID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Task ID is required"})
+		return
+	}
+
+	if _, ok := h.loadOwnedTask(c, taskID); !ok {
+		return
+	}
+
+	updates := map[string]interface{}{
+		"blocked":     services.DeleteField,
+		"blockReason": services.DeleteField,
+	}
+
+	if err := h.store(c).UpdateTask(taskID, updates); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unblock task", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Task unblocked successfully"})
+}
+
 func (h *TaskHandler) CompleteTask(c *gin.Context) {
 	taskID := c.Param("id")
 	if taskID == "" {
@@ -170,16 +805,307 @@ func (h *TaskHandler) CompleteTask(c *gin.Context) {
 		return
 	}
 
+	task, ok := h.loadOwnedTask(c, taskID)
+	if !ok {
+		return
+	}
+
+	if reason := taskTransitionError(task, "completed", c.Query("force") == "true"); reason != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status transition", "details": reason})
+		return
+	}
+
+	if err := h.store(c).UpdateTask(taskID, h.completionUpdates(task)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete task", "details": err.Error()})
+		return
+	}
+	h.completeLinkedReminders(c, task.UserID, taskID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Task completed successfully"})
+}
+
+// ToggleTask completes an open task, or reopens a completed one in the status
+// it had before it was completed (todo when that isn't known). Completing
+// follows the same rules as CompleteTask, ?force=true included.
+func (h *TaskHandler) ToggleTask(c *gin.Context) {
+	taskID := c.Param("id")
+	if taskID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Task ID is required"})
+		return
+	}
+
+	task, ok := h.loadOwnedTask(c, taskID)
+	if !ok {
+		return
+	}
+
+	if task.Status != "completed" {
+		if reason := taskTransitionError(task, "completed", c.Query("force") == "true"); reason != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status transition", "details": reason})
+			return
+		}
+		if err := h.store(c).UpdateTask(taskID, h.completionUpdates(task)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete task", "details": err.Error()})
+			return
+		}
+		h.completeLinkedReminders(c, task.UserID, taskID)
+
+		c.JSON(http.StatusOK, gin.H{"message": "Task completed successfully", "status": "completed"})
+		return
+	}
+
+	status := "todo"
+	if task.PreviousStatus != nil && *task.PreviousStatus != "completed" {
+		status = *task.PreviousStatus
+	}
+	updates := map[string]interface{}{
+		"status":         status,
+		"completed":      false,
+		"completedAt":    services.DeleteField,
+		"previousStatus": services.DeleteField,
+	}
+	if err := h.store(c).UpdateTask(taskID, updates); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reopen task", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Task reopened successfully", "status": status})
+}
+
+// completionUpdates are the writes that complete task, remembering its
+// current status for ToggleTask
+func (h *TaskHandler) completionUpdates(task *models.Task) map[string]interface{} {
 	updates := map[string]interface{}{
 		"status":      "completed",
 		"completed":   true,
-		"completedAt": time.Now(),
+		"completedAt": h.config.Clock.Now(),
+	}
+	if task.Status != "completed" {
+		updates["previousStatus"] = task.Status
+	}
+	return updates
+}
+
+// SyncCalendar pushes the caller's open, dated tasks that aren't on their
+// calendar yet. Each task is synced independently; the response lists the
+// ones that failed so they can be retried.
+func (h *TaskHandler) SyncCalendar(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
 	}
 
-	if err := h.firebaseService.UpdateTask(taskID, updates); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete task", "details": err.Error()})
+	userSession := user.(*models.UserSession)
+	profile, err := h.store(c).GetUser(userSession.UserID)
+	if err != nil {
+		respondResourceError(c, "User", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Task completed successfully"})
-}
\ No newline at end of file
+	tasks, err := h.store(c).GetTasks(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks", "details": err.Error()})
+		return
+	}
+
+	var pending []*models.Task
+	for _, task := range tasks {
+		if task.DueDate != nil && task.GoogleEventID == nil && task.Status != "completed" {
+			pending = append(pending, task)
+		}
+	}
+
+	eventIDs, failed, err := h.googleService.SyncTasks(h.googleService.UserToken(profile), profile.CalendarID, pending)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to connect to Google Calendar", "details": err.Error()})
+		return
+	}
+
+	updates := make(map[string]map[string]interface{}, len(eventIDs))
+	for taskID, eventID := range eventIDs {
+		updates[taskID] = map[string]interface{}{"googleEventId": eventID}
+	}
+	if err := h.store(c).BatchUpdateTasks(updates); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Events created but failed to save their IDs", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"synced": len(eventIDs),
+		"failed": failed,
+	})
+}
+
+func (h *TaskHandler) RescheduleOverdue(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	userSession := user.(*models.UserSession)
+
+	var req models.RescheduleOverdueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	// Exactly one rescheduling mode must be supplied
+	if (req.To == nil) == (req.ShiftDays == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Provide exactly one of to or shiftDays"})
+		return
+	}
+
+	loc, err := requestLocation(c, h.store(c), userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tz parameter, expected an IANA time zone", "details": err.Error()})
+		return
+	}
+
+	now := h.config.Clock.Now()
+	var target time.Time
+	if req.To != nil {
+		parsed, err := time.ParseInLocation("2006-01-02", *req.To, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, expected YYYY-MM-DD"})
+			return
+		}
+		// Moving tasks to a day that has already passed leaves them overdue
+		if today := now.In(loc); parsed.Before(time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, loc)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must not be in the past"})
+			return
+		}
+		target = parsed
+	}
+	if req.ShiftDays != nil && *req.ShiftDays <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "shiftDays must be a positive number"})
+		return
+	}
+
+	tasks, err := h.store(c).GetTasks(userSession.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks", "details": err.Error()})
+		return
+	}
+
+	updates := make(map[string]map[string]interface{})
+	rescheduled := []string{}
+	for _, task := range tasks {
+		if !isOverdue(task, now, loc, h.config.OverdueGrace) {
+			continue
+		}
+
+		// Keep the original time of day, in the user's zone, when moving to an absolute date
+		var newDueDate time.Time
+		if req.To != nil {
+			due := task.DueDate.In(loc)
+			newDueDate = time.Date(target.Year(), target.Month(), target.Day(), due.Hour(), due.Minute(), due.Second(), 0, loc)
+		} else {
+			newDueDate = task.DueDate.AddDate(0, 0, *req.ShiftDays)
+		}
+
+		// Move the start date by the same amount so the task keeps its length
+		fields := map[string]interface{}{"dueDate": newDueDate}
+		if task.StartDate != nil {
+			fields["startDate"] = task.StartDate.Add(newDueDate.Sub(*task.DueDate))
+		}
+
+		updates[task.ID] = fields
+		rescheduled = append(rescheduled, task.ID)
+	}
+
+	if len(updates) > 0 {
+		if err := h.store(c).BatchUpdateTasks(updates); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reschedule tasks", "details": err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rescheduled": rescheduled,
+		"message":     "Overdue tasks rescheduled successfully",
+	})
+}
+
+// loadOwnedTask fetches a task owned by the current user, writing the
+// ownership policy's error response when that fails
+func (h *TaskHandler) loadOwnedTask(c *gin.Context, taskID string) (*models.Task, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return nil, false
+	}
+
+	userSession := user.(*models.UserSession)
+	task, err := h.store(c).GetTask(taskID)
+	if err == nil {
+		err = checkOwnership(task, userSession.UserID)
+	}
+	if err != nil {
+		respondResourceError(c, "Task", err)
+		return nil, false
+	}
+
+	return task, true
+}
+
+// linkedReminders returns userID's reminders that belong to any of taskIDs
+func (h *TaskHandler) linkedReminders(c *gin.Context, userID string, taskIDs ...string) ([]*models.Reminder, error) {
+	if len(taskIDs) == 0 {
+		return nil, nil
+	}
+	reminders, err := h.store(c).GetReminders(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var linked []*models.Reminder
+	for _, reminder := range reminders {
+		if reminder.TaskID != nil && containsString(taskIDs, *reminder.TaskID) {
+			linked = append(linked, reminder)
+		}
+	}
+	return linked, nil
+}
+
+// deleteLinkedReminders removes the reminders of tasks that were just deleted.
+// The tasks are already gone, so a failure here is logged rather than
+// returned; the orphaned reminders stay until the user deletes them.
+func (h *TaskHandler) deleteLinkedReminders(c *gin.Context, userID string, taskIDs ...string) {
+	reminders, err := h.linkedReminders(c, userID, taskIDs...)
+	if err == nil && len(reminders) > 0 {
+		err = h.store(c).BatchDeleteReminders(reminders)
+	}
+	if err != nil {
+		log.Printf("⚠️ Failed to delete reminders of deleted tasks for user %s: %v", userID, err)
+	}
+}
+
+// completeLinkedReminders marks the open reminders of a completed task as
+// done. Like deleteLinkedReminders, it's best-effort.
+func (h *TaskHandler) completeLinkedReminders(c *gin.Context, userID, taskID string) {
+	reminders, err := h.linkedReminders(c, userID, taskID)
+	if err != nil {
+		log.Printf("⚠️ Failed to complete reminders of task %s: %v", taskID, err)
+		return
+	}
+
+	now := h.config.Clock.Now()
+	updates := make(map[string]map[string]interface{})
+	for _, reminder := range reminders {
+		if !reminder.IsCompleted {
+			updates[reminder.ID] = map[string]interface{}{
+				"isCompleted": true,
+				"completedAt": now,
+			}
+		}
+	}
+	if len(updates) == 0 {
+		return
+	}
+	if err := h.store(c).BatchUpdateReminders(updates); err != nil {
+		log.Printf("⚠️ Failed to complete reminders of task %s: %v", taskID, err)
+	}
+}
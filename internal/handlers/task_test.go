@@ -0,0 +1,711 @@
+package handlers
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"focusflow-be/internal/models"
+	"focusflow-be/internal/services"
+)
+
+func TestUpdateTaskCompletion(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	completedAt := now.Add(-time.Hour)
+	previous := "in-progress"
+
+	tests := []struct {
+		name            string
+		task            models.Task
+		body            string
+		wantCompletedAt interface{} // nil when completedAt isn't written
+	}{
+		{
+			name:            "completing sets completedAt",
+			task:            models.Task{Status: "in-progress"},
+			body:            `{"status": "completed"}`,
+			wantCompletedAt: now,
+		},
+		{
+			name:            "reopening clears completedAt",
+			task:            models.Task{Status: "completed", Completed: true, CompletedAt: &completedAt, PreviousStatus: &previous},
+			body:            `{"status": "in-progress"}`,
+			wantCompletedAt: services.DeleteField,
+		},
+		{
+			name: "saving a completed task keeps its completedAt",
+			task: models.Task{Status: "completed", Completed: true, CompletedAt: &completedAt},
+			body: `{"status": "completed", "title": "Renamed"}`,
+		},
+		{
+			name: "other status changes leave it alone",
+			task: models.Task{Status: "todo"},
+			body: `{"status": "in-progress"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := tt.task
+			task.ID, task.UserID, task.Title = "t1", "user-1", "Write report"
+			store := newMockStore()
+			store.tasks["t1"] = &task
+			h := NewTaskHandler(store, nil, nil, testConfig(now))
+
+			w := serve(h.UpdateTask, http.MethodPut, "/tasks/:id", "/tasks/t1?force=true", tt.body, "user-1")
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+			}
+			if len(store.updates) != 1 {
+				t.Fatalf("%d updates written, want 1", len(store.updates))
+			}
+			if got := store.updates[0]["completedAt"]; got != tt.wantCompletedAt {
+				t.Errorf("completedAt = %v, want %v", got, tt.wantCompletedAt)
+			}
+		})
+	}
+}
+
+func TestToggleTask(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	completedAt := now.Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		task       models.Task
+		query      string
+		want       int
+		wantStatus []string // status after each toggle
+	}{
+		{"todo round trip", models.Task{Status: "todo"}, "", http.StatusOK, []string{"completed", "todo"}},
+		{"in-progress round trip", models.Task{Status: "in-progress"}, "", http.StatusOK, []string{"completed", "in-progress", "completed"}},
+		{"completed without a prior status", models.Task{Status: "completed", Completed: true, CompletedAt: &completedAt}, "", http.StatusOK, []string{"todo"}},
+		{"blocked", models.Task{Status: "in-progress", Blocked: true}, "", http.StatusBadRequest, nil},
+		{"blocked, forced", models.Task{Status: "in-progress", Blocked: true}, "?force=true", http.StatusOK, []string{"completed", "in-progress"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := tt.task
+			task.ID, task.UserID, task.Title = "t1", "user-1", "Write report"
+			store := newMockStore()
+			store.tasks["t1"] = &task
+			h := NewTaskHandler(store, nil, nil, testConfig(now))
+
+			if tt.want != http.StatusOK {
+				w := serve(h.ToggleTask, http.MethodPatch, "/tasks/:id/toggle", "/tasks/t1/toggle"+tt.query, "", "user-1")
+				if w.Code != tt.want || len(store.updates) != 0 {
+					t.Errorf("status = %d with %d updates, want %d and none", w.Code, len(store.updates), tt.want)
+				}
+				return
+			}
+
+			for i, wantStatus := range tt.wantStatus {
+				w := serve(h.ToggleTask, http.MethodPatch, "/tasks/:id/toggle", "/tasks/t1/toggle"+tt.query, "", "user-1")
+				if w.Code != http.StatusOK {
+					t.Fatalf("toggle %d: status = %d: %s", i+1, w.Code, w.Body.String())
+				}
+				updates := store.updates[len(store.updates)-1]
+				if updates["status"] != wantStatus {
+					t.Fatalf("toggle %d: status = %v, want %s", i+1, updates["status"], wantStatus)
+				}
+
+				// Completing stamps the time and remembers the status to restore;
+				// reopening clears both
+				if wantStatus == "completed" {
+					if updates["completedAt"] != now || updates["previousStatus"] != task.Status {
+						t.Errorf("toggle %d: completedAt = %v, previousStatus = %v; want %v, %s", i+1, updates["completedAt"], updates["previousStatus"], now, task.Status)
+					}
+				} else if updates["completedAt"] != services.DeleteField || updates["previousStatus"] != services.DeleteField {
+					t.Errorf("toggle %d: completedAt = %v, previousStatus = %v; want both deleted", i+1, updates["completedAt"], updates["previousStatus"])
+				}
+				applyTaskUpdates(&task, updates)
+			}
+		})
+	}
+}
+
+// applyTaskUpdates writes the status fields of updates to task, as the store
+// would
+func applyTaskUpdates(task *models.Task, updates map[string]interface{}) {
+	task.Status = updates["status"].(string)
+	task.Completed = updates["completed"].(bool)
+	task.CompletedAt, task.PreviousStatus = nil, nil
+	if at, ok := updates["completedAt"].(time.Time); ok {
+		task.CompletedAt = &at
+	}
+	if previous, ok := updates["previousStatus"].(string); ok {
+		task.PreviousStatus = &previous
+	}
+}
+
+func TestTaskTransitionError(t *testing.T) {
+	tests := []struct {
+		name    string
+		blocked bool
+		status  string
+		force   bool
+		wantErr bool
+	}{
+		{"complete an open task", false, "completed", false, false},
+		{"complete a blocked task", true, "completed", false, true},
+		{"force-complete a blocked task", true, "completed", true, false},
+		{"start a blocked task", true, "in-progress", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &models.Task{Status: "todo", Blocked: tt.blocked}
+			reason := taskTransitionError(task, tt.status, tt.force)
+			if (reason != "") != tt.wantErr {
+				t.Errorf("reason = %q, want error %v", reason, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetCompletedTasks(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	completedAt := func(days int) *time.Time {
+		at := now.AddDate(0, 0, -days)
+		return &at
+	}
+	otherKey, err := services.EncodeCursor(services.PageCursor{Value: now, ID: "one"}, []byte("other-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		want       int
+		wantFrom   time.Time
+		wantTo     time.Time
+		wantIDs    []string
+		wantCursor bool
+	}{
+		{"last week by default", "", http.StatusOK, now.AddDate(0, 0, -7), now, []string{"six", "one"}, false},
+		{"explicit range", "?from=2026-09-01T00:00:00Z&to=2026-10-12T00:00:00Z", http.StatusOK, time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), []string{"thirty", "six"}, false},
+		{"from defaults to a week before to", "?to=2026-10-12T00:00:00Z", http.StatusOK, time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), []string{"six"}, false},
+		{"full page has a cursor", "?limit=2", http.StatusOK, now.AddDate(0, 0, -7), now, []string{"six", "one"}, true},
+		{"short page has none", "?limit=3", http.StatusOK, now.AddDate(0, 0, -7), now, []string{"six", "one"}, false},
+		{"from after to", "?from=2026-10-16T00:00:00Z&to=2026-10-15T00:00:00Z", http.StatusBadRequest, time.Time{}, time.Time{}, nil, false},
+		{"invalid from", "?from=yesterday", http.StatusBadRequest, time.Time{}, time.Time{}, nil, false},
+		{"invalid to", "?to=2026-10-16", http.StatusBadRequest, time.Time{}, time.Time{}, nil, false},
+		{"zero limit", "?limit=0", http.StatusBadRequest, time.Time{}, time.Time{}, nil, false},
+		{"limit over the page size", fmt.Sprintf("?limit=%d", maxPageSize+1), http.StatusBadRequest, time.Time{}, time.Time{}, nil, false},
+		{"forged cursor", "?cursor=abc", http.StatusBadRequest, time.Time{}, time.Time{}, nil, false},
+		{"cursor signed with another key", "?cursor=" + url.QueryEscape(otherKey), http.StatusBadRequest, time.Time{}, time.Time{}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.tasks["one"] = &models.Task{ID: "one", UserID: "user-1", Status: "completed", CompletedAt: completedAt(1)}
+			store.tasks["six"] = &models.Task{ID: "six", UserID: "user-1", Status: "completed", CompletedAt: completedAt(6)}
+			store.tasks["thirty"] = &models.Task{ID: "thirty", UserID: "user-1", Status: "completed", CompletedAt: completedAt(30)}
+			store.tasks["theirs"] = &models.Task{ID: "theirs", UserID: "user-2", Status: "completed", CompletedAt: completedAt(1)}
+			cfg := testConfig(now)
+			cfg.JWTSecret = "test-secret"
+			h := NewTaskHandler(store, nil, nil, cfg)
+
+			w := serve(h.GetCompletedTasks, http.MethodGet, "/tasks/completed", "/tasks/completed"+tt.query, "", "user-1")
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want != http.StatusOK {
+				if len(store.completed) != 0 {
+					t.Error("store was queried for a rejected request")
+				}
+				return
+			}
+
+			if len(store.completed) != 1 || !store.completed[0][0].Equal(tt.wantFrom) || !store.completed[0][1].Equal(tt.wantTo) {
+				t.Errorf("queried %v, want [%v %v]", store.completed, tt.wantFrom, tt.wantTo)
+			}
+			var tasks []models.Task
+			if err := json.Unmarshal(w.Body.Bytes(), &tasks); err != nil {
+				t.Fatalf("response is not a task list: %v: %s", err, w.Body.String())
+			}
+			var ids []string
+			for _, task := range tasks {
+				ids = append(ids, task.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("tasks = %v, want %v", ids, tt.wantIDs)
+			}
+			if hasCursor := w.Header().Get(nextCursorHeader) != ""; hasCursor != tt.wantCursor {
+				t.Errorf("cursor sent = %v, want %v", hasCursor, tt.wantCursor)
+			}
+		})
+	}
+}
+
+func TestGetCompletedTasksPages(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	store := newMockStore()
+	for i := 1; i <= 3; i++ {
+		at := now.Add(-time.Duration(i) * time.Hour)
+		id := fmt.Sprintf("task-%d", i)
+		store.tasks[id] = &models.Task{ID: id, UserID: "user-1", Status: "completed", CompletedAt: &at}
+	}
+	cfg := testConfig(now)
+	cfg.JWTSecret = "test-secret"
+	h := NewTaskHandler(store, nil, nil, cfg)
+
+	var ids []string
+	path := "/tasks/completed?limit=2"
+	for page := 0; page < 3 && path != ""; page++ {
+		w := serve(h.GetCompletedTasks, http.MethodGet, "/tasks/completed", path, "", "user-1")
+		if w.Code != http.StatusOK {
+			t.Fatalf("page %d: status = %d: %s", page, w.Code, w.Body.String())
+		}
+		var tasks []models.Task
+		if err := json.Unmarshal(w.Body.Bytes(), &tasks); err != nil {
+			t.Fatal(err)
+		}
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+		path = ""
+		if cursor := w.Header().Get(nextCursorHeader); cursor != "" {
+			path = "/tasks/completed?limit=2&cursor=" + url.QueryEscape(cursor)
+		}
+	}
+
+	if want := []string{"task-3", "task-2", "task-1"}; !slices.Equal(ids, want) {
+		t.Errorf("paged through %v, want %v", ids, want)
+	}
+}
+
+func TestRescheduleOverdue(t *testing.T) {
+	// 02:30 UTC on the 16th is still the evening of the 15th in New York
+	now := time.Date(2026, 10, 16, 2, 30, 0, 0, time.UTC)
+	newYork, _ := time.LoadLocation("America/New_York")
+	at := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name     string
+		timezone string
+		body     string
+		want     map[string]time.Time // new due date of each rescheduled task
+		wantCode int
+	}{
+		{
+			name: "to an absolute date",
+			body: `{"to": "2026-10-20"}`,
+			want: map[string]time.Time{
+				"overdue":    time.Date(2026, 10, 20, 17, 0, 0, 0, time.UTC),
+				"with-start": time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC),
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "to a date in the user's time zone",
+			timezone: "America/New_York",
+			body:     `{"to": "2026-10-20"}`,
+			// 17:00 UTC on the 14th was 13:00 in New York
+			want: map[string]time.Time{
+				"overdue":    time.Date(2026, 10, 20, 13, 0, 0, 0, newYork),
+				"with-start": time.Date(2026, 10, 20, 5, 0, 0, 0, newYork),
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "shifted by days",
+			body: `{"shiftDays": 3}`,
+			want: map[string]time.Time{
+				"overdue":    time.Date(2026, 10, 17, 17, 0, 0, 0, time.UTC),
+				"with-start": time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+			},
+			wantCode: http.StatusOK,
+		},
+		{name: "to today", body: `{"to": "2026-10-16"}`, wantCode: http.StatusOK, want: map[string]time.Time{
+			"overdue":    time.Date(2026, 10, 16, 17, 0, 0, 0, time.UTC),
+			"with-start": time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+		}},
+		{name: "to a past date", body: `{"to": "2026-10-15"}`, wantCode: http.StatusBadRequest},
+		{name: "both modes", body: `{"to": "2026-10-20", "shiftDays": 3}`, wantCode: http.StatusBadRequest},
+		{name: "neither mode", body: `{}`, wantCode: http.StatusBadRequest},
+		{name: "shift backwards", body: `{"shiftDays": -1}`, wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.users["user-1"] = &models.UserSession{UserID: "user-1", Timezone: tt.timezone}
+			store.tasks["overdue"] = &models.Task{ID: "overdue", UserID: "user-1", Status: "todo", DueDate: at(time.Date(2026, 10, 14, 17, 0, 0, 0, time.UTC))}
+			store.tasks["with-start"] = &models.Task{
+				ID: "with-start", UserID: "user-1", Status: "in-progress",
+				StartDate: at(time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)),
+				DueDate:   at(time.Date(2026, 10, 13, 9, 0, 0, 0, time.UTC)),
+			}
+			store.tasks["done"] = &models.Task{ID: "done", UserID: "user-1", Status: "completed", DueDate: at(time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC))}
+			store.tasks["upcoming"] = &models.Task{ID: "upcoming", UserID: "user-1", Status: "todo", DueDate: at(time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC))}
+			store.tasks["other-user"] = &models.Task{ID: "other-user", UserID: "user-2", Status: "todo", DueDate: at(time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC))}
+			h := NewTaskHandler(store, nil, nil, testConfig(now))
+
+			w := serve(h.RescheduleOverdue, http.MethodPost, "/tasks/reschedule-overdue", "/tasks/reschedule-overdue", tt.body, "user-1")
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				if len(store.taskBatch) != 0 {
+					t.Errorf("%d batches written, want none", len(store.taskBatch))
+				}
+				return
+			}
+
+			var resp struct {
+				Rescheduled []string `json:"rescheduled"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			slices.Sort(resp.Rescheduled)
+			if want := []string{"overdue", "with-start"}; !slices.Equal(resp.Rescheduled, want) {
+				t.Errorf("rescheduled = %v, want %v", resp.Rescheduled, want)
+			}
+			if len(store.taskBatch) != 1 {
+				t.Fatalf("%d batches written, want 1", len(store.taskBatch))
+			}
+			batch := store.taskBatch[0]
+			for id, want := range tt.want {
+				if got, _ := batch[id]["dueDate"].(time.Time); !got.Equal(want) {
+					t.Errorf("%s dueDate = %v, want %v", id, got, want)
+				}
+			}
+			// The start date moves with the due date, keeping the task a day long
+			start, _ := batch["with-start"]["startDate"].(time.Time)
+			if due, _ := batch["with-start"]["dueDate"].(time.Time); due.Sub(start) != 24*time.Hour {
+				t.Errorf("startDate = %v, want a day before %v", start, due)
+			}
+		})
+	}
+}
+
+func TestRescheduleOverdueNothingDue(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	due := now.Add(48 * time.Hour)
+	store := newMockStore()
+	store.tasks["upcoming"] = &models.Task{ID: "upcoming", UserID: "user-1", Status: "todo", DueDate: &due}
+	h := NewTaskHandler(store, nil, nil, testConfig(now))
+
+	w := serve(h.RescheduleOverdue, http.MethodPost, "/tasks/reschedule-overdue", "/tasks/reschedule-overdue", `{"shiftDays": 1}`, "user-1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	if len(store.taskBatch) != 0 {
+		t.Errorf("%d batches written, want none", len(store.taskBatch))
+	}
+}
+
+func TestDeleteCompletedTasks(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	store := newMockStore()
+	store.tasks["done-1"] = &models.Task{ID: "done-1", UserID: "user-1", Status: "completed"}
+	store.tasks["done-2"] = &models.Task{ID: "done-2", UserID: "user-1", Status: "completed"}
+	store.tasks["open"] = &models.Task{ID: "open", UserID: "user-1", Status: "in-progress"}
+	store.tasks["other-done"] = &models.Task{ID: "other-done", UserID: "user-2", Status: "completed"}
+	taskID := "done-1"
+	store.reminders["linked"] = &models.Reminder{ID: "linked", UserID: "user-1", TaskID: &taskID}
+	store.reminders["unlinked"] = &models.Reminder{ID: "unlinked", UserID: "user-1"}
+	h := NewTaskHandler(store, nil, nil, testConfig(now))
+
+	w := serve(h.DeleteCompletedTasks, http.MethodDelete, "/tasks/completed", "/tasks/completed", "", "user-1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Deleted int `json:"deleted"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Deleted != 2 {
+		t.Errorf("deleted = %d, want 2", resp.Deleted)
+	}
+
+	var remaining []string
+	for id := range store.tasks {
+		remaining = append(remaining, id)
+	}
+	slices.Sort(remaining)
+	if want := []string{"open", "other-done"}; !slices.Equal(remaining, want) {
+		t.Errorf("tasks left = %v, want %v", remaining, want)
+	}
+	if _, ok := store.reminders["linked"]; ok {
+		t.Error("reminder of a deleted task was kept")
+	}
+	if _, ok := store.reminders["unlinked"]; !ok {
+		t.Error("unrelated reminder was deleted")
+	}
+}
+
+func TestGetTasksFields(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	description := "Quarterly numbers"
+
+	tests := []struct {
+		name     string
+		query    string
+		want     int
+		wantKeys []string
+	}{
+		{"projected", "?fields=id,title,status", http.StatusOK, []string{"id", "status", "title"}},
+		{"spaces and empty names ignored", "?fields=id,%20title,,", http.StatusOK, []string{"id", "title"}},
+		{"unknown field", "?fields=id,secret", http.StatusBadRequest, nil},
+		{"Go field name rather than JSON", "?fields=UserID", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.tasks["report"] = &models.Task{ID: "report", UserID: "user-1", Title: "Write report", Description: &description, Status: "todo", Priority: "high"}
+			h := NewTaskHandler(store, nil, nil, testConfig(now))
+
+			w := serve(h.GetTasks, http.MethodGet, "/tasks", "/tasks"+tt.query, "", "user-1")
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+
+			var items []map[string]interface{}
+			decode(t, w, &items)
+			if len(items) != 1 {
+				t.Fatalf("items = %v, want one task", items)
+			}
+			var keys []string
+			for key := range items[0] {
+				keys = append(keys, key)
+			}
+			slices.Sort(keys)
+			if !slices.Equal(keys, tt.wantKeys) {
+				t.Errorf("fields = %v, want %v", keys, tt.wantKeys)
+			}
+			if items[0]["id"] != "report" {
+				t.Errorf("id = %v, want %q", items[0]["id"], "report")
+			}
+		})
+	}
+}
+
+func TestGetTaskBacklog(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	due := now.AddDate(0, 0, 3)
+	store := newMockStore()
+	for i, task := range []*models.Task{
+		{ID: "old-medium", Priority: "medium", Status: "todo"},
+		{ID: "low", Priority: "low", Status: "todo"},
+		{ID: "dated-urgent", Priority: "urgent", Status: "todo", DueDate: &due},
+		{ID: "new-medium", Priority: "medium", Status: "in-progress"},
+		{ID: "done-urgent", Priority: "urgent", Status: "completed"},
+		{ID: "urgent", Priority: "urgent", Status: "todo"},
+		{ID: "other-user", UserID: "user-2", Priority: "urgent", Status: "todo"},
+	} {
+		if task.UserID == "" {
+			task.UserID = "user-1"
+		}
+		task.CreatedAt = now.Add(time.Duration(i-10) * time.Hour)
+		store.tasks[task.ID] = task
+	}
+	h := NewTaskHandler(store, nil, nil, testConfig(now))
+
+	w := serve(h.GetTaskBacklog, http.MethodGet, "/tasks/backlog", "/tasks/backlog", "", "user-1")
+	var backlog []models.Task
+	decode(t, w, &backlog)
+
+	var ids []string
+	for _, task := range backlog {
+		ids = append(ids, task.ID)
+	}
+	if want := []string{"urgent", "old-medium", "new-medium", "low"}; !slices.Equal(ids, want) {
+		t.Errorf("backlog = %v, want %v", ids, want)
+	}
+}
+
+func TestGetTaskAgenda(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC) // 18:00 in Tokyo
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(day, hour int) *time.Time {
+		due := time.Date(2026, 10, day, hour, 0, 0, 0, tokyo)
+		return &due
+	}
+
+	store := newMockStore()
+	for _, task := range []*models.Task{
+		{ID: "yesterday", Status: "todo", Priority: "low", DueDate: at(15, 12)},
+		{ID: "today-low", Status: "todo", Priority: "low", DueDate: at(16, 10)}, // earlier today still counts as today
+		{ID: "today-urgent", Status: "in-progress", Priority: "urgent", DueDate: at(16, 20)},
+		{ID: "tomorrow-in-tokyo", Status: "todo", Priority: "medium", DueDate: at(17, 1)}, // still the 16th in UTC
+		{ID: "after-range", Status: "todo", Priority: "high", DueDate: at(19, 9)},
+		{ID: "undated", Status: "todo", Priority: "medium"},
+		{ID: "done", Status: "completed", Priority: "high", DueDate: at(14, 9)},
+	} {
+		task.UserID = "user-1"
+		store.tasks[task.ID] = task
+	}
+	h := NewTaskHandler(store, nil, nil, testConfig(now))
+
+	var agenda struct {
+		Timezone string             `json:"timezone"`
+		Overdue  []*models.Task     `json:"overdue"`
+		Days     []models.AgendaDay `json:"days"`
+		NoDate   []*models.Task     `json:"noDate"`
+	}
+	decode(t, serve(h.GetTaskAgenda, http.MethodGet, "/tasks/agenda", "/tasks/agenda?from=2026-10-16&to=2026-10-18&tz=Asia/Tokyo", "", "user-1"), &agenda)
+
+	ids := func(tasks []*models.Task) []string {
+		var ids []string
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+	var buckets []string
+	for _, day := range agenda.Days {
+		buckets = append(buckets, day.Date+": "+strings.Join(ids(day.Tasks), ","))
+	}
+	if want := []string{"2026-10-16: today-urgent,today-low", "2026-10-17: tomorrow-in-tokyo"}; !slices.Equal(buckets, want) {
+		t.Errorf("days = %v, want %v", buckets, want)
+	}
+	if got := ids(agenda.Overdue); !slices.Equal(got, []string{"yesterday"}) {
+		t.Errorf("overdue = %v, want [yesterday]", got)
+	}
+	if got := ids(agenda.NoDate); !slices.Equal(got, []string{"undated"}) {
+		t.Errorf("no date = %v, want [undated]", got)
+	}
+	if agenda.Timezone != "Asia/Tokyo" {
+		t.Errorf("timezone = %q, want Asia/Tokyo", agenda.Timezone)
+	}
+
+	for _, query := range []string{"?from=2026-10-18&to=2026-10-16", "?from=2026-10-16&to=2027-10-16", "?from=16/10/2026", "?tz=Mars/Olympus"} {
+		if w := serve(h.GetTaskAgenda, http.MethodGet, "/tasks/agenda", "/tasks/agenda"+query, "", "user-1"); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", query, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestGetTasksContentNegotiation(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 10, 20, 17, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		accept   string
+		query    string
+		want     int
+		wantType string
+		wantBody string
+	}{
+		{"JSON by default", "", "", http.StatusOK, "application/json", ""},
+		{"JSON when asked", "application/json", "", http.StatusOK, "application/json", ""},
+		{"CSV when asked", "text/csv", "?fields=id,title,tags,dueDate", http.StatusOK, "text/csv", "id,title,tags,dueDate\nreport,\"Write, then send\",work;q4,2026-10-20T17:00:00Z\n"},
+		{"CSV listed first", "text/csv, application/json", "?fields=id,completed", http.StatusOK, "text/csv", "id,completed\nreport,false\n"},
+		{"CSV with an unknown column", "text/csv", "?fields=secret", http.StatusBadRequest, "application/json", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.tasks["report"] = &models.Task{ID: "report", UserID: "user-1", Title: "Write, then send", Tags: []string{"work", "q4"}, DueDate: &due, Status: "todo"}
+			h := NewTaskHandler(store, nil, nil, testConfig(now))
+
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.GET("/tasks", func(c *gin.Context) {
+				c.Set("user", &models.UserSession{UserID: "user-1"})
+				h.GetTasks(c)
+			})
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/tasks"+tt.query, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, tt.wantType) {
+				t.Errorf("Content-Type = %q, want %s", contentType, tt.wantType)
+			}
+			if tt.wantType == "application/json" && tt.want == http.StatusOK {
+				var tasks []models.Task
+				decode(t, w, &tasks)
+				if len(tasks) != 1 || tasks[0].Title != "Write, then send" {
+					t.Errorf("tasks = %+v", tasks)
+				}
+			}
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestQuickAddTask(t *testing.T) {
+	// Friday evening in UTC, already Saturday morning in Tokyo
+	now := time.Date(2026, 10, 16, 20, 0, 0, 0, time.UTC)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		timezone     string
+		body         string
+		want         int
+		wantDue      time.Time
+		wantPriority string
+	}{
+		{"user's time zone", "Asia/Tokyo", `{"text": "Call bank tomorrow 9am #money"}`, http.StatusCreated, time.Date(2026, 10, 18, 9, 0, 0, 0, tokyo), "medium"},
+		{"UTC without one", "", `{"text": "Call bank tomorrow 9am #money !urgent"}`, http.StatusCreated, time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC), "urgent"},
+		{"nothing left for a title", "", `{"text": "tomorrow #money !high"}`, http.StatusBadRequest, time.Time{}, ""},
+		{"text missing", "", `{}`, http.StatusBadRequest, time.Time{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.users["user-1"] = &models.UserSession{UserID: "user-1", Timezone: tt.timezone}
+			h := NewTaskHandler(store, nil, nil, testConfig(now))
+
+			w := serve(h.QuickAddTask, http.MethodPost, "/tasks/quick-add", "/tasks/quick-add", tt.body, "user-1")
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want != http.StatusCreated {
+				if len(store.tasks) != 0 {
+					t.Errorf("tasks = %v, want none created", store.tasks)
+				}
+				return
+			}
+
+			var response struct {
+				Task   models.Task                   `json:"task"`
+				Parsed models.QuickAddInterpretation `json:"parsed"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			stored := store.tasks[response.Task.ID]
+			if stored == nil || stored.Title != "Call bank" || !slices.Equal(stored.Tags, []string{"money"}) || stored.Priority != tt.wantPriority {
+				t.Fatalf("stored task = %+v", stored)
+			}
+			if stored.DueDate == nil || !stored.DueDate.Equal(tt.wantDue) {
+				t.Errorf("due = %v, want %v", stored.DueDate, tt.wantDue)
+			}
+			if response.Parsed.DateText != "tomorrow 9am" || response.Parsed.Timezone != cmp.Or(tt.timezone, "UTC") {
+				t.Errorf("interpretation = %+v", response.Parsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+// Package ical reads events out of iCalendar (RFC 5545) files. Only the VEVENT
+// properties that map onto meetings are kept; everything else, including
+// nested components such as VALARM, is skipped.
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"focusflow-be/internal/recurrence"
+)
+
+var ErrInvalidCalendar = errors.New("invalid iCalendar file")
+
+// Event is a single VEVENT. Err is set when one of its properties could not
+// be parsed, so callers can report the event without failing the whole file.
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	Status       string // TENTATIVE, CONFIRMED or CANCELLED
+	Start        time.Time
+	End          time.Time     // zero when the event has no DTEND
+	Duration     time.Duration // from DURATION, used when End is zero
+	AllDay       bool
+	Attendees    []string // email addresses
+	RRule        string
+	ExDates      []time.Time
+	RecurrenceID *time.Time // set on an edited instance of a recurring event
+	Err          error
+}
+
+// Parse reads every VEVENT in r. It fails only when r isn't an iCalendar
+// file at all; problems with individual events are recorded in Event.Err.
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, fmt.Errorf("%w: missing BEGIN:VCALENDAR", ErrInvalidCalendar)
+	}
+
+	var events []Event
+	var current *Event
+	nested := 0 // depth of components inside the current VEVENT
+	for _, line := range lines {
+		name, params, value := splitLine(line)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT") && current == nil:
+			current = &Event{}
+		case name == "BEGIN" && current != nil:
+			nested++
+		case name == "END" && current != nil && nested > 0:
+			nested--
+		case name == "END" && strings.EqualFold(value, "VEVENT") && current != nil:
+			if current.Err == nil && current.Start.IsZero() {
+				current.Err = errors.New("missing DTSTART")
+			}
+			events = append(events, *current)
+			current = nil
+		case current != nil && nested == 0:
+			if err := current.set(name, params, value); err != nil && current.Err == nil {
+				current.Err = fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+	if current != nil {
+		return nil, fmt.Errorf("%w: unterminated VEVENT", ErrInvalidCalendar)
+	}
+	return events, nil
+}
+
+func (e *Event) set(name string, params map[string]string, value string) error {
+	switch name {
+	case "UID":
+		e.UID = value
+	case "SUMMARY":
+		e.Summary = unescape(value)
+	case "DESCRIPTION":
+		e.Description = unescape(value)
+	case "LOCATION":
+		e.Location = unescape(value)
+	case "STATUS":
+		e.Status = strings.ToUpper(value)
+	case "DTSTART":
+		start, allDay, err := parseTime(params, value)
+		if err != nil {
+			return err
+		}
+		e.Start, e.AllDay = start, allDay
+	case "DTEND":
+		end, _, err := parseTime(params, value)
+		if err != nil {
+			return err
+		}
+		e.End = end
+	case "DURATION":
+		duration, err := parseDuration(value)
+		if err != nil {
+			return err
+		}
+		e.Duration = duration
+	case "ATTENDEE":
+		if email, ok := cutPrefixFold(value, "mailto:"); ok {
+			e.Attendees = append(e.Attendees, email)
+		}
+	case "RRULE":
+		e.RRule = value
+	case "EXDATE":
+		for _, v := range strings.Split(value, ",") {
+			exdate, _, err := parseTime(params, v)
+			if err != nil {
+				return err
+			}
+			e.ExDates = append(e.ExDates, exdate)
+		}
+	case "RECURRENCE-ID":
+		id, _, err := parseTime(params, value)
+		if err != nil {
+			return err
+		}
+		e.RecurrenceID = &id
+	}
+	return nil
+}
+
+// Rule converts the event's RRULE into a recurrence rule, or returns nil when
+// the event doesn't repeat. Only the parts the recurrence package can honour
+// are accepted; BYDAY is allowed for weekly rules when it names the start day.
+func (e Event) Rule() (*recurrence.Rule, error) {
+	if e.RRule == "" {
+		return nil, nil
+	}
+
+	rule := &recurrence.Rule{}
+	var byDay string
+	for _, part := range strings.Split(e.RRule, ";") {
+		key, value, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Frequency = recurrence.Frequency(strings.ToLower(value))
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid RRULE INTERVAL %q", value)
+			}
+			rule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid RRULE COUNT %q", value)
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, _, err := parseTime(nil, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid RRULE UNTIL %q", value)
+			}
+			rule.Until = &until
+		case "BYDAY":
+			byDay = strings.ToUpper(value)
+		case "WKST":
+		default:
+			return nil, fmt.Errorf("unsupported RRULE part %s", key)
+		}
+	}
+
+	if byDay != "" {
+		startDay := strings.ToUpper(e.Start.Weekday().String()[:2])
+		if rule.Frequency != recurrence.Weekly || byDay != startDay {
+			return nil, fmt.Errorf("unsupported RRULE BYDAY=%s", byDay)
+		}
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// unfold joins continuation lines (those starting with a space or tab) onto
+// the line before them and drops blank lines.
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCalendar, err)
+	}
+	return lines, nil
+}
+
+// splitLine breaks a content line into its upper-cased name, its parameters
+// and its value. Colons and semicolons inside quoted parameter values don't
+// count as separators.
+func splitLine(line string) (string, map[string]string, string) {
+	quoted := false
+	end := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return strings.ToUpper(line), nil, ""
+	}
+
+	head, value := line[:end], line[end+1:]
+	var parts []string
+	start := 0
+	quoted = false
+	for i, r := range head {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ';' && !quoted {
+			parts = append(parts, head[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, head[start:])
+
+	params := make(map[string]string, len(parts)-1)
+	for _, part := range parts[1:] {
+		key, val, _ := strings.Cut(part, "=")
+		params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+// parseTime reads a DATE or DATE-TIME value. Times with a TZID are read in
+// that zone, and floating times are taken as UTC.
+func parseTime(params map[string]string, value string) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.Parse("20060102", value)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+
+	loc := time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, false, fmt.Errorf("unknown TZID %q", tzid)
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseDuration reads an RFC 5545 duration such as PT1H30M or P1D
+func parseDuration(value string) (time.Duration, error) {
+	match := durationPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil || value == "P" || value == "PT" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var duration time.Duration
+	for i, unit := range units {
+		if match[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i+2])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		duration += time.Duration(n) * unit
+	}
+	if match[1] == "-" {
+		duration = -duration
+	}
+	return duration, nil
+}
+
+var textEscapes = strings.NewReplacer(`\\`, `\`, `\;`, `;`, `\,`, `,`, `\n`, "\n", `\N`, "\n")
+
+func unescape(value string) string {
+	return textEscapes.Replace(value)
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+		return s[len(prefix):], true
+	}
+	return s, false
+}
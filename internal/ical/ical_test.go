@@ -0,0 +1,228 @@
+package ical
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"focusflow-be/internal/recurrence"
+)
+
+// calendar wraps content lines in a VCALENDAR with CRLF line endings
+func calendar(lines ...string) string {
+	all := append([]string{"BEGIN:VCALENDAR", "VERSION:2.0"}, lines...)
+	all = append(all, "END:VCALENDAR")
+	return strings.Join(all, "\r\n") + "\r\n"
+}
+
+func TestParse(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recurrenceID := time.Date(2026, 10, 27, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []Event
+		wantErr []bool // per event
+	}{
+		{
+			name: "timed event",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"UID:abc@example.com",
+				"SUMMARY:Planning\\, Q4",
+				"DESCRIPTION:Line one\\nLine two",
+				"LOCATION:Room 1\\; east wing",
+				"STATUS:tentative",
+				"DTSTART:20261020T090000Z",
+				"DTEND:20261020T100000Z",
+				"ATTENDEE;CN=\"Doe: Jane\";ROLE=REQ-PARTICIPANT:MAILTO:jane@example.com",
+				"ATTENDEE:urn:uuid:not-an-email",
+				"END:VEVENT",
+			),
+			want: []Event{{
+				UID:         "abc@example.com",
+				Summary:     "Planning, Q4",
+				Description: "Line one\nLine two",
+				Location:    "Room 1; east wing",
+				Status:      "TENTATIVE",
+				Start:       time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC),
+				End:         time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC),
+				Attendees:   []string{"jane@example.com"},
+			}},
+			wantErr: []bool{false},
+		},
+		{
+			name: "folded lines",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"SUMMARY:A very long",
+				"  meeting title",
+				"DTSTART:20261020T090000Z",
+				"END:VEVENT",
+			),
+			want:    []Event{{Summary: "A very long meeting title", Start: time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)}},
+			wantErr: []bool{false},
+		},
+		{
+			name: "time zone, duration and all-day",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"DTSTART;TZID=Europe/Berlin:20261020T090000",
+				"DURATION:PT1H30M",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"DTSTART;VALUE=DATE:20261021",
+				"END:VEVENT",
+			),
+			want: []Event{
+				{Start: time.Date(2026, 10, 20, 9, 0, 0, 0, berlin), Duration: 90 * time.Minute},
+				{Start: time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC), AllDay: true},
+			},
+			wantErr: []bool{false, false},
+		},
+		{
+			name: "recurrence properties",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"DTSTART:20261020T090000Z",
+				"RRULE:FREQ=WEEKLY;COUNT=4",
+				"EXDATE:20261027T090000Z,20261103T090000Z",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"DTSTART:20261028T090000Z",
+				"RECURRENCE-ID:20261027T090000Z",
+				"END:VEVENT",
+			),
+			want: []Event{
+				{
+					Start:   time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC),
+					RRule:   "FREQ=WEEKLY;COUNT=4",
+					ExDates: []time.Time{time.Date(2026, 10, 27, 9, 0, 0, 0, time.UTC), time.Date(2026, 11, 3, 9, 0, 0, 0, time.UTC)},
+				},
+				{Start: time.Date(2026, 10, 28, 9, 0, 0, 0, time.UTC), RecurrenceID: &recurrenceID},
+			},
+			wantErr: []bool{false, false},
+		},
+		{
+			name: "nested alarm is skipped",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"SUMMARY:Standup",
+				"DTSTART:20261020T090000Z",
+				"BEGIN:VALARM",
+				"SUMMARY:Alarm",
+				"DTSTART:garbage",
+				"END:VALARM",
+				"END:VEVENT",
+			),
+			want:    []Event{{Summary: "Standup", Start: time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)}},
+			wantErr: []bool{false},
+		},
+		{
+			name: "bad events are reported one by one",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"SUMMARY:No start",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"DTSTART;TZID=Mars/Olympus:20261020T090000",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"DTSTART:20261020T090000Z",
+				"DURATION:1 hour",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"SUMMARY:Fine",
+				"DTSTART:20261020T090000Z",
+				"END:VEVENT",
+			),
+			want: []Event{
+				{Summary: "No start"},
+				{},
+				{Start: time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)},
+				{Summary: "Fine", Start: time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)},
+			},
+			wantErr: []bool{true, true, true, false},
+		},
+		{
+			name:    "no events",
+			input:   calendar(),
+			want:    nil,
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(events) != len(tt.want) {
+				t.Fatalf("got %d events, want %d", len(events), len(tt.want))
+			}
+			for i, event := range events {
+				if (event.Err != nil) != tt.wantErr[i] {
+					t.Errorf("event %d err = %v, want error %v", i, event.Err, tt.wantErr[i])
+				}
+				event.Err = nil
+				if !reflect.DeepEqual(event, tt.want[i]) {
+					t.Errorf("event %d = %+v, want %+v", i, event, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseInvalidCalendar(t *testing.T) {
+	tests := map[string]string{
+		"empty":              "",
+		"not a calendar":     "hello world\r\n",
+		"unterminated event": "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART:20261020T090000Z\r\n",
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(input)); !errors.Is(err, ErrInvalidCalendar) {
+				t.Errorf("err = %v, want ErrInvalidCalendar", err)
+			}
+		})
+	}
+}
+
+func TestEventRule(t *testing.T) {
+	tuesday := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		rrule   string
+		want    *recurrence.Rule
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"FREQ=DAILY", &recurrence.Rule{Frequency: recurrence.Daily}, false},
+		{"FREQ=WEEKLY;INTERVAL=2;COUNT=5", &recurrence.Rule{Frequency: recurrence.Weekly, Interval: 2, Count: 5}, false},
+		{"FREQ=MONTHLY;UNTIL=20261201T000000Z", &recurrence.Rule{Frequency: recurrence.Monthly, Until: &until}, false},
+		{"FREQ=WEEKLY;BYDAY=TU;WKST=MO", &recurrence.Rule{Frequency: recurrence.Weekly}, false},
+		{"FREQ=WEEKLY;BYDAY=MO,WE", nil, true},
+		{"FREQ=MONTHLY;BYDAY=TU", nil, true},
+		{"FREQ=DAILY;BYHOUR=9", nil, true},
+		{"FREQ=HOURLY", nil, true},
+		{"FREQ=DAILY;COUNT=many", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rrule, func(t *testing.T) {
+			rule, err := Event{Start: tuesday, RRule: tt.rrule}.Rule()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(rule, tt.want) {
+				t.Errorf("rule = %+v, want %+v", rule, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// Package locale renders dates in a human-friendly form using a user's
+// language preference. Machine-readable fields should keep using RFC3339.
+package locale
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Default is used when a user has no preference or an unsupported one
+const Default = "en"
+
+type language struct {
+	weekdays [7]string  // Sunday first, matching time.Weekday
+	months   [12]string // January first
+	layout   string     // placeholders: {weekday} {day} {month} {year} {time}
+	clock24  bool
+}
+
+var languages = map[string]language{
+	"en": {
+		weekdays: [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		months:   [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		layout:   "{weekday}, {month} {day}, {year} {time}",
+	},
+	"es": {
+		weekdays: [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		months:   [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		layout:   "{weekday}, {day} de {month} de {year} {time}",
+		clock24:  true,
+	},
+	"fr": {
+		weekdays: [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		months:   [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		layout:   "{weekday} {day} {month} {year} {time}",
+		clock24:  true,
+	},
+	"de": {
+		weekdays: [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		months:   [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		layout:   "{weekday}, {day}. {month} {year} {time}",
+		clock24:  true,
+	},
+	"pt": {
+		weekdays: [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		months:   [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		layout:   "{weekday}, {day} de {month} de {year} {time}",
+		clock24:  true,
+	},
+	"it": {
+		weekdays: [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		months:   [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		layout:   "{weekday} {day} {month} {year} {time}",
+		clock24:  true,
+	},
+}
+
+// Normalize reduces a tag such as "en-GB" or "pt_BR" to a supported base
+// language, falling back to Default
+func Normalize(tag string) string {
+	base := strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(base, "-_"); i >= 0 {
+		base = base[:i]
+	}
+	if _, ok := languages[base]; ok {
+		return base
+	}
+	return Default
+}
+
+// Supported reports whether tag reduces to one of the supported languages
+func Supported(tag string) bool {
+	base := strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(base, "-_"); i >= 0 {
+		base = base[:i]
+	}
+	_, ok := languages[base]
+	return ok
+}
+
+// FormatDateTime renders t with localized weekday and month names
+func FormatDateTime(t time.Time, tag string) string {
+	lang := languages[Normalize(tag)]
+
+	clock := t.Format("3:04 PM")
+	if lang.clock24 {
+		clock = t.Format("15:04")
+	}
+
+	replacer := strings.NewReplacer(
+		"{weekday}", lang.weekdays[t.Weekday()],
+		"{day}", fmt.Sprintf("%d", t.Day()),
+		"{month}", lang.months[t.Month()-1],
+		"{year}", fmt.Sprintf("%d", t.Year()),
+		"{time}", clock,
+	)
+	return replacer.Replace(lang.layout)
+}
@@ -0,0 +1,54 @@
+package locale
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatDateTime(t *testing.T) {
+	afternoon := time.Date(2026, 10, 16, 14, 5, 0, 0, time.UTC)
+
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"en", "Friday, October 16, 2026 2:05 PM"},
+		{"en-GB", "Friday, October 16, 2026 2:05 PM"},
+		{"es", "viernes, 16 de octubre de 2026 14:05"},
+		{"fr", "vendredi 16 octobre 2026 14:05"},
+		{"de_DE", "Freitag, 16. Oktober 2026 14:05"},
+		{"pt-BR", "sexta-feira, 16 de outubro de 2026 14:05"},
+		{"it", "venerdì 16 ottobre 2026 14:05"},
+		{"xx", "Friday, October 16, 2026 2:05 PM"},
+		{"", "Friday, October 16, 2026 2:05 PM"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := FormatDateTime(afternoon, tt.tag); got != tt.want {
+				t.Errorf("FormatDateTime(%q) = %q, want %q", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		tag       string
+		want      string
+		supported bool
+	}{
+		{"fr", "fr", true},
+		{" FR-ca ", "fr", true},
+		{"pt_BR", "pt", true},
+		{"nl", Default, false},
+		{"", Default, false},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.tag); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+		if got := Supported(tt.tag); got != tt.supported {
+			t.Errorf("Supported(%q) = %v, want %v", tt.tag, got, tt.supported)
+		}
+	}
+}
@@ -1,16 +1,163 @@
 package middleware
 
 import (
+	"container/list"
+	"errors"
+	"log"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"focusflow-be/internal/clock"
 	"focusflow-be/internal/services"
 )
 
-func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
+// UserChecker confirms that a token's subject still has an account, so tokens
+// issued before an account was deleted stop working. Users seen recently are
+// cached for ttl to avoid a Firestore read on every request; the cache holds
+// at most size users, evicting the least recently used.
+type UserChecker struct {
+	store services.Store
+	clock clock.Clock
+	ttl   time.Duration
+	size  int
+
+	mu      sync.Mutex
+	order   *list.List               // most recently used at the front
+	checked map[string]*list.Element // userID -> element holding a checkedUser
+}
+
+type checkedUser struct {
+	userID string
+	expiry time.Time
+}
+
+func NewUserChecker(store services.Store, clk clock.Clock, ttl time.Duration, size int) *UserChecker {
+	if size < 1 {
+		size = 1
+	}
+	return &UserChecker{
+		store:   store,
+		clock:   clk,
+		ttl:     ttl,
+		size:    size,
+		order:   list.New(),
+		checked: make(map[string]*list.Element),
+	}
+}
+
+func (u *UserChecker) exists(userID string) (bool, error) {
+	if u.cached(userID) {
+		return true, nil
+	}
+
+	if _, err := u.store.GetUser(userID); err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	u.remember(userID)
+	return true, nil
+}
+
+func (u *UserChecker) cached(userID string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	elem, ok := u.checked[userID]
+	if !ok {
+		return false
+	}
+	if !u.clock.Now().Before(elem.Value.(*checkedUser).expiry) {
+		u.order.Remove(elem)
+		delete(u.checked, userID)
+		return false
+	}
+	u.order.MoveToFront(elem)
+	return true
+}
+
+func (u *UserChecker) remember(userID string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	expiry := u.clock.Now().Add(u.ttl)
+	if elem, ok := u.checked[userID]; ok {
+		elem.Value.(*checkedUser).expiry = expiry
+		u.order.MoveToFront(elem)
+		return
+	}
+
+	u.checked[userID] = u.order.PushFront(&checkedUser{userID: userID, expiry: expiry})
+	for u.order.Len() > u.size {
+		oldest := u.order.Back()
+		u.order.Remove(oldest)
+		delete(u.checked, oldest.Value.(*checkedUser).userID)
+	}
+}
+
+// Forget drops a user from the cache so the next request looks them up again.
+// It's registered with the Firebase service to run whenever a user document
+// changes.
+func (u *UserChecker) Forget(userID string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if elem, ok := u.checked[userID]; ok {
+		u.order.Remove(elem)
+		delete(u.checked, userID)
+	}
+}
+
+// publicRoutes matches the registered routes that skip authentication. A
+// pattern ending in "*" matches every route that starts with the rest of it.
+type publicRoutes struct {
+	exact    map[string]bool
+	prefixes []string
+}
+
+func newPublicRoutes(patterns []string) publicRoutes {
+	routes := publicRoutes{exact: make(map[string]bool, len(patterns))}
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			routes.prefixes = append(routes.prefixes, prefix)
+		} else {
+			routes.exact[pattern] = true
+		}
+	}
+	return routes
+}
+
+func (p publicRoutes) match(route string) bool {
+	if p.exact[route] {
+		return true
+	}
+	for _, prefix := range p.prefixes {
+		if strings.HasPrefix(route, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthMiddleware verifies the bearer token. When users is non-nil the token's
+// subject must also still exist. Routes matching one of public (as registered,
+// e.g. "/auth/google" or "/admin/*") pass without a token, as do requests that
+// match no route so they still get the router's 404.
+func AuthMiddleware(authService *services.AuthService, users *UserChecker, public ...string) gin.HandlerFunc {
+	publicRoutes := newPublicRoutes(public)
+
 	return func(c *gin.Context) {
+		if route := c.FullPath(); route == "" || publicRoutes.match(route) {
+			c.Next()
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
@@ -25,6 +172,7 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 			return
 		}
 
+		started := time.Now()
 		token := parts[1]
 		userSession, err := authService.VerifyJWT(token)
 		if err != nil {
@@ -33,6 +181,22 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 			return
 		}
 
+		if users != nil {
+			exists, err := users.exists(userSession.UserID)
+			if err != nil {
+				log.Printf("⚠️ Failed to look up user %s: %v", userSession.UserID, err)
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to verify user"})
+				c.Abort()
+				return
+			}
+			if !exists {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found", "code": "user_not_found"})
+				c.Abort()
+				return
+			}
+		}
+
+		RecordTiming(c, "auth", time.Since(started))
 		c.Set("user", userSession)
 		c.Next()
 	}
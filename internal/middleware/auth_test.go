@@ -0,0 +1,220 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"focusflow-be/internal/clock"
+	"focusflow-be/internal/config"
+	"focusflow-be/internal/models"
+	"focusflow-be/internal/services"
+)
+
+// userStore answers GetUser from users, counting lookups; anything else panics
+type userStore struct {
+	services.Store
+	users   map[string]bool
+	err     error
+	lookups int
+}
+
+func (s *userStore) GetUser(userID string) (*models.UserSession, error) {
+	s.lookups++
+	if s.err != nil {
+		return nil, s.err
+	}
+	if !s.users[userID] {
+		return nil, services.ErrNotFound
+	}
+	return &models.UserSession{UserID: userID}, nil
+}
+
+type authFixture struct {
+	router *gin.Engine
+	auth   *services.AuthService
+	clock  *clock.Fake
+	store  *userStore
+	users  *UserChecker
+}
+
+func newAuthFixture(t *testing.T, check bool, size int) *authFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	clk := clock.NewFake(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+	auth, err := services.NewAuthService(&config.Config{
+		JWTAlgorithm: "HS256",
+		JWTSecret:    strings.Repeat("s", 32),
+		Clock:        clk,
+	})
+	if err != nil {
+		t.Fatalf("NewAuthService: %v", err)
+	}
+
+	f := &authFixture{auth: auth, clock: clk, store: &userStore{users: map[string]bool{"alice": true, "bob": true}}}
+	if check {
+		f.users = NewUserChecker(f.store, clk, time.Minute, size)
+	}
+	f.router = gin.New()
+	f.router.Use(AuthMiddleware(auth, f.users, "/public"))
+	f.router.GET("/me", func(c *gin.Context) {
+		user, _ := c.Get("user")
+		c.JSON(http.StatusOK, gin.H{"id": user.(*models.UserSession).UserID})
+	})
+	f.router.GET("/public", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	return f
+}
+
+// get requests /me as userID and returns the status and the body's code
+func (f *authFixture) get(t *testing.T, userID string) (int, string) {
+	t.Helper()
+	token, err := f.auth.CreateJWT(&models.UserSession{UserID: userID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+
+	var body struct {
+		Code string `json:"code"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &body)
+	return w.Code, body.Code
+}
+
+func TestAuthMiddlewareDeletedUser(t *testing.T) {
+	f := newAuthFixture(t, true, 10)
+
+	if code, _ := f.get(t, "alice"); code != http.StatusOK {
+		t.Fatalf("existing user: status = %d, want %d", code, http.StatusOK)
+	}
+	code, errCode := f.get(t, "deleted")
+	if code != http.StatusUnauthorized || errCode != "user_not_found" {
+		t.Errorf("deleted user: status = %d, code = %q, want %d user_not_found", code, errCode, http.StatusUnauthorized)
+	}
+
+	// A user deleted while cached is rejected once the change is seen
+	delete(f.store.users, "alice")
+	f.users.Forget("alice")
+	if code, errCode := f.get(t, "alice"); code != http.StatusUnauthorized || errCode != "user_not_found" {
+		t.Errorf("user deleted after login: status = %d, code = %q, want %d user_not_found", code, errCode, http.StatusUnauthorized)
+	}
+}
+
+func TestAuthMiddlewareLookupFailure(t *testing.T) {
+	f := newAuthFixture(t, true, 10)
+	f.store.err = errors.New("firestore unavailable")
+
+	if code, _ := f.get(t, "alice"); code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestAuthMiddlewareWithoutUserCheck(t *testing.T) {
+	f := newAuthFixture(t, false, 0)
+
+	if code, _ := f.get(t, "deleted"); code != http.StatusOK {
+		t.Errorf("status = %d, want %d with the check off", code, http.StatusOK)
+	}
+	if f.store.lookups != 0 {
+		t.Errorf("%d lookups, want none", f.store.lookups)
+	}
+
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/public", nil))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("public route: status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
+
+func TestUserCheckerCache(t *testing.T) {
+	// Each step is a request as a user, or an event; lookups is the running
+	// count of Firestore reads expected after it
+	type step struct {
+		user    string
+		event   string
+		lookups int
+	}
+
+	tests := []struct {
+		name  string
+		size  int
+		steps []step
+	}{
+		{"miss then hit", 10, []step{{user: "alice", lookups: 1}, {user: "alice", lookups: 1}}},
+		{"expired entry is a miss", 10, []step{{user: "alice", lookups: 1}, {event: "advance 59s", lookups: 1}, {user: "alice", lookups: 1}, {event: "advance 1m", lookups: 1}, {user: "alice", lookups: 2}}},
+		{"update invalidates", 10, []step{{user: "alice", lookups: 1}, {event: "forget alice", lookups: 1}, {user: "alice", lookups: 2}, {user: "alice", lookups: 2}}},
+		{"forgetting one user keeps others", 10, []step{{user: "alice", lookups: 1}, {user: "bob", lookups: 2}, {event: "forget alice", lookups: 2}, {user: "bob", lookups: 2}}},
+		{"least recently used is evicted", 2, []step{{user: "alice", lookups: 1}, {user: "bob", lookups: 2}, {user: "alice", lookups: 2}, {user: "carol", lookups: 3}, {user: "alice", lookups: 3}, {user: "bob", lookups: 4}}},
+		{"unknown users are not cached", 10, []step{{user: "carol", lookups: 1}, {user: "carol", lookups: 2}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAuthFixture(t, true, tt.size)
+			f.store.users["carol"] = tt.name != "unknown users are not cached"
+
+			for i, s := range tt.steps {
+				switch s.event {
+				case "":
+					f.get(t, s.user)
+				case "advance 59s":
+					f.clock.Advance(59 * time.Second)
+				case "advance 1m":
+					f.clock.Advance(time.Minute)
+				case "forget alice":
+					f.users.Forget("alice")
+				}
+				if f.store.lookups != s.lookups {
+					t.Fatalf("step %d: %d lookups, want %d", i+1, f.store.lookups, s.lookups)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewarePublicRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth, err := services.NewAuthService(&config.Config{JWTSecret: strings.Repeat("s", 32), Clock: clock.NewFake(time.Now())})
+	if err != nil {
+		t.Fatalf("NewAuthService: %v", err)
+	}
+	router := gin.New()
+	router.Use(AuthMiddleware(auth, nil, "/version", "/shared/*", "/admin/*"))
+	for _, route := range []string{"/version", "/versions", "/shared/:token", "/shared/:token/feed.ics", "/admin/stats", "/administrators", "/tasks", "/tasks/:id"} {
+		router.GET(route, func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	}
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/version", http.StatusNoContent},
+		{"/shared/abc", http.StatusNoContent},
+		{"/shared/abc/feed.ics", http.StatusNoContent},
+		{"/admin/stats", http.StatusNoContent},
+		// Exact entries don't match longer paths, and prefixes stop at the "/"
+		{"/versions", http.StatusUnauthorized},
+		{"/administrators", http.StatusUnauthorized},
+		{"/tasks", http.StatusUnauthorized},
+		{"/tasks/abc", http.StatusUnauthorized},
+		// Unknown routes reach the router's 404 rather than a 401
+		{"/nowhere", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireFeature answers 404 for routes whose feature flag is switched off,
+// as if the route didn't exist
+func RequireFeature(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled {
+			c.JSON(http.StatusNotFound, gin.H{"error": "This feature is not enabled"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
\ No newline at end of file
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Streaming formats are written through untouched so downloads and feeds
+// keep flushing as they are produced
+var uncompressedTypes = []string{"text/calendar", "text/csv", "text/event-stream", "image/"}
+
+// Gzip compresses responses for clients sending Accept-Encoding: gzip.
+// Bodies smaller than minSize are sent as-is since compressing them costs
+// more than it saves.
+func Gzip(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !strings.Contains(c.GetHeader("Accept-Encoding"), "gzip") || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		w := &gzipWriter{ResponseWriter: c.Writer, minSize: minSize}
+		c.Writer = w
+		c.Header("Vary", "Accept-Encoding")
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				// Drop the half-written body and hand Recovery the real writer
+				// so its error response reaches the client
+				c.Writer = w.ResponseWriter
+				if w.gz != nil {
+					w.gz.Close()
+				}
+				panic(recovered)
+			}
+		}()
+
+		c.Next()
+
+		w.finish()
+	}
+}
+
+type gzipWriter struct {
+	gin.ResponseWriter
+	minSize     int
+	buf         bytes.Buffer
+	gz          *gzip.Writer
+	decided     bool
+	passthrough bool
+}
+
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		w.passthrough = !compressible(w.Header())
+	}
+
+	if w.passthrough {
+		return w.ResponseWriter.Write(data)
+	}
+	if w.gz != nil {
+		return w.gz.Write(data)
+	}
+
+	w.buf.Write(data)
+	if w.buf.Len() >= w.minSize {
+		if err := w.startGzip(); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *gzipWriter) Flush() {
+	if w.gz != nil {
+		w.gz.Flush()
+	} else if w.buf.Len() > 0 {
+		// A handler that flushes is streaming; stop buffering and send as-is
+		w.passthrough = true
+		w.ResponseWriter.Write(w.buf.Bytes())
+		w.buf.Reset()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *gzipWriter) startGzip() error {
+	header := w.Header()
+	header.Set("Content-Encoding", "gzip")
+	header.Del("Content-Length")
+
+	w.gz = gzip.NewWriter(w.ResponseWriter)
+	_, err := w.gz.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+// finish sends whatever is still buffered once the handler returns
+func (w *gzipWriter) finish() {
+	if w.gz != nil {
+		w.gz.Close()
+		return
+	}
+	if w.buf.Len() > 0 {
+		w.ResponseWriter.Write(w.buf.Bytes())
+	}
+}
+
+func compressible(header http.Header) bool {
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := header.Get("Content-Type")
+	for _, excluded := range uncompressedTypes {
+		if strings.HasPrefix(contentType, excluded) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newGzipRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Recovery(), Gzip(1024))
+	r.GET("/large", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"items": strings.Repeat("task ", 1000)})
+	})
+	r.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	r.GET("/calendar.ics", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(strings.Repeat("BEGIN:VEVENT\r\n", 200)))
+	})
+	r.GET("/panic", func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+		c.Writer.WriteString(`{"partial":`)
+		panic("boom")
+	})
+	return r
+}
+
+func TestGzip(t *testing.T) {
+	r := newGzipRouter()
+
+	tests := []struct {
+		name     string
+		path     string
+		encoding string
+		wantGzip bool
+	}{
+		{"large JSON", "/large", "gzip, deflate", true},
+		{"small JSON", "/small", "gzip", false},
+		{"client without gzip", "/large", "", false},
+		{"calendar feed", "/calendar.ics", "gzip", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.encoding != "" {
+				req.Header.Set("Accept-Encoding", tt.encoding)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+			gzipped := w.Header().Get("Content-Encoding") == "gzip"
+			if gzipped != tt.wantGzip {
+				t.Fatalf("Content-Encoding = %q, want gzip %v", w.Header().Get("Content-Encoding"), tt.wantGzip)
+			}
+
+			body := w.Body.Bytes()
+			if gzipped {
+				zr, err := gzip.NewReader(w.Body)
+				if err != nil {
+					t.Fatalf("body is not gzip: %v", err)
+				}
+				if body, err = io.ReadAll(zr); err != nil {
+					t.Fatalf("reading gzip body: %v", err)
+				}
+			}
+			if strings.HasSuffix(tt.path, ".ics") {
+				if !strings.HasPrefix(string(body), "BEGIN:VEVENT") {
+					t.Errorf("body = %.40q, want the calendar feed", body)
+				}
+				return
+			}
+			if !json.Valid(body) {
+				t.Errorf("body is not JSON: %.80s", body)
+			}
+		})
+	}
+}
+
+func TestGzipPanic(t *testing.T) {
+	r := newGzipRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if enc := w.Header().Get("Content-Encoding"); enc != "" {
+		t.Errorf("Content-Encoding = %q, want none", enc)
+	}
+	var body struct {
+		Code string `json:"code"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("body is not JSON: %v: %q", err, w.Body.String())
+	}
+	if body.Code != "internal_error" {
+		t.Errorf("code = %q, want internal_error", body.Code)
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestReadOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mode := NewReadOnlyMode(true, 2*time.Minute)
+	router := gin.New()
+	router.Use(ReadOnly(mode, "/admin/read-only"))
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	router.Handle(http.MethodGet, "/tasks", ok)
+	router.Handle(http.MethodHead, "/tasks", ok)
+	router.Handle(http.MethodOptions, "/tasks", ok)
+	router.Handle(http.MethodPost, "/tasks", ok)
+	router.Handle(http.MethodPut, "/tasks/:id", ok)
+	router.Handle(http.MethodPatch, "/tasks/:id", ok)
+	router.Handle(http.MethodDelete, "/tasks/:id", ok)
+	router.Handle(http.MethodPut, "/admin/read-only", ok)
+
+	tests := []struct {
+		method  string
+		path    string
+		enabled bool
+		want    int
+	}{
+		{http.MethodGet, "/tasks", true, http.StatusNoContent},
+		{http.MethodHead, "/tasks", true, http.StatusNoContent},
+		{http.MethodOptions, "/tasks", true, http.StatusNoContent},
+		{http.MethodPost, "/tasks", true, http.StatusServiceUnavailable},
+		{http.MethodPut, "/tasks/abc", true, http.StatusServiceUnavailable},
+		{http.MethodPatch, "/tasks/abc", true, http.StatusServiceUnavailable},
+		{http.MethodDelete, "/tasks/abc", true, http.StatusServiceUnavailable},
+		{http.MethodPut, "/admin/read-only", true, http.StatusNoContent},
+		{http.MethodPost, "/tasks", false, http.StatusNoContent},
+		{http.MethodDelete, "/tasks/abc", false, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		name := tt.method + " " + tt.path
+		if !tt.enabled {
+			name += " when writable"
+		}
+		t.Run(name, func(t *testing.T) {
+			mode.Set(tt.enabled)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			retryAfter := w.Header().Get("Retry-After")
+			if tt.want == http.StatusServiceUnavailable && retryAfter != "120" {
+				t.Errorf("Retry-After = %q, want %q", retryAfter, "120")
+			}
+			if tt.want != http.StatusServiceUnavailable && retryAfter != "" {
+				t.Errorf("Retry-After = %q on an allowed request", retryAfter)
+			}
+		})
+	}
+}
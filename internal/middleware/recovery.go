@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the ID that ties a failed response to its log line
+const RequestIDHeader = "X-Request-ID"
+
+// Recovery turns a handler panic into the usual JSON error body. The panic
+// value and stack trace are logged but never sent to the client; the response
+// carries the request ID instead, taken from X-Request-ID or generated, so the
+// log line can be found again. http.ErrAbortHandler is re-panicked so the
+// server can abort the response as intended.
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				// net/http uses this to drop the connection quietly; let it through
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+				requestID := c.GetHeader(RequestIDHeader)
+				if requestID == "" {
+					requestID = rand.Text()
+				}
+				log.Printf("💥 Panic on %s %s (request %s): %v\n%s", c.Request.Method, c.Request.URL.Path, requestID, recovered, debug.Stack())
+
+				// Headers may already be on the wire; only the connection can be dropped then
+				if c.Writer.Written() {
+					c.Abort()
+					return
+				}
+				c.Header(RequestIDHeader, requestID)
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+					"error":     "Internal server error",
+					"code":      "internal_error",
+					"requestId": requestID,
+				})
+			}
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Recovery(), ServerTiming(true, 0), Gzip(1024))
+	r.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	tests := []struct {
+		name      string
+		requestID string
+		encoding  string
+	}{
+		{"request ID from the client", "req-123", ""},
+		{"generated request ID", "", ""},
+		{"gzip client", "req-456", "gzip"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+			if tt.requestID != "" {
+				req.Header.Set(RequestIDHeader, tt.requestID)
+			}
+			if tt.encoding != "" {
+				req.Header.Set("Accept-Encoding", tt.encoding)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusInternalServerError {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+			}
+			var body struct {
+				Error     string `json:"error"`
+				Code      string `json:"code"`
+				RequestID string `json:"requestId"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("body is not JSON: %v: %s", err, w.Body.String())
+			}
+			if body.Code != "internal_error" || body.Error == "" {
+				t.Errorf("body = %s, want an internal_error", w.Body.String())
+			}
+			if body.RequestID == "" || (tt.requestID != "" && body.RequestID != tt.requestID) {
+				t.Errorf("requestId = %q, want %q", body.RequestID, tt.requestID)
+			}
+			if got := w.Header().Get(RequestIDHeader); got != body.RequestID {
+				t.Errorf("%s header = %q, want %q", RequestIDHeader, got, body.RequestID)
+			}
+		})
+	}
+}
+
+func TestRecoveryAbortHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Recovery())
+	r.GET("/abort", func(c *gin.Context) {
+		panic(http.ErrAbortHandler)
+	})
+
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler", recovered)
+		}
+	}()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/abort", nil))
+	t.Error("ErrAbortHandler was swallowed")
+}
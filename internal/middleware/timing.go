@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"focusflow-be/internal/services"
+)
+
+const timingsKey = "timings"
+
+// Timings collects the named phases of one request for the Server-Timing
+// header. Whatever isn't attributed to a phase is reported as "app".
+type Timings struct {
+	mu     sync.Mutex
+	start  time.Time
+	names  []string
+	phases map[string]time.Duration
+}
+
+// Add attributes d to the named phase, summing repeated phases
+func (t *Timings) Add(name string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.phases[name]; !ok {
+		t.names = append(t.names, name)
+	}
+	t.phases[name] += d
+}
+
+// header renders the phases measured so far plus app and total
+func (t *Timings) header() (string, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	total := time.Since(t.start)
+	app := total
+	metrics := make([]string, 0, len(t.names)+2)
+	for _, name := range t.names {
+		app -= t.phases[name]
+		metrics = append(metrics, timingMetric(name, t.phases[name]))
+	}
+	metrics = append(metrics, timingMetric("app", app), timingMetric("total", total))
+	return strings.Join(metrics, ", "), total
+}
+
+func timingMetric(name string, d time.Duration) string {
+	return fmt.Sprintf("%s;dur=%.1f", name, float64(d.Microseconds())/1000)
+}
+
+// RecordTiming adds d to a phase of the current request, if it's being timed
+func RecordTiming(c *gin.Context, name string, d time.Duration) {
+	if value, ok := c.Get(timingsKey); ok {
+		value.(*Timings).Add(name, d)
+	}
+}
+
+// ServerTiming times each request. With emit set the phases are sent in a
+// Server-Timing header for browser devtools; with a non-zero budget, requests
+// that take longer are logged along with their breakdown.
+func ServerTiming(emit bool, budget time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timings := &Timings{start: time.Now(), phases: make(map[string]time.Duration)}
+		c.Set(timingsKey, timings)
+
+		// Stores bound to the request report their Firestore calls here
+		c.Request = c.Request.WithContext(services.WithFirestoreTimer(c.Request.Context(), func(d time.Duration) {
+			timings.Add("firestore", d)
+		}))
+
+		if emit {
+			c.Writer = &timingWriter{ResponseWriter: c.Writer, timings: timings}
+		}
+
+		c.Next()
+
+		if budget > 0 {
+			if breakdown, total := timings.header(); total > budget {
+				log.Printf("🐢 %s %s took %s, over the %s budget (%s)", c.Request.Method, c.Request.URL.Path, total, budget, breakdown)
+			}
+		}
+	}
+}
+
+// timingWriter adds the Server-Timing header just before the response headers
+// go out, which is the last moment it can still be set
+type timingWriter struct {
+	gin.ResponseWriter
+	timings *Timings
+	sent    bool
+}
+
+func (w *timingWriter) setHeader() {
+	if w.sent || w.Written() {
+		return
+	}
+	w.sent = true
+	breakdown, _ := w.timings.header()
+	w.Header().Set("Server-Timing", breakdown)
+	w.Header().Set("Timing-Allow-Origin", "*")
+}
+
+// Gin sends bodiless responses (204, AbortWithStatus) through its own writer
+// rather than this one, but the status is always set through here first
+func (w *timingWriter) WriteHeader(code int) {
+	w.setHeader()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *timingWriter) WriteHeaderNow() {
+	w.setHeader()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *timingWriter) Write(data []byte) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timingWriter) WriteString(s string) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.WriteString(s)
+}
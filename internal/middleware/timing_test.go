@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestServerTiming(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ServerTiming(true, 0))
+	r.GET("/json", func(c *gin.Context) {
+		RecordTiming(c, "db", 10*time.Millisecond)
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	r.DELETE("/no-content", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	r.GET("/aborted", func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusForbidden)
+	})
+
+	tests := []struct {
+		method, path string
+		wantStatus   int
+		wantPhases   []string
+	}{
+		{http.MethodGet, "/json", http.StatusOK, []string{"db;dur=10.0", "app;dur=", "total;dur="}},
+		{http.MethodDelete, "/no-content", http.StatusNoContent, []string{"app;dur=", "total;dur="}},
+		{http.MethodGet, "/aborted", http.StatusForbidden, []string{"app;dur=", "total;dur="}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			header := w.Header().Get("Server-Timing")
+			if header == "" {
+				t.Fatal("no Server-Timing header")
+			}
+			for _, phase := range tt.wantPhases {
+				if !strings.Contains(header, phase) {
+					t.Errorf("Server-Timing %q is missing %q", header, phase)
+				}
+			}
+		})
+	}
+}
@@ -1,17 +1,90 @@
 package models
 
 import (
+	"fmt"
+	"regexp"
+	"sort"
 	"time"
+
+	"focusflow-be/internal/recurrence"
+)
+
+// Priorities lists the allowed task and reminder priorities, lowest first.
+// Request validation and overview counts all key off this list.
+var Priorities = []string{"low", "medium", "high", "urgent"}
+
+// IsValidPriority reports whether priority is one of Priorities
+func IsValidPriority(priority string) bool {
+	return PriorityRank(priority) >= 0
+}
+
+// IsHighPriority reports whether priority is "high" or above
+func IsHighPriority(priority string) bool {
+	return PriorityRank(priority) >= PriorityRank("high")
+}
+
+// PriorityRank is priority's position in Priorities, so higher ranks are more
+// urgent, or -1 when it isn't a known priority
+func PriorityRank(priority string) int {
+	for i, p := range Priorities {
+		if p == priority {
+			return i
+		}
+	}
+	return -1
+}
+
+// Limits on the free-form metadata a task can carry
+const (
+	MaxMetadataEntries  = 20
+	MaxMetadataValueLen = 200
 )
 
+var metadataKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,40}$`)
+
+// ValidateMetadata checks task metadata against the limits above. Keys are 1-40
+// letters, digits, '_' or '-', so they can be used as ?meta.<key>= filters.
+func ValidateMetadata(metadata map[string]string) error {
+	if len(metadata) > MaxMetadataEntries {
+		return fmt.Errorf("metadata has %d entries, at most %d are allowed", len(metadata), MaxMetadataEntries)
+	}
+
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !metadataKeyPattern.MatchString(key) {
+			return fmt.Errorf("invalid metadata key %q", key)
+		}
+		if len(metadata[key]) > MaxMetadataValueLen {
+			return fmt.Errorf("metadata value for %q is longer than %d bytes", key, MaxMetadataValueLen)
+		}
+	}
+	return nil
+}
+
 type UserSession struct {
 	UserID       string  `json:"userId" firestore:"userId"`
 	Email        string  `json:"email" firestore:"email"`
 	Name         string  `json:"name" firestore:"name"`
 	AccessToken  string  `json:"accessToken" firestore:"accessToken"`
 	RefreshToken *string `json:"refreshToken,omitempty" firestore:"refreshToken,omitempty"`
-	CreatedAt    time.Time `json:"createdAt" firestore:"createdAt"`
-	LastLogin    time.Time `json:"lastLogin" firestore:"lastLogin"`
+	Locale       string  `json:"locale,omitempty" firestore:"locale,omitempty"`
+	Timezone     string  `json:"timezone,omitempty" firestore:"timezone,omitempty"`     // IANA name; UTC when empty
+	CalendarID   string  `json:"calendarId,omitempty" firestore:"calendarId,omitempty"` // Google calendar to sync to; primary when empty
+	WebhookURL   string  `json:"webhookUrl,omitempty" firestore:"webhookUrl,omitempty"`
+
+	// Minutes before an item's time that new reminders default to
+	DefaultReminderLead *int      `json:"defaultReminderLead,omitempty" firestore:"defaultReminderLead,omitempty"`
+	CreatedAt           time.Time `json:"createdAt" firestore:"createdAt"`
+	LastLogin           time.Time `json:"lastLogin" firestore:"lastLogin"`
+
+	// Opt-in: move unfinished task/personal reminders to the next day
+	CarryForwardReminders bool `json:"carryForwardReminders" firestore:"carryForwardReminders,omitempty"`
+	// Opt-in: show meetings as ongoing or completed from their times
+	AutoMeetingStatus bool `json:"autoMeetingStatus" firestore:"autoMeetingStatus,omitempty"`
 }
 
 type Task struct {
@@ -20,43 +93,135 @@ type Task struct {
 	Title          string     `json:"title" firestore:"title"`
 	Description    *string    `json:"description,omitempty" firestore:"description,omitempty"`
 	Completed      bool       `json:"completed" firestore:"completed"`
-	Status         string     `json:"status" firestore:"status"` // todo, in-progress, completed
-	Priority       string     `json:"priority" firestore:"priority"` // low, medium, high
+	Status         string     `json:"status" firestore:"status"`                       // todo, in-progress, completed
+	Blocked        bool       `json:"blocked,omitempty" firestore:"blocked,omitempty"` // stuck; status is kept as it was
+	BlockReason    *string    `json:"blockReason,omitempty" firestore:"blockReason,omitempty"`
+	Priority       string     `json:"priority" firestore:"priority"` // one of Priorities
+	Tags           []string   `json:"tags,omitempty" firestore:"tags,omitempty"`
 	StartDate      *time.Time `json:"startDate,omitempty" firestore:"startDate,omitempty"`
 	DueDate        *time.Time `json:"dueDate,omitempty" firestore:"dueDate,omitempty"`
-	EstimatedHours *int       `json:"estimatedHours,omitempty" firestore:"estimatedHours,omitempty"`
-	ActualHours    *int       `json:"actualHours,omitempty" firestore:"actualHours,omitempty"`
+	EstimatedHours *float64   `json:"estimatedHours,omitempty" firestore:"estimatedHours,omitempty"` // fractions allowed, e.g. 1.5
+	ActualHours    *float64   `json:"actualHours,omitempty" firestore:"actualHours,omitempty"`
+	Order          *int       `json:"order,omitempty" firestore:"order,omitempty"` // manual position within a board column
 	GoogleEventID  *string    `json:"googleEventId,omitempty" firestore:"googleEventId,omitempty"`
+	StartedAt      *time.Time `json:"startedAt,omitempty" firestore:"startedAt,omitempty"`
+	CompletedAt    *time.Time `json:"completedAt,omitempty" firestore:"completedAt,omitempty"`
 	CreatedAt      time.Time  `json:"createdAt" firestore:"createdAt"`
 	UpdatedAt      time.Time  `json:"updatedAt" firestore:"updatedAt"`
+
+	// Free-form key/value pairs set by the user; see ValidateMetadata
+	Metadata map[string]string `json:"metadata,omitempty" firestore:"metadata,omitempty"`
+
+	// Status the task had before it was completed; toggling it back restores it
+	PreviousStatus *string `json:"previousStatus,omitempty" firestore:"previousStatus,omitempty"`
 }
 
 type Meeting struct {
-	ID            string     `json:"id,omitempty" firestore:"-"`
-	UserID        string     `json:"userId" firestore:"userId"`
-	Title         string     `json:"title" firestore:"title"`
-	Description   *string    `json:"description,omitempty" firestore:"description,omitempty"`
-	StartTime     time.Time  `json:"startTime" firestore:"startTime"`
-	EndTime       time.Time  `json:"endTime" firestore:"endTime"`
-	Attendees     []string   `json:"attendees,omitempty" firestore:"attendees,omitempty"`
-	Location      *string    `json:"location,omitempty" firestore:"location,omitempty"`
-	MeetingType   string     `json:"meetingType" firestore:"meetingType"` // call, in-person, video
-	Status        string     `json:"status" firestore:"status"` // scheduled, ongoing, completed, cancelled
-	GoogleEventID *string    `json:"googleEventId,omitempty" firestore:"googleEventId,omitempty"`
-	CreatedAt     time.Time  `json:"createdAt" firestore:"createdAt"`
+	ID            string       `json:"id,omitempty" firestore:"-"`
+	UserID        string       `json:"userId" firestore:"userId"`
+	Title         string       `json:"title" firestore:"title"`
+	Description   *string      `json:"description,omitempty" firestore:"description,omitempty"`
+	StartTime     time.Time    `json:"startTime" firestore:"startTime"`
+	EndTime       time.Time    `json:"endTime" firestore:"endTime"`
+	Attendees     []string     `json:"attendees,omitempty" firestore:"attendees,omitempty"`
+	Location      *string      `json:"location,omitempty" firestore:"location,omitempty"`
+	MeetingType   string       `json:"meetingType" firestore:"meetingType"` // call, in-person, video
+	Status        string       `json:"status" firestore:"status"`           // scheduled, ongoing, completed, cancelled
+	Attended      *bool        `json:"attended,omitempty" firestore:"attended,omitempty"`
+	BufferBefore  *int         `json:"bufferBefore,omitempty" firestore:"bufferBefore,omitempty"` // minutes blocked before, e.g. for travel
+	BufferAfter   *int         `json:"bufferAfter,omitempty" firestore:"bufferAfter,omitempty"`   // minutes blocked after
+	Notes         *string      `json:"notes,omitempty" firestore:"notes,omitempty"`
+	ActionItems   []ActionItem `json:"actionItems,omitempty" firestore:"actionItems,omitempty"`
+	GoogleEventID *string      `json:"googleEventId,omitempty" firestore:"googleEventId,omitempty"`
+	CompletedAt   *time.Time   `json:"completedAt,omitempty" firestore:"completedAt,omitempty"`
+	CreatedAt     time.Time    `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt     time.Time    `json:"updatedAt" firestore:"updatedAt"`
+
+	// Calendar notifications; empty means the calendar's defaults
+	ReminderMinutes []EventReminder `json:"reminderMinutes,omitempty" firestore:"reminderMinutes,omitempty"`
+	// iCalendar UID the meeting was imported from, suffixed with the
+	// occurrence start for instances of a recurring event
+	ExternalID *string `json:"externalId,omitempty" firestore:"externalId,omitempty"`
+	// Times the meeting has been moved with PATCH /meetings/:id/reschedule
+	RescheduleCount int `json:"rescheduleCount,omitempty" firestore:"rescheduleCount,omitempty"`
+	// Whether Google emails attendees when the calendar event changes; unset means yes
+	NotifyAttendees *bool `json:"notifyAttendees,omitempty" firestore:"notifyAttendees,omitempty"`
+	// Not yet confirmed. Whether it counts in conflict checks and availability
+	// is set by CONFLICTS_INCLUDE_TENTATIVE.
+	Tentative bool `json:"tentative,omitempty" firestore:"tentative,omitempty"`
+
+	// Effective blocked window including buffers, only set when the meeting has any
+	BlockedStart *time.Time `json:"blockedStart,omitempty" firestore:"-"`
+	BlockedEnd   *time.Time `json:"blockedEnd,omitempty" firestore:"-"`
+
+	// Full attendee total, set when a list response may have cut Attendees short
+	AttendeeCount int `json:"attendeeCount,omitempty" firestore:"-"`
+}
+
+// EventReminder is a calendar notification sent Minutes before an event starts
+type EventReminder struct {
+	Method  string `json:"method" firestore:"method" binding:"required,oneof=popup email"`
+	Minutes int    `json:"minutes" firestore:"minutes" binding:"min=0,max=40320"` // Google allows up to four weeks
+}
+
+// ActionItem is a follow-up captured in a meeting's notes
+type ActionItem struct {
+	ID       string  `json:"id" firestore:"id"`
+	Text     string  `json:"text" firestore:"text"`
+	Done     bool    `json:"done" firestore:"done"`
+	Assignee *string `json:"assignee,omitempty" firestore:"assignee,omitempty"`
+	TaskID   *string `json:"taskId,omitempty" firestore:"taskId,omitempty"` // set once turned into a task
+}
+
+// BlockedWindow is the meeting's time widened by its buffers
+func (m *Meeting) BlockedWindow() (time.Time, time.Time) {
+	start, end := m.StartTime, m.EndTime
+	if m.BufferBefore != nil {
+		start = start.Add(-time.Duration(*m.BufferBefore) * time.Minute)
+	}
+	if m.BufferAfter != nil {
+		end = end.Add(time.Duration(*m.BufferAfter) * time.Minute)
+	}
+	return start, end
+}
+
+// TimedStatus is the status the meeting's times imply at now: ongoing from
+// its start and completed from its end. Cancelled and completed meetings
+// keep their status.
+func (m *Meeting) TimedStatus(now time.Time) string {
+	switch {
+	case m.Status == "cancelled" || m.Status == "completed":
+		return m.Status
+	case !now.Before(m.EndTime):
+		return "completed"
+	case !now.Before(m.StartTime):
+		return "ongoing"
+	}
+	return m.Status
 }
 
 type Reminder struct {
-	ID           string    `json:"id,omitempty" firestore:"-"`
-	UserID       string    `json:"userId" firestore:"userId"`
-	Title        string    `json:"title" firestore:"title"`
-	Description  *string   `json:"description,omitempty" firestore:"description,omitempty"`
-	ReminderTime time.Time `json:"reminderTime" firestore:"reminderTime"`
-	ReminderType string    `json:"reminderType" firestore:"reminderType"` // task, meeting, personal
-	IsCompleted  bool      `json:"isCompleted" firestore:"isCompleted"`
-	Priority     string    `json:"priority" firestore:"priority"` // low, medium, high
-	GoogleEventID *string  `json:"googleEventId,omitempty" firestore:"googleEventId,omitempty"`
-	CreatedAt    time.Time `json:"createdAt" firestore:"createdAt"`
+	ID              string     `json:"id,omitempty" firestore:"-"`
+	UserID          string     `json:"userId" firestore:"userId"`
+	Title           string     `json:"title" firestore:"title"`
+	Description     *string    `json:"description,omitempty" firestore:"description,omitempty"`
+	ReminderTime    time.Time  `json:"reminderTime" firestore:"reminderTime"`
+	ReminderType    string     `json:"reminderType" firestore:"reminderType"` // task, meeting, personal
+	IsCompleted     bool       `json:"isCompleted" firestore:"isCompleted"`
+	Priority        string     `json:"priority" firestore:"priority"` // one of Priorities
+	RolledOverCount int        `json:"rolledOverCount,omitempty" firestore:"rolledOverCount,omitempty"`
+	GoogleEventID   *string    `json:"googleEventId,omitempty" firestore:"googleEventId,omitempty"`
+	CompletedAt     *time.Time `json:"completedAt,omitempty" firestore:"completedAt,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt" firestore:"updatedAt"`
+
+	// Repeating reminders are one document per occurrence. Dismissing an
+	// occurrence creates the next one; completing it ends the series.
+	Recurrence *recurrence.Rule `json:"recurrence,omitempty" firestore:"recurrence,omitempty"`
+
+	// The task a "task" reminder belongs to. Deleting the task deletes the
+	// reminder; completing the task completes it.
+	TaskID *string `json:"taskId,omitempty" firestore:"taskId,omitempty"`
 }
 
 type CalendarEvent struct {
@@ -68,6 +233,12 @@ type CalendarEvent struct {
 	Status      string  `json:"status"`
 	Color       *string `json:"color,omitempty"`
 	Description *string `json:"description,omitempty"`
+	Blocked     bool    `json:"blocked,omitempty"`
+	Tentative   bool    `json:"tentative,omitempty"` // meetings only
+
+	// Localized, human-friendly times; only set when ?humanize=true
+	DisplayStart *string `json:"displayStart,omitempty"`
+	DisplayEnd   *string `json:"displayEnd,omitempty"`
 }
 
 type GanttItem struct {
@@ -80,12 +251,207 @@ type GanttItem struct {
 	Status       string   `json:"status"`
 	Dependencies []string `json:"dependencies,omitempty"`
 	Priority     string   `json:"priority"`
+	Blocked      bool     `json:"blocked,omitempty"`
+}
+
+// Tombstone records a deleted item so syncing clients can drop their copy
+type Tombstone struct {
+	UserID    string    `json:"-" firestore:"userId"`
+	Type      string    `json:"type" firestore:"type"` // task, meeting, reminder
+	ItemID    string    `json:"id" firestore:"itemId"`
+	DeletedAt time.Time `json:"deletedAt" firestore:"deletedAt"`
+}
+
+// SyncChanges is what changed for a user since a point in time
+type SyncChanges struct {
+	Tasks     []*Task      `json:"tasks"`
+	Meetings  []*Meeting   `json:"meetings"`
+	Reminders []*Reminder  `json:"reminders"`
+	Deleted   []*Tombstone `json:"deleted"`
+}
+
+// DayView is everything scheduled on one calendar day in the user's time zone
+type DayView struct {
+	Date      string      `json:"date"` // YYYY-MM-DD
+	Timezone  string      `json:"timezone"`
+	Tasks     []*Task     `json:"tasks"`
+	Meetings  []*Meeting  `json:"meetings"`
+	Reminders []*Reminder `json:"reminders"`
+	Summary   DaySummary  `json:"summary"`
+}
+
+type DaySummary struct {
+	Tasks          int `json:"tasks"`
+	Meetings       int `json:"meetings"`
+	Reminders      int `json:"reminders"`
+	MeetingMinutes int `json:"meetingMinutes"` // cancelled meetings excluded
+	FreeMinutes    int `json:"freeMinutes"`    // working hours not taken by meetings
+}
+
+// TimeRange is the half-open interval [Start, End)
+type TimeRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// AvailabilitySlot is one fixed-length slot of the working day. It is busy
+// when any meeting or timed task overlaps it.
+type AvailabilitySlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Busy  bool      `json:"busy"`
+}
+
+// Availability is a day's working hours split into free and busy time
+type Availability struct {
+	Date        string             `json:"date"` // YYYY-MM-DD
+	Timezone    string             `json:"timezone"`
+	WorkStart   time.Time          `json:"workStart"`
+	WorkEnd     time.Time          `json:"workEnd"`
+	SlotMinutes int                `json:"slotMinutes"`
+	Slots       []AvailabilitySlot `json:"slots"`
+	Busy        []TimeRange        `json:"busy"` // overlapping items merged
+	Free        []TimeRange        `json:"free"` // gaps between busy ranges
+	BusyMinutes int                `json:"busyMinutes"`
+	FreeMinutes int                `json:"freeMinutes"`
+	LongestFree *TimeRange         `json:"longestFree,omitempty"`
+}
+
+// ConflictItem is one side of a scheduling conflict. For meetings Start and
+// End include any buffers.
+type ConflictItem struct {
+	ID    string    `json:"id"`
+	Type  string    `json:"type"` // meeting or task
+	Title string    `json:"title"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	Tentative bool `json:"tentative,omitempty"`
+}
+
+// Conflict is two items booked over the same time. First starts no later
+// than Second.
+type Conflict struct {
+	First          ConflictItem `json:"first"`
+	Second         ConflictItem `json:"second"`
+	OverlapStart   time.Time    `json:"overlapStart"`
+	OverlapEnd     time.Time    `json:"overlapEnd"`
+	OverlapMinutes int          `json:"overlapMinutes"`
+}
+
+// ScheduleItem is a meeting or task on the weekly schedule. Timed items have
+// Start and End; anytime items have only the date they fall on.
+type ScheduleItem struct {
+	ID       string     `json:"id"`
+	Type     string     `json:"type"` // meeting or task
+	Title    string     `json:"title"`
+	Status   string     `json:"status"`
+	Priority string     `json:"priority,omitempty"`
+	Start    *time.Time `json:"start,omitempty"`
+	End      *time.Time `json:"end,omitempty"`
+	Due      *time.Time `json:"due,omitempty"`
+
+	Tentative bool `json:"tentative,omitempty"`
+}
+
+// ScheduleSlot is one row of a day's grid, listing the timed items that
+// overlap it
+type ScheduleSlot struct {
+	Start time.Time      `json:"start"`
+	End   time.Time      `json:"end"`
+	Items []ScheduleItem `json:"items"`
+}
+
+// ScheduleDay is one column of the weekly schedule
+type ScheduleDay struct {
+	Date         string         `json:"date"` // YYYY-MM-DD
+	Weekday      string         `json:"weekday"`
+	Slots        []ScheduleSlot `json:"slots"`
+	Anytime      []ScheduleItem `json:"anytime"`      // tasks with a date but no time range
+	OutsideHours []ScheduleItem `json:"outsideHours"` // timed items that miss the working hours
+}
+
+// WeekSchedule is a Monday to Sunday grid of the working hours, for a
+// printable weekly planner
+type WeekSchedule struct {
+	WeekStart   string        `json:"weekStart"` // the Monday, YYYY-MM-DD
+	Timezone    string        `json:"timezone"`
+	WorkStart   string        `json:"workStart"` // HH:MM
+	WorkEnd     string        `json:"workEnd"`
+	SlotMinutes int           `json:"slotMinutes"`
+	Days        []ScheduleDay `json:"days"`
+}
+
+// WorkloadDay is the work scheduled on one day: estimates of open tasks due
+// that day plus time in meetings
+type WorkloadDay struct {
+	Date         string  `json:"date"` // YYYY-MM-DD
+	TaskHours    float64 `json:"taskHours"`
+	MeetingHours float64 `json:"meetingHours"`
+	TotalHours   float64 `json:"totalHours"`
+	OverCapacity bool    `json:"overCapacity"`
+}
+
+// AgendaDay is the open tasks due on one day, most urgent first
+type AgendaDay struct {
+	Date  string  `json:"date"` // YYYY-MM-DD
+	Tasks []*Task `json:"tasks"`
+}
+
+// EstimateAccuracy compares estimated with actual hours over completed tasks
+// that have both. Ratios are actual / estimated, so above 1 means the work
+// took longer than estimated.
+type EstimateAccuracy struct {
+	Tasks          int              `json:"tasks"`
+	EstimatedHours float64          `json:"estimatedHours"`
+	ActualHours    float64          `json:"actualHours"`
+	AverageRatio   float64          `json:"averageRatio"`   // mean of the per-task ratios
+	OverallRatio   float64          `json:"overallRatio"`   // total actual / total estimated
+	Underestimated int              `json:"underestimated"` // took longer than estimated
+	OnTarget       int              `json:"onTarget"`
+	Overestimated  int              `json:"overestimated"` // finished sooner than estimated
+	Distribution   []EstimateBucket `json:"distribution"`
+}
+
+// EstimateBucket counts the tasks whose ratio falls in [Min, Max). The last
+// bucket has no upper bound.
+type EstimateBucket struct {
+	Label string   `json:"label"`
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max,omitempty"`
+	Count int      `json:"count"`
 }
 
 type Overview struct {
 	Tasks     TaskOverview     `json:"tasks"`
 	Meetings  MeetingOverview  `json:"meetings"`
 	Reminders ReminderOverview `json:"reminders"`
+
+	// Set when a collection couldn't be loaded; its counts are then zero
+	Partial bool              `json:"partial,omitempty"`
+	Errors  []CollectionError `json:"errors,omitempty"`
+}
+
+// OverviewSnapshot is an overview frozen for sharing by link. UserID records
+// who shared it but is never shown to the people the link is sent to.
+type OverviewSnapshot struct {
+	Token     string    `json:"-"`
+	UserID    string    `json:"-"`
+	Overview  Overview  `json:"overview"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// ShareOverviewRequest optionally shortens how long a shared overview stays
+// readable ("12h", "3d"); it can't exceed OVERVIEW_SHARE_TTL
+type ShareOverviewRequest struct {
+	ExpiresIn *string `json:"expiresIn"`
+}
+
+// CollectionError names a collection a combined view failed to load
+type CollectionError struct {
+	Collection string `json:"collection"` // tasks, meetings, reminders
+	Error      string `json:"error"`
 }
 
 type TaskOverview struct {
@@ -95,13 +461,55 @@ type TaskOverview struct {
 	Todo         int `json:"todo"`
 	HighPriority int `json:"highPriority"`
 	Overdue      int `json:"overdue"`
+	Blocked      int `json:"blocked"`
 }
 
 type MeetingOverview struct {
-	Total     int `json:"total"`
-	Today     int `json:"today"`
-	Upcoming  int `json:"upcoming"`
-	Completed int `json:"completed"`
+	Total          int     `json:"total"`
+	Today          int     `json:"today"`
+	Upcoming       int     `json:"upcoming"`
+	Completed      int     `json:"completed"`
+	Attended       int     `json:"attended"`
+	Missed         int     `json:"missed"`
+	AttendanceRate float64 `json:"attendanceRate"` // attended / (attended + missed)
+}
+
+// ActivityEntry is one item of the activity feed
+type ActivityEntry struct {
+	Type      string    `json:"type"`   // task, meeting, reminder
+	Action    string    `json:"action"` // created, started, completed
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// OverdueItem is one entry of the "attention needed" list
+type OverdueItem struct {
+	Type           string    `json:"type"` // task, meeting, reminder
+	ID             string    `json:"id"`
+	Title          string    `json:"title"`
+	Priority       string    `json:"priority,omitempty"`
+	DueAt          time.Time `json:"dueAt"` // due date, reminder time or meeting start
+	OverdueMinutes int       `json:"overdueMinutes"`
+}
+
+// BadgeCounts are the small numbers shown on navigation badges
+// UserPreferences are the user's stored settings with defaults filled in
+type UserPreferences struct {
+	Timezone              string `json:"timezone"`
+	Locale                string `json:"locale"`
+	CalendarID            string `json:"calendarId"`
+	DefaultReminderLead   int    `json:"defaultReminderLead"` // minutes
+	WebhookURL            string `json:"webhookUrl,omitempty"`
+	CarryForwardReminders bool   `json:"carryForwardReminders"`
+	AutoMeetingStatus     bool   `json:"autoMeetingStatus"`
+}
+
+type BadgeCounts struct {
+	OpenTasks        int `json:"openTasks"`
+	MeetingsToday    int `json:"meetingsToday"`
+	PendingReminders int `json:"pendingReminders"`
+	Overdue          int `json:"overdue"` // open tasks and pending reminders past due
 }
 
 type ReminderOverview struct {
@@ -111,41 +519,181 @@ type ReminderOverview struct {
 	Overdue   int `json:"overdue"`
 }
 
+// UserProfile is the public part of a user record, without OAuth tokens
+type UserProfile struct {
+	UserID    string    `json:"userId"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Locale    string    `json:"locale,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	LastLogin time.Time `json:"lastLogin"`
+}
+
+// DataBundle is the portable export/import format for a user's data
+type DataBundle struct {
+	Version    int          `json:"version"`
+	ExportedAt time.Time    `json:"exportedAt"`
+	Profile    *UserProfile `json:"profile,omitempty"`
+	Tasks      []*Task      `json:"tasks"`
+	Meetings   []*Meeting   `json:"meetings"`
+	Reminders  []*Reminder  `json:"reminders"`
+}
+
+// ImportError describes a bundle record that was skipped during import
+type ImportError struct {
+	Type  string `json:"type"` // task, meeting, reminder, event
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+type ImportResult struct {
+	Tasks     int            `json:"tasks"`
+	Meetings  int            `json:"meetings"`
+	Reminders int            `json:"reminders"`
+	Errors    []*ImportError `json:"errors"`
+}
+
+// ICSImportResult counts the meetings an .ics upload created or updated.
+// Errors index into the file's VEVENTs.
+type ICSImportResult struct {
+	Created int            `json:"created"`
+	Updated int            `json:"updated"`
+	Errors  []*ImportError `json:"errors"`
+}
+
+type UsageStats struct {
+	Users         int `json:"users"`
+	Tasks         int `json:"tasks"`
+	Meetings      int `json:"meetings"`
+	Reminders     int `json:"reminders"`
+	ActiveUsers7d int `json:"activeUsers7d"`
+}
+
 type GoogleUserInfo struct {
-	ID    string `json:"id"`
-	Email string `json:"email"`
-	Name  string `json:"name"`
+	ID     string `json:"id"`
+	Email  string `json:"email"`
+	Name   string `json:"name"`
+	Locale string `json:"locale"`
 }
 
 // Request/Response DTOs
 type CreateTaskRequest struct {
 	Title          string     `json:"title" binding:"required"`
 	Description    *string    `json:"description"`
-	Priority       string     `json:"priority" binding:"required,oneof=low medium high"`
+	Priority       string     `json:"priority" binding:"required,priority"`
+	Tags           []string   `json:"tags" binding:"omitempty,max=20,dive,min=1,max=50"`
 	StartDate      *time.Time `json:"startDate"`
 	DueDate        *time.Time `json:"dueDate"`
-	EstimatedHours *int       `json:"estimatedHours"`
+	EstimatedHours *float64   `json:"estimatedHours" binding:"omitempty,min=0"`
+	Order          *int       `json:"order"`
+
+	Metadata map[string]string `json:"metadata"`
 }
 
 type UpdateTaskRequest struct {
 	Title          *string    `json:"title"`
 	Description    *string    `json:"description"`
-	Priority       *string    `json:"priority" binding:"omitempty,oneof=low medium high"`
+	Priority       *string    `json:"priority" binding:"omitempty,priority"`
 	Status         *string    `json:"status" binding:"omitempty,oneof=todo in-progress completed"`
 	StartDate      *time.Time `json:"startDate"`
 	DueDate        *time.Time `json:"dueDate"`
-	EstimatedHours *int       `json:"estimatedHours"`
-	ActualHours    *int       `json:"actualHours"`
+	EstimatedHours *float64   `json:"estimatedHours" binding:"omitempty,min=0"`
+	ActualHours    *float64   `json:"actualHours" binding:"omitempty,min=0"`
+	Order          *int       `json:"order"`
+	ClearFields    []string   `json:"clearFields" binding:"omitempty,dive,oneof=description startDate dueDate estimatedHours actualHours order metadata"`
+
+	// Replaces all of the task's metadata; clear it with clearFields
+	Metadata map[string]string `json:"metadata"`
+}
+
+// QuickAddTaskRequest is a task written as one line, e.g.
+// "Submit report tomorrow 5pm #work !high"
+type QuickAddTaskRequest struct {
+	Text string `json:"text" binding:"required,max=500"`
+}
+
+// QuickAddInterpretation reports how quick-add text was read, so the client
+// can show it for confirmation
+type QuickAddInterpretation struct {
+	Title    string     `json:"title"`
+	DueDate  *time.Time `json:"dueDate,omitempty"`
+	DateText string     `json:"dateText,omitempty"` // the words taken as the due date
+	Tags     []string   `json:"tags,omitempty"`
+	Priority string     `json:"priority,omitempty"` // empty when the text named none
+	Timezone string     `json:"timezone"`
+}
+
+type BlockTaskRequest struct {
+	Reason *string `json:"reason"`
+}
+
+type RescheduleOverdueRequest struct {
+	To        *string `json:"to"`        // YYYY-MM-DD
+	ShiftDays *int    `json:"shiftDays"` // days to push each due date forward
+}
+
+// RescheduleRemindersRequest moves overdue reminders either by Shift ("24h",
+// "2d") or all to the absolute time To
+type RescheduleRemindersRequest struct {
+	Shift *string    `json:"shift"`
+	To    *time.Time `json:"to"`
 }
 
 type CreateMeetingRequest struct {
-	Title       string     `json:"title" binding:"required"`
-	Description *string    `json:"description"`
-	StartTime   time.Time  `json:"startTime" binding:"required"`
-	EndTime     time.Time  `json:"endTime" binding:"required"`
-	Attendees   []string   `json:"attendees"`
-	Location    *string    `json:"location"`
-	MeetingType string     `json:"meetingType" binding:"required,oneof=call in-person video"`
+	Title        string     `json:"title" binding:"required"`
+	Description  *string    `json:"description"`
+	StartTime    time.Time  `json:"startTime" binding:"required"`
+	EndTime      *time.Time `json:"endTime"` // defaults to startTime + the configured duration
+	Attendees    []string   `json:"attendees"`
+	Location     *string    `json:"location"`
+	MeetingType  string     `json:"meetingType" binding:"required,oneof=call in-person video"`
+	BufferBefore *int       `json:"bufferBefore" binding:"omitempty,min=0,max=240"` // minutes
+	BufferAfter  *int       `json:"bufferAfter" binding:"omitempty,min=0,max=240"`  // minutes
+	// Google Calendar accepts at most five overrides per event
+	ReminderMinutes []EventReminder `json:"reminderMinutes" binding:"omitempty,max=5,dive"`
+	NotifyAttendees *bool           `json:"notifyAttendees"` // defaults to true
+	Tentative       bool            `json:"tentative"`
+}
+
+type UpdateMeetingRequest struct {
+	Title           *string    `json:"title"`
+	Description     *string    `json:"description"`
+	StartTime       *time.Time `json:"startTime"`
+	EndTime         *time.Time `json:"endTime"`
+	Attendees       []string   `json:"attendees"` // replaces the whole list
+	AddAttendees    []string   `json:"addAttendees" binding:"omitempty,dive,email"`
+	RemoveAttendees []string   `json:"removeAttendees" binding:"omitempty,dive,email"`
+	Location        *string    `json:"location"`
+	MeetingType     *string    `json:"meetingType" binding:"omitempty,oneof=call in-person video"`
+	ClearFields     []string   `json:"clearFields" binding:"omitempty,dive,oneof=description attendees location"`
+	NotifyAttendees *bool      `json:"notifyAttendees"`
+	Tentative       *bool      `json:"tentative"`
+}
+
+type UpdateMeetingNotesRequest struct {
+	Notes       *string           `json:"notes"`
+	ActionItems []ActionItemInput `json:"actionItems" binding:"omitempty,dive"` // replaces the list when present
+}
+
+type ActionItemInput struct {
+	ID       string  `json:"id"` // keep an existing item's ID; empty for new items
+	Text     string  `json:"text" binding:"required"`
+	Done     bool    `json:"done"`
+	Assignee *string `json:"assignee" binding:"omitempty,email"`
+}
+
+type ActionItemToTaskRequest struct {
+	Priority *string    `json:"priority" binding:"omitempty,priority"`
+	DueDate  *time.Time `json:"dueDate"`
+}
+
+type DuplicateMeetingRequest struct {
+	StartTime time.Time `json:"startTime" binding:"required"`
+	EndTime   time.Time `json:"endTime" binding:"required"`
+}
+
+type UpdateMeetingAttendanceRequest struct {
+	Attended *bool `json:"attended" binding:"required"`
 }
 
 type CreateReminderRequest struct {
@@ -153,9 +701,63 @@ type CreateReminderRequest struct {
 	Description  *string   `json:"description"`
 	ReminderTime time.Time `json:"reminderTime" binding:"required"`
 	ReminderType string    `json:"reminderType" binding:"required,oneof=task meeting personal"`
-	Priority     string    `json:"priority" binding:"required,oneof=low medium high"`
+	Priority     string    `json:"priority" binding:"required,priority"`
+
+	Recurrence *recurrence.Rule `json:"recurrence"`
+	TaskID     *string          `json:"taskId"`
+}
+
+type UpdateReminderRequest struct {
+	Title        *string    `json:"title"`
+	Description  *string    `json:"description"`
+	ReminderTime *time.Time `json:"reminderTime"`
+	ReminderType *string    `json:"reminderType" binding:"omitempty,oneof=task meeting personal"`
+	Priority     *string    `json:"priority" binding:"omitempty,priority"`
+	ClearFields  []string   `json:"clearFields" binding:"omitempty,dive,oneof=description"`
+}
+
+type BulkReminderRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1,max=500,dive,required"`
+}
+
+// SyncFailure is one item a calendar sync could not push
+type SyncFailure struct {
+	ID    string `json:"id"`
+	Error string `json:"error"`
+}
+
+// BulkResult reports the outcome for one ID of a bulk operation
+type BulkResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"` // completed, already-completed, not-found
+	Error  string `json:"error,omitempty"`
+}
+
+// UpdatePreferencesRequest changes only the fields that are sent; an empty
+// string resets that preference to its default
+type UpdatePreferencesRequest struct {
+	Timezone              *string `json:"timezone"`
+	Locale                *string `json:"locale"`
+	CalendarID            *string `json:"calendarId"`
+	DefaultReminderLead   *int    `json:"defaultReminderLead" binding:"omitempty,min=0,max=10080"` // up to a week
+	WebhookURL            *string `json:"webhookUrl"`
+	CarryForwardReminders *bool   `json:"carryForwardReminders"`
+	AutoMeetingStatus     *bool   `json:"autoMeetingStatus"`
+}
+
+type ReadOnlyRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// RescheduleMeetingRequest moves a meeting. EndTime defaults to keeping the
+// meeting's current length.
+type RescheduleMeetingRequest struct {
+	StartTime time.Time  `json:"startTime" binding:"required"`
+	EndTime   *time.Time `json:"endTime"`
+	// Overrides the meeting's notifyAttendees for this move only
+	NotifyAttendees *bool `json:"notifyAttendees"`
 }
 
 type UpdateMeetingStatusRequest struct {
 	Status string `json:"status" binding:"required,oneof=scheduled ongoing completed cancelled"`
-}
\ No newline at end of file
+}
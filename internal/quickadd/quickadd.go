@@ -0,0 +1,382 @@
+// Package quickadd turns a line of free text such as
+// "Submit report tomorrow 5pm #work !high" into the parts of a task: a title,
+// a due time, tags and a priority. Only a fixed set of English date phrases is
+// understood; words that aren't recognised stay in the title.
+package quickadd
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"focusflow-be/internal/models"
+)
+
+// Result is what Parse read out of the text
+type Result struct {
+	Title    string
+	Due      *time.Time
+	DateText string   // the words read as the due date, as they were written
+	Tags     []string // lowercased and without the '#', in order of appearance
+	Priority string   // empty when the text names none
+}
+
+// A date without a time is due at the end of that day
+const (
+	endOfDayHour   = 23
+	endOfDayMinute = 59
+)
+
+// Words that introduce a date ("due by friday") and are dropped along with it
+var connectors = map[string]bool{"at": true, "on": true, "by": true, "due": true, "before": true}
+
+// "sun" and "sat" are left out as they're ordinary words too ("buy sun cream")
+var weekdays = map[string]time.Weekday{
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"saturday": time.Saturday, "sunday": time.Sunday,
+}
+
+var months = map[string]time.Month{
+	"jan": time.January, "january": time.January,
+	"feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March,
+	"apr": time.April, "april": time.April,
+	"may": time.May,
+	"jun": time.June, "june": time.June,
+	"jul": time.July, "july": time.July,
+	"aug": time.August, "august": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November,
+	"dec": time.December, "december": time.December,
+}
+
+var (
+	tagPattern      = regexp.MustCompile(`^#([\pL\pN_-]+)$`)
+	isoDatePattern  = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})$`)
+	dayPattern      = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?$`)
+	yearPattern     = regexp.MustCompile(`^\d{4}$`)
+	meridiemPattern = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)$`)
+	clockPattern    = regexp.MustCompile(`^(\d{1,2}):(\d{2})$`)
+	hourPattern     = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?$`)
+)
+
+type token struct {
+	raw  string
+	word string // lowercased, trailing punctuation removed
+	used bool
+}
+
+type clockTime struct {
+	hour, minute int
+}
+
+type parser struct {
+	tokens []token
+	now    time.Time
+	today  time.Time // midnight of now's day in now's location
+
+	day     *time.Time
+	clock   *clockTime
+	matched []int // indexes of the tokens that made up the date
+}
+
+// Parse reads text, resolving relative phrases such as "tomorrow" or "friday"
+// against now in now's location. The first date phrase and the first time of
+// day win; later ones are left in the title.
+func Parse(text string, now time.Time) Result {
+	p := &parser{
+		now:   now,
+		today: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()),
+	}
+	for _, raw := range strings.Fields(text) {
+		p.tokens = append(p.tokens, token{raw: raw, word: strings.TrimRight(strings.ToLower(raw), ",.;")})
+	}
+
+	var result Result
+	seenTags := make(map[string]bool)
+	for i := range p.tokens {
+		t := &p.tokens[i]
+		if t.used {
+			continue
+		}
+
+		if match := tagPattern.FindStringSubmatch(t.word); match != nil {
+			t.used = true
+			if tag := match[1]; !seenTags[tag] {
+				seenTags[tag] = true
+				result.Tags = append(result.Tags, tag)
+			}
+			continue
+		}
+		if priority, ok := strings.CutPrefix(t.word, "!"); ok && result.Priority == "" && models.IsValidPriority(priority) {
+			t.used = true
+			result.Priority = priority
+			continue
+		}
+
+		if p.day == nil {
+			if n := p.matchDate(i); n > 0 {
+				p.consume(i, n)
+				continue
+			}
+		}
+		if p.clock == nil {
+			if n := p.matchTime(i); n > 0 {
+				p.consume(i, n)
+			}
+		}
+	}
+
+	var title []string
+	for _, t := range p.tokens {
+		if !t.used {
+			title = append(title, t.raw)
+		}
+	}
+	result.Title = strings.Join(title, " ")
+	result.Due = p.due()
+
+	sort.Ints(p.matched)
+	var dateText []string
+	for _, i := range p.matched {
+		dateText = append(dateText, strings.TrimRight(p.tokens[i].raw, ",.;"))
+	}
+	result.DateText = strings.Join(dateText, " ")
+	return result
+}
+
+// consume marks n tokens from i as part of the date, along with any
+// connecting words just before them
+func (p *parser) consume(i, n int) {
+	for j := i; j < i+n; j++ {
+		p.tokens[j].used = true
+		p.matched = append(p.matched, j)
+	}
+	for j := i - 1; j >= 0 && !p.tokens[j].used && connectors[p.tokens[j].word]; j-- {
+		p.tokens[j].used = true
+	}
+}
+
+func (p *parser) word(i int) string {
+	if i >= len(p.tokens) || p.tokens[i].used {
+		return ""
+	}
+	return p.tokens[i].word
+}
+
+// matchDate reads a date phrase starting at token i and returns how many
+// tokens it used, or 0 when there isn't one
+func (p *parser) matchDate(i int) int {
+	w := p.word(i)
+	switch w {
+	case "today":
+		p.setDay(p.today)
+		return 1
+	case "tonight":
+		p.setDay(p.today)
+		if p.clock == nil {
+			p.clock = &clockTime{hour: 20}
+		}
+		return 1
+	case "tomorrow", "tmrw", "tmr":
+		p.setDay(p.today.AddDate(0, 0, 1))
+		return 1
+	case "next", "this":
+		next := p.word(i + 1)
+		if next == "week" && w == "next" {
+			p.setDay(p.nextWeekday(time.Monday, false))
+			return 2
+		}
+		if weekday, ok := weekdays[next]; ok {
+			p.setDay(p.nextWeekday(weekday, w == "this"))
+			return 2
+		}
+		return 0
+	case "in":
+		return p.matchOffset(i)
+	}
+
+	if weekday, ok := weekdays[w]; ok {
+		p.setDay(p.nextWeekday(weekday, false))
+		return 1
+	}
+
+	if match := isoDatePattern.FindStringSubmatch(w); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		month, _ := strconv.Atoi(match[2])
+		day, _ := strconv.Atoi(match[3])
+		if date, ok := p.date(year, time.Month(month), day); ok {
+			p.setDay(date)
+			return 1
+		}
+		return 0
+	}
+
+	// "oct 20", "october 20th 2027", "20 oct"
+	month, day, n := time.Month(0), 0, 0
+	if m, ok := months[w]; ok {
+		if match := dayPattern.FindStringSubmatch(p.word(i + 1)); match != nil {
+			month, n = m, 2
+			day, _ = strconv.Atoi(match[1])
+		}
+	} else if match := dayPattern.FindStringSubmatch(w); match != nil {
+		if m, ok := months[p.word(i+1)]; ok {
+			month, n = m, 2
+			day, _ = strconv.Atoi(match[1])
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+
+	year := p.today.Year()
+	explicitYear := yearPattern.MatchString(p.word(i + n))
+	if explicitYear {
+		year, _ = strconv.Atoi(p.word(i + n))
+	}
+	date, ok := p.date(year, month, day)
+	if !ok {
+		return 0
+	}
+	if explicitYear {
+		n++
+	} else if date.Before(p.today) {
+		// A date already gone this year means the next one
+		if date, ok = p.date(year+1, month, day); !ok {
+			return 0
+		}
+	}
+	p.setDay(date)
+	return n
+}
+
+// matchOffset reads "in 3 days", "in a week", "in 2 hours" starting at the "in"
+func (p *parser) matchOffset(i int) int {
+	amount := p.word(i + 1)
+	n, err := strconv.Atoi(amount)
+	if amount == "a" || amount == "an" {
+		n, err = 1, nil
+	}
+	if err != nil || n < 1 || n > 1000 {
+		return 0
+	}
+
+	switch strings.TrimSuffix(p.word(i+2), "s") {
+	case "day":
+		p.setDay(p.today.AddDate(0, 0, n))
+	case "week":
+		p.setDay(p.today.AddDate(0, 0, 7*n))
+	case "month":
+		p.setDay(p.today.AddDate(0, n, 0))
+	case "hour", "hr", "minute", "min":
+		if p.clock != nil {
+			return 0
+		}
+		unit := time.Minute
+		if w := strings.TrimSuffix(p.word(i+2), "s"); w == "hour" || w == "hr" {
+			unit = time.Hour
+		}
+		at := p.now.Add(time.Duration(n) * unit)
+		p.setDay(time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location()))
+		p.clock = &clockTime{hour: at.Hour(), minute: at.Minute()}
+	default:
+		return 0
+	}
+	return 3
+}
+
+// matchTime reads a time of day such as "5pm", "5:30 pm", "17:00" or "noon".
+// A bare number isn't taken as a time, so "read 5 chapters" keeps its 5.
+func (p *parser) matchTime(i int) int {
+	w := p.word(i)
+	if w == "noon" || w == "midday" {
+		p.clock = &clockTime{hour: 12}
+		return 1
+	}
+
+	n := 0
+	var hour, minute string
+	var pm, am bool
+	if match := meridiemPattern.FindStringSubmatch(w); match != nil {
+		hour, minute, n = match[1], match[2], 1
+		pm, am = match[3] == "pm", match[3] == "am"
+	} else if match := hourPattern.FindStringSubmatch(w); match != nil && (p.word(i+1) == "am" || p.word(i+1) == "pm") {
+		hour, minute, n = match[1], match[2], 2
+		pm, am = p.word(i+1) == "pm", p.word(i+1) == "am"
+	} else if match := clockPattern.FindStringSubmatch(w); match != nil {
+		hour, minute, n = match[1], match[2], 1
+	}
+	if n == 0 {
+		return 0
+	}
+
+	h, _ := strconv.Atoi(hour)
+	m := 0
+	if minute != "" {
+		m, _ = strconv.Atoi(minute)
+	}
+	if (am || pm) && (h < 1 || h > 12) || h > 23 || m > 59 {
+		return 0
+	}
+	if pm && h != 12 {
+		h += 12
+	} else if am && h == 12 {
+		h = 0
+	}
+	p.clock = &clockTime{hour: h, minute: m}
+	return n
+}
+
+func (p *parser) setDay(day time.Time) {
+	p.day = &day
+}
+
+// nextWeekday returns the next weekday after today, or today itself when
+// includeToday is set and today is that day
+func (p *parser) nextWeekday(weekday time.Weekday, includeToday bool) time.Time {
+	days := (int(weekday) - int(p.today.Weekday()) + 7) % 7
+	if days == 0 && !includeToday {
+		days = 7
+	}
+	return p.today.AddDate(0, 0, days)
+}
+
+// date builds a date in now's location, rejecting ones like Feb 30 that
+// time.Date would roll into the next month
+func (p *parser) date(year int, month time.Month, day int) (time.Time, bool) {
+	date := time.Date(year, month, day, 0, 0, 0, 0, p.today.Location())
+	if date.Month() != month || date.Day() != day {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// due combines the date and time read. A time on its own means its next
+// occurrence: today if it's still ahead, otherwise tomorrow.
+func (p *parser) due() *time.Time {
+	if p.day == nil && p.clock == nil {
+		return nil
+	}
+
+	day := p.today
+	if p.day != nil {
+		day = *p.day
+	}
+	clock := clockTime{hour: endOfDayHour, minute: endOfDayMinute}
+	if p.clock != nil {
+		clock = *p.clock
+	}
+
+	due := time.Date(day.Year(), day.Month(), day.Day(), clock.hour, clock.minute, 0, 0, day.Location())
+	if p.day == nil && !due.After(p.now) {
+		due = time.Date(day.Year(), day.Month(), day.Day()+1, clock.hour, clock.minute, 0, 0, day.Location())
+	}
+	return &due
+}
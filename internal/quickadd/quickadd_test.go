@@ -0,0 +1,67 @@
+package quickadd
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A Friday morning
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, newYork)
+	at := func(year int, month time.Month, day, hour, minute int) *time.Time {
+		due := time.Date(year, month, day, hour, minute, 0, 0, newYork)
+		return &due
+	}
+
+	tests := []struct {
+		text         string
+		wantTitle    string
+		wantDue      *time.Time
+		wantDateText string
+		wantTags     []string
+		wantPriority string
+	}{
+		{"Submit report tomorrow 5pm #work !high", "Submit report", at(2026, 10, 17, 17, 0), "tomorrow 5pm", []string{"work"}, "high"},
+		{"Call mom", "Call mom", nil, "", nil, ""},
+		{"Pay rent due by friday", "Pay rent", at(2026, 10, 23, 23, 59), "friday", nil, ""},
+		{"Standup this friday 9:30 am", "Standup", at(2026, 10, 16, 9, 30), "this friday 9:30 am", nil, ""},
+		{"Dentist oct 20th at 3pm", "Dentist", at(2026, 10, 20, 15, 0), "oct 20th 3pm", nil, ""},
+		{"Renew passport jan 5", "Renew passport", at(2027, 1, 5, 23, 59), "jan 5", nil, ""},
+		{"Review PR in 2 hours", "Review PR", at(2026, 10, 16, 11, 0), "in 2 hours", nil, ""},
+		{"read 5 chapters 8am", "read 5 chapters", at(2026, 10, 17, 8, 0), "8am", nil, ""},
+		{"Ship it in a week, 17:00", "Ship it", at(2026, 10, 23, 17, 0), "in a week 17:00", nil, ""},
+		{"Party tonight", "Party", at(2026, 10, 16, 20, 0), "tonight", nil, ""},
+		{"Launch next week #Release", "Launch", at(2026, 10, 19, 23, 59), "next week", []string{"release"}, ""},
+		{"Buy sun cream #errands #Errands !urgent !low", "Buy sun cream !low", nil, "", []string{"errands"}, "urgent"},
+		{"Plan feb 30 offsite !someday", "Plan feb 30 offsite !someday", nil, "", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := Parse(tt.text, now)
+
+			if got.Title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", got.Title, tt.wantTitle)
+			}
+			switch {
+			case (got.Due == nil) != (tt.wantDue == nil):
+				t.Errorf("due = %v, want %v", got.Due, tt.wantDue)
+			case got.Due != nil && (!got.Due.Equal(*tt.wantDue) || got.Due.Location() != newYork):
+				t.Errorf("due = %v, want %v in New York", got.Due, tt.wantDue)
+			}
+			if got.DateText != tt.wantDateText {
+				t.Errorf("date text = %q, want %q", got.DateText, tt.wantDateText)
+			}
+			if !slices.Equal(got.Tags, tt.wantTags) {
+				t.Errorf("tags = %v, want %v", got.Tags, tt.wantTags)
+			}
+			if got.Priority != tt.wantPriority {
+				t.Errorf("priority = %q, want %q", got.Priority, tt.wantPriority)
+			}
+		})
+	}
+}
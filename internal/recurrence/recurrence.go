@@ -0,0 +1,183 @@
+// Package recurrence expands repeating schedules into concrete occurrence
+// times. Expansion is done on the wall clock of the owner's time zone, so a
+// 9:00 weekly meeting stays at 9:00 local time across daylight saving changes.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "daily"
+	Weekly  Frequency = "weekly"
+	Monthly Frequency = "monthly"
+	Yearly  Frequency = "yearly"
+)
+
+// Rule describes how often something repeats and when the series ends.
+// A rule with neither Count nor Until only ends at the caller's limit.
+type Rule struct {
+	Frequency Frequency  `json:"frequency"`
+	Interval  int        `json:"interval,omitempty"` // defaults to 1
+	Count     int        `json:"count,omitempty"`    // total occurrences including the first
+	Until     *time.Time `json:"until,omitempty"`    // inclusive
+}
+
+var (
+	ErrInvalidRule        = errors.New("invalid recurrence rule")
+	ErrTooManyOccurrences = errors.New("recurrence rule exceeds the expansion limit")
+)
+
+// Limits bounds how far a series may be expanded. A zero field means no limit
+// of that kind.
+type Limits struct {
+	MaxOccurrences int
+	Horizon        time.Duration // measured from the series start
+}
+
+func (r Rule) Validate() error {
+	switch r.Frequency {
+	case Daily, Weekly, Monthly, Yearly:
+	default:
+		return fmt.Errorf("%w: unknown frequency %q", ErrInvalidRule, r.Frequency)
+	}
+	if r.Interval < 0 {
+		return fmt.Errorf("%w: interval must be positive", ErrInvalidRule)
+	}
+	if r.Count < 0 {
+		return fmt.Errorf("%w: count must be positive", ErrInvalidRule)
+	}
+	return nil
+}
+
+// LoadLocation resolves an IANA zone name such as "Europe/London".
+// An empty name means UTC.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(name)
+}
+
+// Occurrences returns the times of the series starting at start, at most limit
+// of them. Each occurrence is built with time.Date in loc rather than by adding
+// a fixed duration, which keeps the local hour stable across DST transitions.
+// Dates that do not exist in a given month or year (Jan 31 + 1 month, Feb 29 in
+// a non-leap year) are skipped rather than rolled over.
+func Occurrences(start time.Time, rule Rule, loc *time.Location, limit int) ([]time.Time, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	interval := rule.Interval
+	if interval == 0 {
+		interval = 1
+	}
+
+	local := start.In(loc)
+	year, month, day := local.Date()
+	hour, min, sec := local.Clock()
+	nsec := local.Nanosecond()
+
+	var occurrences []time.Time
+	for step := 0; len(occurrences) < limit; step++ {
+		if rule.Count > 0 && len(occurrences) >= rule.Count {
+			break
+		}
+
+		n := step * interval
+		var next time.Time
+		switch rule.Frequency {
+		case Daily:
+			next = time.Date(year, month, day+n, hour, min, sec, nsec, loc)
+		case Weekly:
+			next = time.Date(year, month, day+7*n, hour, min, sec, nsec, loc)
+		case Monthly:
+			next = time.Date(year, month+time.Month(n), day, hour, min, sec, nsec, loc)
+		case Yearly:
+			next = time.Date(year+n, month, day, hour, min, sec, nsec, loc)
+		}
+
+		if rule.Until != nil && next.After(*rule.Until) {
+			break
+		}
+		if next.Day() != day && (rule.Frequency == Monthly || rule.Frequency == Yearly) {
+			continue
+		}
+		occurrences = append(occurrences, next)
+	}
+
+	return occurrences, nil
+}
+
+// Expand materializes a series within limits. A rule that asks for more than
+// the limits allow, through Count or Until, is rejected with
+// ErrTooManyOccurrences. A rule with neither is open-ended; it is cut off at
+// the horizon or the occurrence cap and reported as truncated.
+func Expand(start time.Time, rule Rule, loc *time.Location, limits Limits) (occurrences []time.Time, truncated bool, err error) {
+	if err := rule.Validate(); err != nil {
+		return nil, false, err
+	}
+
+	bounded := rule.Count > 0 || rule.Until != nil
+	if limits.MaxOccurrences > 0 && rule.Count > limits.MaxOccurrences {
+		return nil, false, fmt.Errorf("%w: count %d is over the maximum of %d", ErrTooManyOccurrences, rule.Count, limits.MaxOccurrences)
+	}
+	if limits.Horizon > 0 {
+		horizon := start.Add(limits.Horizon)
+		if rule.Until != nil && rule.Count == 0 && rule.Until.After(horizon) {
+			return nil, false, fmt.Errorf("%w: until is more than %s after the start", ErrTooManyOccurrences, limits.Horizon)
+		}
+		if !bounded {
+			rule.Until = &horizon
+		}
+	}
+
+	limit := limits.MaxOccurrences
+	if limit <= 0 {
+		if !bounded && rule.Until == nil {
+			return nil, false, fmt.Errorf("%w: rule has no end", ErrTooManyOccurrences)
+		}
+		limit = math.MaxInt
+	} else {
+		// One extra occurrence tells a capped series apart from one that
+		// happens to end exactly at the limit
+		limit++
+	}
+
+	occurrences, err = Occurrences(start, rule, loc, limit)
+	if err != nil {
+		return nil, false, err
+	}
+	if limits.MaxOccurrences > 0 && len(occurrences) > limits.MaxOccurrences {
+		if bounded {
+			return nil, false, fmt.Errorf("%w: rule produces more than %d occurrences", ErrTooManyOccurrences, limits.MaxOccurrences)
+		}
+		occurrences = occurrences[:limits.MaxOccurrences]
+	}
+
+	return occurrences, !bounded, nil
+}
+
+// Next returns the occurrence that follows current, treating current as the
+// first occurrence of the series, along with the rule that continues the
+// series from there. ok is false when current was the last occurrence.
+func Next(current time.Time, rule Rule, loc *time.Location) (next time.Time, rest Rule, ok bool, err error) {
+	occurrences, err := Occurrences(current, rule, loc, 2)
+	if err != nil || len(occurrences) < 2 {
+		return time.Time{}, rule, false, err
+	}
+
+	rest = rule
+	if rest.Count > 0 {
+		rest.Count--
+	}
+	return occurrences[1], rest, true, nil
+}
@@ -0,0 +1,157 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExpandWallClock(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		start time.Time
+		rule  Rule
+		loc   *time.Location
+		want  []string // local times in loc
+	}{
+		{
+			name:  "weekly across the spring change",
+			start: time.Date(2026, 3, 22, 9, 0, 0, 0, london),
+			rule:  Rule{Frequency: Weekly, Count: 3},
+			loc:   london,
+			want:  []string{"2026-03-22 09:00 GMT", "2026-03-29 09:00 BST", "2026-04-05 09:00 BST"},
+		},
+		{
+			name:  "daily across the autumn change",
+			start: time.Date(2026, 10, 31, 9, 0, 0, 0, newYork),
+			rule:  Rule{Frequency: Daily, Count: 3},
+			loc:   newYork,
+			want:  []string{"2026-10-31 09:00 EDT", "2026-11-01 09:00 EST", "2026-11-02 09:00 EST"},
+		},
+		{
+			name:  "start given in UTC keeps the local hour",
+			start: time.Date(2026, 3, 22, 9, 0, 0, 0, time.UTC),
+			rule:  Rule{Frequency: Weekly, Count: 2},
+			loc:   london,
+			want:  []string{"2026-03-22 09:00 GMT", "2026-03-29 09:00 BST"},
+		},
+		{
+			name:  "every other day",
+			start: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+			rule:  Rule{Frequency: Daily, Interval: 2, Count: 3},
+			loc:   nil,
+			want:  []string{"2026-10-16 09:00 UTC", "2026-10-18 09:00 UTC", "2026-10-20 09:00 UTC"},
+		},
+		{
+			name:  "monthly skips short months",
+			start: time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC),
+			rule:  Rule{Frequency: Monthly, Count: 3},
+			loc:   time.UTC,
+			want:  []string{"2026-01-31 09:00 UTC", "2026-03-31 09:00 UTC", "2026-05-31 09:00 UTC"},
+		},
+		{
+			name:  "yearly on a leap day",
+			start: time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+			rule:  Rule{Frequency: Yearly, Count: 2},
+			loc:   time.UTC,
+			want:  []string{"2024-02-29 09:00 UTC", "2028-02-29 09:00 UTC"},
+		},
+		{
+			name:  "until is inclusive",
+			start: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+			rule:  Rule{Frequency: Daily, Until: timePtr(time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC))},
+			loc:   time.UTC,
+			want:  []string{"2026-10-16 09:00 UTC", "2026-10-17 09:00 UTC", "2026-10-18 09:00 UTC"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			occurrences, truncated, err := Expand(tt.start, tt.rule, tt.loc, Limits{})
+			if err != nil {
+				t.Fatalf("Expand: %v", err)
+			}
+			if truncated {
+				t.Error("a bounded series was reported as truncated")
+			}
+			loc := tt.loc
+			if loc == nil {
+				loc = time.UTC
+			}
+			if len(occurrences) != len(tt.want) {
+				t.Fatalf("got %d occurrences, want %d: %v", len(occurrences), len(tt.want), occurrences)
+			}
+			for i, occurrence := range occurrences {
+				if got := occurrence.In(loc).Format("2006-01-02 15:04 MST"); got != tt.want[i] {
+					t.Errorf("occurrence %d = %s, want %s", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExpandInvalidRule(t *testing.T) {
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	for _, rule := range []Rule{
+		{Frequency: "hourly", Count: 2},
+		{Frequency: Daily, Interval: -1, Count: 2},
+		{Frequency: Daily, Count: -1},
+	} {
+		if _, _, err := Expand(start, rule, time.UTC, Limits{}); !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("Expand(%+v) err = %v, want ErrInvalidRule", rule, err)
+		}
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestExpandLimits(t *testing.T) {
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	limits := Limits{MaxOccurrences: 5, Horizon: 30 * 24 * time.Hour}
+
+	tests := []struct {
+		name          string
+		rule          Rule
+		limits        Limits
+		wantCount     int
+		wantTruncated bool
+		wantErr       error
+	}{
+		{"count within the cap", Rule{Frequency: Daily, Count: 5}, limits, 5, false, nil},
+		{"count over the cap", Rule{Frequency: Daily, Count: 6}, limits, 0, false, ErrTooManyOccurrences},
+		{"until within both limits", Rule{Frequency: Weekly, Until: timePtr(start.AddDate(0, 0, 21))}, limits, 4, false, nil},
+		{"until past the horizon", Rule{Frequency: Monthly, Until: timePtr(start.AddDate(0, 2, 0))}, limits, 0, false, ErrTooManyOccurrences},
+		{"until yields too many", Rule{Frequency: Daily, Until: timePtr(start.AddDate(0, 0, 10))}, limits, 0, false, ErrTooManyOccurrences},
+		{"open-ended cut at the cap", Rule{Frequency: Daily}, limits, 5, true, nil},
+		{"open-ended cut at the horizon", Rule{Frequency: Weekly}, Limits{MaxOccurrences: 10, Horizon: 30 * 24 * time.Hour}, 5, true, nil},
+		{"open-ended monthly cut at the horizon", Rule{Frequency: Monthly}, limits, 1, true, nil},
+		{"open-ended with only a cap", Rule{Frequency: Yearly}, Limits{MaxOccurrences: 3}, 3, true, nil},
+		{"open-ended with only a horizon", Rule{Frequency: Daily}, Limits{Horizon: 72 * time.Hour}, 4, true, nil},
+		{"open-ended without limits", Rule{Frequency: Daily}, Limits{}, 0, false, ErrTooManyOccurrences},
+		{"count ignores the horizon", Rule{Frequency: Monthly, Count: 3}, limits, 3, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			occurrences, truncated, err := Expand(start, tt.rule, time.UTC, tt.limits)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if len(occurrences) != tt.wantCount {
+				t.Errorf("got %d occurrences, want %d", len(occurrences), tt.wantCount)
+			}
+			if truncated != tt.wantTruncated {
+				t.Errorf("truncated = %v, want %v", truncated, tt.wantTruncated)
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package services
 
 import (
+	"crypto/rsa"
 	"errors"
+	"fmt"
+	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -11,13 +14,61 @@ import (
 )
 
 type AuthService struct {
-	config *config.Config
+	config    *config.Config
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
 }
 
-func NewAuthService(cfg *config.Config) *AuthService {
-	return &AuthService{
+func NewAuthService(cfg *config.Config) (*AuthService, error) {
+	s := &AuthService{
 		config: cfg,
 	}
+
+	switch cfg.JWTAlgorithm {
+	case "", "HS256":
+		s.method = jwt.SigningMethodHS256
+		s.signKey = []byte(cfg.JWTSecret)
+		s.verifyKey = []byte(cfg.JWTSecret)
+	case "RS256":
+		privateKey, err := loadRSAPrivateKey(cfg.JWTPrivateKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		publicKey, err := loadRSAPublicKey(cfg.JWTPublicKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		s.method = jwt.SigningMethodRS256
+		s.signKey = privateKey
+		s.verifyKey = publicKey
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q, expected HS256 or RS256", cfg.JWTAlgorithm)
+	}
+
+	return s, nil
+}
+
+func loadRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
+	if path == "" {
+		return nil, errors.New("JWT_PRIVATE_KEY_PATH is required for RS256")
+	}
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT private key: %w", err)
+	}
+	return jwt.ParseRSAPrivateKeyFromPEM(pem)
+}
+
+func loadRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	if path == "" {
+		return nil, errors.New("JWT_PUBLIC_KEY_PATH is required for RS256")
+	}
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT public key: %w", err)
+	}
+	return jwt.ParseRSAPublicKeyFromPEM(pem)
 }
 
 type Claims struct {
@@ -28,31 +79,51 @@ type Claims struct {
 }
 
 func (s *AuthService) CreateJWT(userSession *models.UserSession) (string, error) {
+	now := s.config.Clock.Now()
 	claims := &Claims{
 		UserID: userSession.UserID,
 		Email:  userSession.Email,
 		Name:   userSession.Name,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(24 * time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.config.JWTSecret))
+	token := jwt.NewWithClaims(s.method, claims)
+	return token.SignedString(s.signKey)
 }
 
+// ErrTokenExpired is returned by ParseJWT for a well-formed token whose
+// expiry has passed. The claims are still returned alongside it.
+var ErrTokenExpired = jwt.ErrTokenExpired
+
 func (s *AuthService) VerifyJWT(tokenString string) (*models.UserSession, error) {
+	claims, err := s.ParseJWT(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.UserSession{
+		UserID: claims.UserID,
+		Email:  claims.Email,
+		Name:   claims.Name,
+	}, nil
+}
+
+// ParseJWT verifies a token and returns its claims, including the expiry.
+func (s *AuthService) ParseJWT(tokenString string) (*Claims, error) {
 	claims := &Claims{}
-	
+
+	// Only accept the configured algorithm so an attacker can't pick a weaker one
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return []byte(s.config.JWTSecret), nil
-	})
+		return s.verifyKey, nil
+	}, jwt.WithValidMethods([]string{s.method.Alg()}), jwt.WithTimeFunc(s.config.Clock.Now), jwt.WithLeeway(s.config.JWTLeeway))
 
 	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return claims, err
+		}
 		return nil, err
 	}
 
@@ -60,9 +131,5 @@ func (s *AuthService) VerifyJWT(tokenString string) (*models.UserSession, error)
 		return nil, errors.New("invalid token")
 	}
 
-	return &models.UserSession{
-		UserID: claims.UserID,
-		Email:  claims.Email,
-		Name:   claims.Name,
-	}, nil
-}
\ No newline at end of file
+	return claims, nil
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"focusflow-be/internal/clock"
+	"focusflow-be/internal/config"
+	"focusflow-be/internal/models"
+)
+
+var testNow = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+// newHS256Auth signs with a shared secret
+func newHS256Auth(t *testing.T, leeway time.Duration) *AuthService {
+	t.Helper()
+	auth, err := NewAuthService(&config.Config{
+		JWTAlgorithm: "HS256",
+		JWTSecret:    strings.Repeat("s", 32),
+		JWTLeeway:    leeway,
+		Clock:        clock.NewFake(testNow),
+	})
+	if err != nil {
+		t.Fatalf("NewAuthService: %v", err)
+	}
+	return auth
+}
+
+// newRS256Auth signs with a freshly generated RSA key pair written to PEM files
+func newRS256Auth(t *testing.T) *AuthService {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	privatePath := filepath.Join(dir, "jwt.pem")
+	publicPath := filepath.Join(dir, "jwt.pub")
+	writePEM(t, privatePath, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+	writePEM(t, publicPath, "PUBLIC KEY", publicDER)
+
+	auth, err := NewAuthService(&config.Config{
+		JWTAlgorithm:      "RS256",
+		JWTPrivateKeyPath: privatePath,
+		JWTPublicKeyPath:  publicPath,
+		Clock:             clock.NewFake(testNow),
+	})
+	if err != nil {
+		t.Fatalf("NewAuthService: %v", err)
+	}
+	return auth
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJWTRoundTrip(t *testing.T) {
+	user := &models.UserSession{UserID: "user-1", Email: "jane@example.com", Name: "Jane"}
+
+	tests := []struct {
+		name string
+		auth *AuthService
+	}{
+		{"HS256", newHS256Auth(t, 0)},
+		{"RS256", newRS256Auth(t)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := tt.auth.CreateJWT(user)
+			if err != nil {
+				t.Fatalf("CreateJWT: %v", err)
+			}
+			if header := decodeSegment(t, token, 0); !strings.Contains(header, `"alg":"`+tt.name+`"`) {
+				t.Errorf("header = %s, want alg %s", header, tt.name)
+			}
+
+			got, err := tt.auth.VerifyJWT(token)
+			if err != nil {
+				t.Fatalf("VerifyJWT: %v", err)
+			}
+			if *got != *user {
+				t.Errorf("user = %+v, want %+v", got, user)
+			}
+
+			// A changed payload no longer matches the signature
+			parts := strings.Split(token, ".")
+			payload := strings.Replace(decodeSegment(t, token, 1), `"sub":"user-1"`, `"sub":"user-2"`, 1)
+			parts[1] = base64.RawURLEncoding.EncodeToString([]byte(payload))
+			if _, err := tt.auth.VerifyJWT(strings.Join(parts, ".")); err == nil {
+				t.Error("tampered token was accepted")
+			}
+		})
+	}
+}
+
+func TestJWTAlgorithmMismatch(t *testing.T) {
+	hs256 := newHS256Auth(t, 0)
+	rs256 := newRS256Auth(t)
+	user := &models.UserSession{UserID: "user-1"}
+
+	tests := []struct {
+		name     string
+		signer   *AuthService
+		verifier *AuthService
+	}{
+		{"HS256 token with RS256 configured", hs256, rs256},
+		{"RS256 token with HS256 configured", rs256, hs256},
+		// The classic confusion attack: HMAC-signing with the public key
+		{"HS256 token keyed with the RS256 public key", publicKeyHMAC(t, rs256), rs256},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := tt.signer.CreateJWT(user)
+			if err != nil {
+				t.Fatalf("CreateJWT: %v", err)
+			}
+			if _, err := tt.verifier.VerifyJWT(token); err == nil {
+				t.Error("token signed with the other algorithm was accepted")
+			}
+		})
+	}
+}
+
+func decodeSegment(t *testing.T, token string, index int) string {
+	t.Helper()
+	data, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[index])
+	if err != nil {
+		t.Fatalf("decoding token segment %d: %v", index, err)
+	}
+	return string(data)
+}
+
+// publicKeyHMAC signs HS256 tokens using the PEM of auth's public key as the
+// shared secret, as an attacker who only knows the public key would
+func publicKeyHMAC(t *testing.T, auth *AuthService) *AuthService {
+	t.Helper()
+	publicPEM, err := os.ReadFile(auth.config.JWTPublicKeyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &AuthService{config: auth.config, method: jwt.SigningMethodHS256, signKey: publicPEM, verifyKey: publicPEM}
+}
+
+func TestJWTLeeway(t *testing.T) {
+	expiry := testNow.Add(24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		leeway  time.Duration
+		now     time.Time
+		wantErr bool
+	}{
+		{"before expiry", 30 * time.Second, expiry.Add(-time.Second), false},
+		{"just past expiry, within leeway", 30 * time.Second, expiry.Add(29 * time.Second), false},
+		{"past the leeway", 30 * time.Second, expiry.Add(31 * time.Second), true},
+		{"no leeway, just past expiry", 0, expiry.Add(time.Second), true},
+		{"no leeway, before expiry", 0, expiry.Add(-time.Second), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := newHS256Auth(t, tt.leeway)
+			token, err := auth.CreateJWT(&models.UserSession{UserID: "user-1"})
+			if err != nil {
+				t.Fatalf("CreateJWT: %v", err)
+			}
+
+			auth.config.Clock.(*clock.Fake).Set(tt.now)
+			_, err = auth.ParseJWT(token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrTokenExpired) {
+				t.Errorf("err = %v, want ErrTokenExpired", err)
+			}
+		})
+	}
+}
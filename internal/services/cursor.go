@@ -0,0 +1,51 @@
+package services
+
+import (
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// PageCursor marks the last item of a page: the value of the field the query
+// is ordered by and the document ID that breaks ties. Clients only ever see it
+// signed and encoded, so they can't forge a position or learn document names.
+type PageCursor struct {
+	Value time.Time `json:"v"`
+	ID    string    `json:"i"`
+}
+
+// EncodeCursor signs the cursor with key and returns an opaque token
+func EncodeCursor(cursor PageCursor, key []byte) (string, error) {
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + signState(encoded, key), nil
+}
+
+// DecodeCursor verifies a token from EncodeCursor, returning ErrInvalidCursor
+// if it was modified or signed with another key
+func DecodeCursor(token string, key []byte) (*PageCursor, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(signState(encoded, key))) {
+		return nil, ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor PageCursor
+	if err := json.Unmarshal(payload, &cursor); err != nil || cursor.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
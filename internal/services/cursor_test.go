@@ -0,0 +1,61 @@
+package services
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	key := []byte("test-secret")
+	want := PageCursor{Value: time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC), ID: "task-1"}
+
+	token, err := EncodeCursor(want, key)
+	if err != nil {
+		t.Fatalf("EncodeCursor: %v", err)
+	}
+	if strings.Contains(token, want.ID) {
+		t.Errorf("token %q shows the document ID", token)
+	}
+
+	got, err := DecodeCursor(token, key)
+	if err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+	if !got.Value.Equal(want.Value) || got.ID != want.ID {
+		t.Errorf("decoded %+v, want %+v", got, want)
+	}
+}
+
+func TestCursorRejectsModified(t *testing.T) {
+	key := []byte("test-secret")
+	token, err := EncodeCursor(PageCursor{Value: time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC), ID: "task-1"}, key)
+	if err != nil {
+		t.Fatalf("EncodeCursor: %v", err)
+	}
+	encoded, signature, _ := strings.Cut(token, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"v":"2026-10-16T09:30:00Z","i":"task-2"}`))
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"v":"2026-10-16T09:30:00Z","i":""}`))
+	otherKey, _ := EncodeCursor(PageCursor{ID: "task-1"}, []byte("other-secret"))
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"payload swapped", forged + "." + signature},
+		{"signature altered", encoded + "." + strings.ToUpper(signature)},
+		{"signature dropped", encoded},
+		{"signed with another key", otherKey},
+		{"not base64", "!!!." + signState("!!!", key)},
+		{"missing ID", unsigned + "." + signState(unsigned, key)},
+		{"empty", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.token, key); err != ErrInvalidCursor {
+				t.Errorf("err = %v, want %v", err, ErrInvalidCursor)
+			}
+		})
+	}
+}
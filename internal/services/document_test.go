@@ -0,0 +1,456 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"focusflow-be/internal/clock"
+	"focusflow-be/internal/models"
+)
+
+// fakeDocuments stands in for Firestore's document endpoints, keeping the
+// fields of each document in memory: POST creates (under ?documentId= when
+// given), GET reads and PATCH applies the update mask, dropping masked fields
+// the body leaves out. :commit applies updates and deletes, and :runQuery
+// supports the EQUAL and GREATER_THAN_OR_EQUAL filters on strings and
+// timestamps.
+type fakeDocuments struct {
+	mu   sync.Mutex
+	docs map[string]map[string]interface{} // fields by collection/id
+}
+
+func newFakeDocuments(t *testing.T) (*FirebaseService, *fakeDocuments) {
+	t.Helper()
+	fake := &fakeDocuments{docs: make(map[string]map[string]interface{})}
+	return newTestFirebase(t, fake.serve), fake
+}
+
+func (f *fakeDocuments) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, ":commit"):
+		f.commit(w, r)
+		return
+	case strings.HasSuffix(r.URL.Path, ":runQuery"):
+		f.runQuery(w, r)
+		return
+	}
+
+	_, path, _ := strings.Cut(r.URL.Path, "/documents/")
+	var body struct {
+		Fields map[string]interface{} `json:"fields"`
+	}
+	if r.Body != nil {
+		json.NewDecoder(r.Body).Decode(&body)
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		if id := r.URL.Query().Get("documentId"); id != "" {
+			path += "/" + id
+		} else {
+			path = fmt.Sprintf("%s/doc-%d", path, len(f.docs)+1)
+		}
+		f.docs[path] = body.Fields
+	case http.MethodPatch:
+		fields, ok := f.docs[path]
+		if !ok {
+			http.Error(w, `{"error": {"status": "NOT_FOUND"}}`, http.StatusNotFound)
+			return
+		}
+		for _, field := range r.URL.Query()["updateMask.fieldPaths"] {
+			if value, ok := body.Fields[field]; ok {
+				fields[field] = value
+			} else {
+				delete(fields, field)
+			}
+		}
+	case http.MethodGet:
+		if _, ok := f.docs[path]; !ok {
+			http.Error(w, `{"error": {"status": "NOT_FOUND"}}`, http.StatusNotFound)
+			return
+		}
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"name":   "projects/test/databases/(default)/documents/" + path,
+		"fields": f.docs[path],
+	})
+}
+
+func (f *fakeDocuments) commit(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Writes []struct {
+			Update *struct {
+				Name   string                 `json:"name"`
+				Fields map[string]interface{} `json:"fields"`
+			} `json:"update"`
+			Delete string `json:"delete"`
+		} `json:"writes"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+	for _, write := range body.Writes {
+		if write.Update != nil {
+			_, path, _ := strings.Cut(write.Update.Name, "/documents/")
+			f.docs[path] = write.Update.Fields
+		} else {
+			_, path, _ := strings.Cut(write.Delete, "/documents/")
+			delete(f.docs, path)
+		}
+	}
+	w.Write([]byte(`{}`))
+}
+
+type fakeFilter struct {
+	FieldFilter *struct {
+		Field struct {
+			FieldPath string `json:"fieldPath"`
+		} `json:"field"`
+		Op    string            `json:"op"`
+		Value map[string]string `json:"value"`
+	} `json:"fieldFilter"`
+	CompositeFilter *struct {
+		Filters []fakeFilter `json:"filters"`
+	} `json:"compositeFilter"`
+}
+
+// matches reports whether fields pass the filter; composite filters are ANDs
+func (filter fakeFilter) matches(fields map[string]interface{}) bool {
+	if filter.CompositeFilter != nil {
+		for _, inner := range filter.CompositeFilter.Filters {
+			if !inner.matches(fields) {
+				return false
+			}
+		}
+		return true
+	}
+
+	field, _ := fields[filter.FieldFilter.Field.FieldPath].(map[string]interface{})
+	switch want := filter.FieldFilter.Value; filter.FieldFilter.Op {
+	case "EQUAL":
+		return field["stringValue"] == want["stringValue"]
+	case "GREATER_THAN_OR_EQUAL":
+		value, _ := field["timestampValue"].(string)
+		got, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return false
+		}
+		since, _ := time.Parse(time.RFC3339, want["timestampValue"])
+		return !got.Before(since)
+	}
+	return false
+}
+
+func (f *fakeDocuments) runQuery(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		StructuredQuery struct {
+			From []struct {
+				CollectionID string `json:"collectionId"`
+			} `json:"from"`
+			Where fakeFilter `json:"where"`
+		} `json:"structuredQuery"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+
+	results := []interface{}{}
+	collection := body.StructuredQuery.From[0].CollectionID + "/"
+	for path, fields := range f.docs {
+		if strings.HasPrefix(path, collection) && body.StructuredQuery.Where.matches(fields) {
+			results = append(results, map[string]interface{}{"document": map[string]interface{}{
+				"name":   "projects/test/databases/(default)/documents/" + path,
+				"fields": fields,
+			}})
+		}
+	}
+	json.NewEncoder(w).Encode(results)
+}
+
+func TestMeetingPersistence(t *testing.T) {
+	s, _ := newFakeDocuments(t)
+	description, location, eventID := "Weekly sync", "Room 4", "event-1"
+	start := time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC)
+	meeting := &models.Meeting{
+		UserID:        "user-1",
+		Title:         "Standup",
+		Description:   &description,
+		StartTime:     start,
+		EndTime:       start.Add(30 * time.Minute),
+		Attendees:     []string{"a@example.com", "b@example.com"},
+		Location:      &location,
+		MeetingType:   "video",
+		Status:        "scheduled",
+		GoogleEventID: &eventID,
+	}
+
+	id, err := s.CreateMeeting(meeting)
+	if err != nil {
+		t.Fatalf("CreateMeeting: %v", err)
+	}
+	got, err := s.GetMeeting(id)
+	if err != nil {
+		t.Fatalf("GetMeeting: %v", err)
+	}
+
+	meeting.ID = id
+	meeting.UpdatedAt = time.Time{}
+	got.UpdatedAt = time.Time{}
+	if !reflect.DeepEqual(got, meeting) {
+		t.Errorf("read back %+v, want %+v", got, meeting)
+	}
+
+	if _, err := s.GetMeeting("missing"); err != ErrNotFound {
+		t.Errorf("GetMeeting(missing) err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestReminderPersistence(t *testing.T) {
+	s, _ := newFakeDocuments(t)
+	description, eventID := "Before the review", "event-2"
+	reminder := &models.Reminder{
+		UserID:        "user-1",
+		Title:         "Send agenda",
+		Description:   &description,
+		ReminderTime:  time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC),
+		ReminderType:  "personal",
+		Priority:      "high",
+		GoogleEventID: &eventID,
+	}
+
+	id, err := s.CreateReminder(reminder)
+	if err != nil {
+		t.Fatalf("CreateReminder: %v", err)
+	}
+	if err := s.UpdateReminder(id, map[string]interface{}{"isCompleted": true}); err != nil {
+		t.Fatalf("UpdateReminder: %v", err)
+	}
+	got, err := s.GetReminder(id)
+	if err != nil {
+		t.Fatalf("GetReminder: %v", err)
+	}
+
+	reminder.ID = id
+	reminder.IsCompleted = true
+	reminder.UpdatedAt = got.UpdatedAt
+	if !reflect.DeepEqual(got, reminder) {
+		t.Errorf("read back %+v, want %+v", got, reminder)
+	}
+
+	if err := s.UpdateReminder("missing", map[string]interface{}{"isCompleted": true}); err != ErrNotFound {
+		t.Errorf("UpdateReminder(missing) err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUpdateSetsAndClearsFields(t *testing.T) {
+	start := time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC)
+	due := start.Add(48 * time.Hour)
+
+	tests := []struct {
+		collection string
+		field      string
+		value      interface{}
+		create     func(s *FirebaseService) (string, error)
+		update     func(s *FirebaseService, id string, updates map[string]interface{}) error
+		read       func(s *FirebaseService, id string) (interface{}, error) // the field's value, nil when unset
+	}{
+		{
+			collection: "tasks", field: "dueDate", value: due,
+			create: func(s *FirebaseService) (string, error) {
+				return s.CreateTask(&models.Task{UserID: "user-1", Title: "Write report", Status: "todo", Priority: "medium"})
+			},
+			update: (*FirebaseService).UpdateTask,
+			read: func(s *FirebaseService, id string) (interface{}, error) {
+				task, err := s.GetTask(id)
+				if err != nil || task.DueDate == nil {
+					return nil, err
+				}
+				return *task.DueDate, nil
+			},
+		},
+		{
+			collection: "meetings", field: "location", value: "Room 4",
+			create: func(s *FirebaseService) (string, error) {
+				return s.CreateMeeting(&models.Meeting{UserID: "user-1", Title: "Standup", StartTime: start, EndTime: start.Add(time.Hour), MeetingType: "video", Status: "scheduled"})
+			},
+			update: (*FirebaseService).UpdateMeeting,
+			read: func(s *FirebaseService, id string) (interface{}, error) {
+				meeting, err := s.GetMeeting(id)
+				if err != nil || meeting.Location == nil {
+					return nil, err
+				}
+				return *meeting.Location, nil
+			},
+		},
+		{
+			collection: "reminders", field: "description", value: "Bring the slides",
+			create: func(s *FirebaseService) (string, error) {
+				return s.CreateReminder(&models.Reminder{UserID: "user-1", Title: "Review", ReminderTime: start, ReminderType: "personal", Priority: "low"})
+			},
+			update: (*FirebaseService).UpdateReminder,
+			read: func(s *FirebaseService, id string) (interface{}, error) {
+				reminder, err := s.GetReminder(id)
+				if err != nil || reminder.Description == nil {
+					return nil, err
+				}
+				return *reminder.Description, nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.collection, func(t *testing.T) {
+			s, _ := newFakeDocuments(t)
+			id, err := tt.create(s)
+			if err != nil {
+				t.Fatalf("create: %v", err)
+			}
+
+			steps := []struct {
+				value interface{}
+				want  interface{}
+			}{
+				{tt.value, tt.value},
+				{DeleteField, nil},
+				{tt.value, tt.value},
+			}
+			for i, step := range steps {
+				if err := tt.update(s, id, map[string]interface{}{tt.field: step.value}); err != nil {
+					t.Fatalf("step %d: update: %v", i+1, err)
+				}
+				got, err := tt.read(s, id)
+				if err != nil {
+					t.Fatalf("step %d: read: %v", i+1, err)
+				}
+				if !reflect.DeepEqual(got, step.want) {
+					t.Errorf("step %d: %s = %v, want %v", i+1, tt.field, got, step.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTaskHours(t *testing.T) {
+	s, fake := newFakeDocuments(t)
+	estimated, actual := 1.5, 0.25
+
+	id, err := s.CreateTask(&models.Task{UserID: "user-1", Title: "Write report", Status: "todo", EstimatedHours: &estimated, ActualHours: &actual})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	task, err := s.GetTask(id)
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	if task.EstimatedHours == nil || *task.EstimatedHours != 1.5 || task.ActualHours == nil || *task.ActualHours != 0.25 {
+		t.Errorf("hours = %v, %v; want 1.5, 0.25", task.EstimatedHours, task.ActualHours)
+	}
+
+	if err := s.UpdateTask(id, map[string]interface{}{"actualHours": 2.75}); err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+	if task, err = s.GetTask(id); err != nil {
+		t.Fatal(err)
+	}
+	if task.ActualHours == nil || *task.ActualHours != 2.75 {
+		t.Errorf("actual hours after update = %v, want 2.75", task.ActualHours)
+	}
+
+	// Tasks written when hours were whole numbers stored them as integers
+	fake.docs["tasks/legacy"] = map[string]interface{}{
+		"userId":         map[string]interface{}{"stringValue": "user-1"},
+		"title":          map[string]interface{}{"stringValue": "Old task"},
+		"estimatedHours": map[string]interface{}{"integerValue": "3"},
+		"actualHours":    map[string]interface{}{"integerValue": "4"},
+	}
+	legacy, err := s.GetTask("legacy")
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	if legacy.EstimatedHours == nil || *legacy.EstimatedHours != 3 || legacy.ActualHours == nil || *legacy.ActualHours != 4 {
+		t.Errorf("legacy hours = %v, %v; want 3, 4", legacy.EstimatedHours, legacy.ActualHours)
+	}
+}
+
+func TestGetChangesSince(t *testing.T) {
+	s, _ := newFakeDocuments(t)
+	clk := s.clock.(*clock.Fake)
+	create := func(id string, err error) string {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+
+	// Everything the client already has
+	untouched := create(s.CreateTask(&models.Task{UserID: "user-1", Title: "Untouched", Status: "todo"}))
+	edited := create(s.CreateTask(&models.Task{UserID: "user-1", Title: "Edited", Status: "todo"}))
+	gone := create(s.CreateTask(&models.Task{UserID: "user-1", Title: "Deleted", Status: "todo"}))
+	create(s.CreateMeeting(&models.Meeting{UserID: "user-1", Title: "Old meeting"}))
+	snoozed := create(s.CreateReminder(&models.Reminder{UserID: "user-1", Title: "Snoozed"}))
+
+	clk.Advance(time.Hour)
+	since := clk.Now()
+
+	if err := s.UpdateTask(edited, map[string]interface{}{"title": "Edited again"}); err != nil {
+		t.Fatal(err)
+	}
+	added := create(s.CreateMeeting(&models.Meeting{UserID: "user-1", Title: "New meeting"}))
+	if err := s.UpdateReminder(snoozed, map[string]interface{}{"reminderTime": since.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	create(s.CreateTask(&models.Task{UserID: "user-2", Title: "Someone else's", Status: "todo"}))
+	if err := s.DeleteTask(&models.Task{ID: gone, UserID: "user-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteTask(&models.Task{ID: "theirs", UserID: "user-2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := s.GetChangesSince("user-1", since)
+	if err != nil {
+		t.Fatalf("GetChangesSince: %v", err)
+	}
+	if len(changes.Tasks) != 1 || changes.Tasks[0].ID != edited || changes.Tasks[0].Title != "Edited again" {
+		t.Errorf("tasks = %+v, want only %s", changes.Tasks, edited)
+	}
+	if len(changes.Meetings) != 1 || changes.Meetings[0].ID != added {
+		t.Errorf("meetings = %+v, want only %s", changes.Meetings, added)
+	}
+	if len(changes.Reminders) != 1 || changes.Reminders[0].ID != snoozed {
+		t.Errorf("reminders = %+v, want only %s", changes.Reminders, snoozed)
+	}
+	if len(changes.Deleted) != 1 || *changes.Deleted[0] != (models.Tombstone{UserID: "user-1", Type: "task", ItemID: gone, DeletedAt: since}) {
+		t.Errorf("deleted = %+v, want a tombstone for %s", changes.Deleted, gone)
+	}
+
+	// A later window holds nothing, and the earliest one holds everything left
+	clk.Advance(time.Minute)
+	later, err := s.GetChangesSince("user-1", clk.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(later.Tasks)+len(later.Meetings)+len(later.Reminders)+len(later.Deleted) != 0 {
+		t.Errorf("changes after the last write = %+v, want none", later)
+	}
+	all, err := s.GetChangesSince("user-1", since.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var taskIDs []string
+	for _, task := range all.Tasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+	slices.Sort(taskIDs)
+	if want := []string{untouched, edited}; !slices.Equal(taskIDs, want) || len(all.Meetings) != 2 || len(all.Reminders) != 1 || len(all.Deleted) != 1 {
+		t.Errorf("full window = tasks %v, %d meetings, %d reminders, %d deleted; want tasks %v, 2, 1, 1",
+			taskIDs, len(all.Meetings), len(all.Reminders), len(all.Deleted), want)
+	}
+}
@@ -2,23 +2,41 @@ package services
 
 import (
 	"bytes"
+	"context"
+	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"focusflow-be/internal/clock"
 	"focusflow-be/internal/config"
 	"focusflow-be/internal/models"
+	"focusflow-be/internal/recurrence"
 )
 
+// ErrNotFound is returned when a requested document doesn't exist
+var ErrNotFound = errors.New("document not found")
+
 type FirebaseService struct {
 	projectID string
 	apiKey    string
 	baseURL   string
 	client    *http.Client
+	retry     retryPolicy
+	clock     clock.Clock
+	owner     string // identifies this instance as a lock holder
+
+	// The request a copy made by WithContext is bound to; nil otherwise
+	ctx context.Context
+
+	userChanged []func(userID string)
 }
 
 func NewFirebaseService(cfg *config.Config) (*FirebaseService, error) {
@@ -28,42 +46,100 @@ func NewFirebaseService(cfg *config.Config) (*FirebaseService, error) {
 
 	log.Printf("🔥 Initializing Firebase REST API for project: %s", cfg.FirebaseProjectID)
 
+	owner, err := NewDocumentID()
+	if err != nil {
+		return nil, err
+	}
+
 	return &FirebaseService{
 		projectID: cfg.FirebaseProjectID,
 		apiKey:    cfg.FirebaseAPIKey,
 		baseURL:   fmt.Sprintf("https://firestore.googleapis.com/v1/projects/%s/databases/(default)/documents", cfg.FirebaseProjectID),
 		client:    &http.Client{Timeout: 30 * time.Second},
+		retry: retryPolicy{
+			maxAttempts: cfg.FirestoreRetryAttempts,
+			deadline:    cfg.FirestoreRetryDeadline,
+			baseDelay:   100 * time.Millisecond,
+			maxDelay:    2 * time.Second,
+		},
+		clock: cfg.Clock,
+		owner: owner,
 	}, nil
 }
 
+// WithContext returns a copy of the service whose Firestore calls are tied to
+// ctx: they are abandoned when it is cancelled and timed when it carries a
+// timer from WithFirestoreTimer.
+func (s *FirebaseService) WithContext(ctx context.Context) Store {
+	scoped := *s
+	scoped.ctx = ctx
+	return &scoped
+}
+
 func (s *FirebaseService) Close() error {
 	log.Printf("🔥 Firebase service closed")
 	return nil
 }
 
-// Helper function to make HTTP requests to Firestore REST API
+// Helper function to make HTTP requests to Firestore REST API. A request's
+// Firestore calls all run one after another on its goroutine, so there is no
+// per-request limit on calls in flight; anything that fans them out needs one.
 func (s *FirebaseService) makeRequest(method, path string, body interface{}) (*http.Response, error) {
+	return s.makeRequestWithRetry(s.retry, method, path, body)
+}
+
+func (s *FirebaseService) makeRequestWithRetry(retry retryPolicy, method, path string, body interface{}) (*http.Response, error) {
 	url := s.baseURL + path
 	if s.apiKey != "" {
-		url += "?key=" + s.apiKey
+		if strings.Contains(path, "?") {
+			url += "&key=" + s.apiKey
+		} else {
+			url += "?key=" + s.apiKey
+		}
 	}
 
-	var reqBody io.Reader
+	var jsonBody []byte
 	if body != nil {
-		jsonBody, err := json.Marshal(body)
+		var err error
+		jsonBody, err = json.Marshal(body)
 		if err != nil {
 			return nil, err
 		}
-		reqBody = bytes.NewBuffer(jsonBody)
 	}
 
-	req, err := http.NewRequest(method, url, reqBody)
-	if err != nil {
-		return nil, err
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if !idempotent(method, path) {
+		retry.maxAttempts = 1
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	return s.client.Do(req)
+	start := time.Now()
+	resp, err := retry.do(ctx, func() (*http.Response, error) {
+		var reqBody io.Reader
+		if jsonBody != nil {
+			reqBody = bytes.NewReader(jsonBody)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		return s.client.Do(req)
+	})
+
+	if record := firestoreTimer(ctx); record != nil {
+		if err != nil {
+			record(time.Since(start))
+		} else {
+			resp.Body = &timedBody{ReadCloser: resp.Body, start: start, record: record}
+		}
+	}
+	return resp, err
 }
 
 // Convert our models to Firestore document format
@@ -83,6 +159,27 @@ func (s *FirebaseService) toFirestoreDoc(data interface{}) map[string]interface{
 		if v.RefreshToken != nil {
 			fields["refreshToken"] = map[string]interface{}{"stringValue": *v.RefreshToken}
 		}
+		if v.Locale != "" {
+			fields["locale"] = map[string]interface{}{"stringValue": v.Locale}
+		}
+		if v.Timezone != "" {
+			fields["timezone"] = map[string]interface{}{"stringValue": v.Timezone}
+		}
+		if v.CalendarID != "" {
+			fields["calendarId"] = map[string]interface{}{"stringValue": v.CalendarID}
+		}
+		if v.WebhookURL != "" {
+			fields["webhookUrl"] = map[string]interface{}{"stringValue": v.WebhookURL}
+		}
+		if v.DefaultReminderLead != nil {
+			fields["defaultReminderLead"] = map[string]interface{}{"integerValue": fmt.Sprintf("%d", *v.DefaultReminderLead)}
+		}
+		if v.CarryForwardReminders {
+			fields["carryForwardReminders"] = map[string]interface{}{"booleanValue": true}
+		}
+		if v.AutoMeetingStatus {
+			fields["autoMeetingStatus"] = map[string]interface{}{"booleanValue": true}
+		}
 		fields["createdAt"] = map[string]interface{}{"timestampValue": v.CreatedAt.Format(time.RFC3339)}
 		fields["lastLogin"] = map[string]interface{}{"timestampValue": v.LastLogin.Format(time.RFC3339)}
 
@@ -94,7 +191,16 @@ func (s *FirebaseService) toFirestoreDoc(data interface{}) map[string]interface{
 		}
 		fields["completed"] = map[string]interface{}{"booleanValue": v.Completed}
 		fields["status"] = map[string]interface{}{"stringValue": v.Status}
+		if v.Blocked {
+			fields["blocked"] = map[string]interface{}{"booleanValue": true}
+		}
+		if v.BlockReason != nil {
+			fields["blockReason"] = map[string]interface{}{"stringValue": *v.BlockReason}
+		}
 		fields["priority"] = map[string]interface{}{"stringValue": v.Priority}
+		if len(v.Tags) > 0 {
+			fields["tags"] = s.toFirestoreValue(v.Tags)
+		}
 		if v.StartDate != nil {
 			fields["startDate"] = map[string]interface{}{"timestampValue": v.StartDate.Format(time.RFC3339)}
 		}
@@ -102,13 +208,117 @@ func (s *FirebaseService) toFirestoreDoc(data interface{}) map[string]interface{
 			fields["dueDate"] = map[string]interface{}{"timestampValue": v.DueDate.Format(time.RFC3339)}
 		}
 		if v.EstimatedHours != nil {
-			fields["estimatedHours"] = map[string]interface{}{"integerValue": fmt.Sprintf("%d", *v.EstimatedHours)}
+			fields["estimatedHours"] = map[string]interface{}{"doubleValue": *v.EstimatedHours}
 		}
 		if v.ActualHours != nil {
-			fields["actualHours"] = map[string]interface{}{"integerValue": fmt.Sprintf("%d", *v.ActualHours)}
+			fields["actualHours"] = map[string]interface{}{"doubleValue": *v.ActualHours}
+		}
+		if v.Order != nil {
+			fields["order"] = map[string]interface{}{"integerValue": fmt.Sprintf("%d", *v.Order)}
+		}
+		if v.StartedAt != nil {
+			fields["startedAt"] = map[string]interface{}{"timestampValue": v.StartedAt.Format(time.RFC3339)}
+		}
+		if v.CompletedAt != nil {
+			fields["completedAt"] = map[string]interface{}{"timestampValue": v.CompletedAt.Format(time.RFC3339)}
+		}
+		fields["createdAt"] = map[string]interface{}{"timestampValue": v.CreatedAt.Format(time.RFC3339)}
+		fields["updatedAt"] = map[string]interface{}{"timestampValue": v.UpdatedAt.Format(time.RFC3339)}
+		if len(v.Metadata) > 0 {
+			fields["metadata"] = s.toFirestoreValue(v.Metadata)
+		}
+		if v.PreviousStatus != nil {
+			fields["previousStatus"] = map[string]interface{}{"stringValue": *v.PreviousStatus}
+		}
+
+	case *models.Meeting:
+		fields["userId"] = map[string]interface{}{"stringValue": v.UserID}
+		fields["title"] = map[string]interface{}{"stringValue": v.Title}
+		if v.Description != nil {
+			fields["description"] = map[string]interface{}{"stringValue": *v.Description}
+		}
+		fields["startTime"] = map[string]interface{}{"timestampValue": v.StartTime.Format(time.RFC3339)}
+		fields["endTime"] = map[string]interface{}{"timestampValue": v.EndTime.Format(time.RFC3339)}
+		if len(v.Attendees) > 0 {
+			fields["attendees"] = s.toFirestoreValue(v.Attendees)
+		}
+		if v.Location != nil {
+			fields["location"] = map[string]interface{}{"stringValue": *v.Location}
+		}
+		fields["meetingType"] = map[string]interface{}{"stringValue": v.MeetingType}
+		fields["status"] = map[string]interface{}{"stringValue": v.Status}
+		if v.Attended != nil {
+			fields["attended"] = map[string]interface{}{"booleanValue": *v.Attended}
+		}
+		if v.BufferBefore != nil {
+			fields["bufferBefore"] = map[string]interface{}{"integerValue": fmt.Sprintf("%d", *v.BufferBefore)}
+		}
+		if v.BufferAfter != nil {
+			fields["bufferAfter"] = map[string]interface{}{"integerValue": fmt.Sprintf("%d", *v.BufferAfter)}
+		}
+		if v.CompletedAt != nil {
+			fields["completedAt"] = map[string]interface{}{"timestampValue": v.CompletedAt.Format(time.RFC3339)}
+		}
+		if v.Notes != nil {
+			fields["notes"] = map[string]interface{}{"stringValue": *v.Notes}
+		}
+		if len(v.ActionItems) > 0 {
+			fields["actionItems"] = s.toFirestoreValue(v.ActionItems)
+		}
+		if len(v.ReminderMinutes) > 0 {
+			fields["reminderMinutes"] = s.toFirestoreValue(v.ReminderMinutes)
+		}
+		if v.ExternalID != nil {
+			fields["externalId"] = map[string]interface{}{"stringValue": *v.ExternalID}
+		}
+		if v.RescheduleCount > 0 {
+			fields["rescheduleCount"] = map[string]interface{}{"integerValue": fmt.Sprintf("%d", v.RescheduleCount)}
+		}
+		if v.NotifyAttendees != nil {
+			fields["notifyAttendees"] = map[string]interface{}{"booleanValue": *v.NotifyAttendees}
+		}
+		if v.Tentative {
+			fields["tentative"] = map[string]interface{}{"booleanValue": true}
+		}
+		if v.GoogleEventID != nil {
+			fields["googleEventId"] = map[string]interface{}{"stringValue": *v.GoogleEventID}
 		}
 		fields["createdAt"] = map[string]interface{}{"timestampValue": v.CreatedAt.Format(time.RFC3339)}
 		fields["updatedAt"] = map[string]interface{}{"timestampValue": v.UpdatedAt.Format(time.RFC3339)}
+
+	case *models.Reminder:
+		fields["userId"] = map[string]interface{}{"stringValue": v.UserID}
+		fields["title"] = map[string]interface{}{"stringValue": v.Title}
+		if v.Description != nil {
+			fields["description"] = map[string]interface{}{"stringValue": *v.Description}
+		}
+		fields["reminderTime"] = map[string]interface{}{"timestampValue": v.ReminderTime.Format(time.RFC3339)}
+		fields["reminderType"] = map[string]interface{}{"stringValue": v.ReminderType}
+		fields["isCompleted"] = map[string]interface{}{"booleanValue": v.IsCompleted}
+		fields["priority"] = map[string]interface{}{"stringValue": v.Priority}
+		if v.RolledOverCount > 0 {
+			fields["rolledOverCount"] = map[string]interface{}{"integerValue": fmt.Sprintf("%d", v.RolledOverCount)}
+		}
+		if v.Recurrence != nil {
+			fields["recurrence"] = s.toFirestoreValue(v.Recurrence)
+		}
+		if v.CompletedAt != nil {
+			fields["completedAt"] = map[string]interface{}{"timestampValue": v.CompletedAt.Format(time.RFC3339)}
+		}
+		if v.GoogleEventID != nil {
+			fields["googleEventId"] = map[string]interface{}{"stringValue": *v.GoogleEventID}
+		}
+		if v.TaskID != nil {
+			fields["taskId"] = map[string]interface{}{"stringValue": *v.TaskID}
+		}
+		fields["createdAt"] = map[string]interface{}{"timestampValue": v.CreatedAt.Format(time.RFC3339)}
+		fields["updatedAt"] = map[string]interface{}{"timestampValue": v.UpdatedAt.Format(time.RFC3339)}
+
+	case *models.Tombstone:
+		fields["userId"] = map[string]interface{}{"stringValue": v.UserID}
+		fields["type"] = map[string]interface{}{"stringValue": v.Type}
+		fields["itemId"] = map[string]interface{}{"stringValue": v.ItemID}
+		fields["deletedAt"] = map[string]interface{}{"timestampValue": v.DeletedAt.Format(time.RFC3339)}
 	}
 
 	return doc
@@ -138,6 +348,27 @@ func (s *FirebaseService) fromFirestoreDoc(doc map[string]interface{}, result in
 		if refreshToken, ok := s.getStringValue(fields, "refreshToken"); ok {
 			v.RefreshToken = &refreshToken
 		}
+		if locale, ok := s.getStringValue(fields, "locale"); ok {
+			v.Locale = locale
+		}
+		if timezone, ok := s.getStringValue(fields, "timezone"); ok {
+			v.Timezone = timezone
+		}
+		if calendarID, ok := s.getStringValue(fields, "calendarId"); ok {
+			v.CalendarID = calendarID
+		}
+		if webhookURL, ok := s.getStringValue(fields, "webhookUrl"); ok {
+			v.WebhookURL = webhookURL
+		}
+		if lead, ok := s.getIntegerValue(fields, "defaultReminderLead"); ok {
+			v.DefaultReminderLead = &lead
+		}
+		if carryForward, ok := s.getBooleanValue(fields, "carryForwardReminders"); ok {
+			v.CarryForwardReminders = carryForward
+		}
+		if autoStatus, ok := s.getBooleanValue(fields, "autoMeetingStatus"); ok {
+			v.AutoMeetingStatus = autoStatus
+		}
 		if createdAt, ok := s.getTimestampValue(fields, "createdAt"); ok {
 			v.CreatedAt = createdAt
 		}
@@ -161,238 +392,741 @@ func (s *FirebaseService) fromFirestoreDoc(doc map[string]interface{}, result in
 		if status, ok := s.getStringValue(fields, "status"); ok {
 			v.Status = status
 		}
+		if blocked, ok := s.getBooleanValue(fields, "blocked"); ok {
+			v.Blocked = blocked
+		}
+		if blockReason, ok := s.getStringValue(fields, "blockReason"); ok {
+			v.BlockReason = &blockReason
+		}
 		if priority, ok := s.getStringValue(fields, "priority"); ok {
 			v.Priority = priority
 		}
+		if tags, ok := s.getStringArrayValue(fields, "tags"); ok {
+			v.Tags = tags
+		}
 		if startDate, ok := s.getTimestampValue(fields, "startDate"); ok {
 			v.StartDate = &startDate
 		}
 		if dueDate, ok := s.getTimestampValue(fields, "dueDate"); ok {
 			v.DueDate = &dueDate
 		}
+		if estimatedHours, ok := s.getNumberValue(fields, "estimatedHours"); ok {
+			v.EstimatedHours = &estimatedHours
+		}
+		if actualHours, ok := s.getNumberValue(fields, "actualHours"); ok {
+			v.ActualHours = &actualHours
+		}
+		if order, ok := s.getIntegerValue(fields, "order"); ok {
+			v.Order = &order
+		}
+		if startedAt, ok := s.getTimestampValue(fields, "startedAt"); ok {
+			v.StartedAt = &startedAt
+		}
+		if completedAt, ok := s.getTimestampValue(fields, "completedAt"); ok {
+			v.CompletedAt = &completedAt
+		}
+		if googleEventID, ok := s.getStringValue(fields, "googleEventId"); ok {
+			v.GoogleEventID = &googleEventID
+		}
+		if createdAt, ok := s.getTimestampValue(fields, "createdAt"); ok {
+			v.CreatedAt = createdAt
+		}
+		if updatedAt, ok := s.getTimestampValue(fields, "updatedAt"); ok {
+			v.UpdatedAt = updatedAt
+		}
+		if metadata, ok := s.getStringMapValue(fields, "metadata"); ok {
+			v.Metadata = metadata
+		}
+		if previousStatus, ok := s.getStringValue(fields, "previousStatus"); ok {
+			v.PreviousStatus = &previousStatus
+		}
+
+	case *models.Meeting:
+		if userId, ok := s.getStringValue(fields, "userId"); ok {
+			v.UserID = userId
+		}
+		if title, ok := s.getStringValue(fields, "title"); ok {
+			v.Title = title
+		}
+		if description, ok := s.getStringValue(fields, "description"); ok {
+			v.Description = &description
+		}
+		if startTime, ok := s.getTimestampValue(fields, "startTime"); ok {
+			v.StartTime = startTime
+		}
+		if endTime, ok := s.getTimestampValue(fields, "endTime"); ok {
+			v.EndTime = endTime
+		}
+		if attendees, ok := s.getStringArrayValue(fields, "attendees"); ok {
+			v.Attendees = attendees
+		}
+		if location, ok := s.getStringValue(fields, "location"); ok {
+			v.Location = &location
+		}
+		if meetingType, ok := s.getStringValue(fields, "meetingType"); ok {
+			v.MeetingType = meetingType
+		}
+		if status, ok := s.getStringValue(fields, "status"); ok {
+			v.Status = status
+		}
+		if attended, ok := s.getBooleanValue(fields, "attended"); ok {
+			v.Attended = &attended
+		}
+		if bufferBefore, ok := s.getIntegerValue(fields, "bufferBefore"); ok {
+			v.BufferBefore = &bufferBefore
+		}
+		if bufferAfter, ok := s.getIntegerValue(fields, "bufferAfter"); ok {
+			v.BufferAfter = &bufferAfter
+		}
+		if completedAt, ok := s.getTimestampValue(fields, "completedAt"); ok {
+			v.CompletedAt = &completedAt
+		}
+		if notes, ok := s.getStringValue(fields, "notes"); ok {
+			v.Notes = &notes
+		}
+		if actionItems, ok := s.getActionItemsValue(fields, "actionItems"); ok {
+			v.ActionItems = actionItems
+		}
+		if reminders, ok := s.getEventRemindersValue(fields, "reminderMinutes"); ok {
+			v.ReminderMinutes = reminders
+		}
+		if externalID, ok := s.getStringValue(fields, "externalId"); ok {
+			v.ExternalID = &externalID
+		}
+		v.RescheduleCount, _ = s.getIntegerValue(fields, "rescheduleCount")
+		if notify, ok := s.getBooleanValue(fields, "notifyAttendees"); ok {
+			v.NotifyAttendees = &notify
+		}
+		if tentative, ok := s.getBooleanValue(fields, "tentative"); ok {
+			v.Tentative = tentative
+		}
+		if googleEventID, ok := s.getStringValue(fields, "googleEventId"); ok {
+			v.GoogleEventID = &googleEventID
+		}
+		if createdAt, ok := s.getTimestampValue(fields, "createdAt"); ok {
+			v.CreatedAt = createdAt
+		}
+		// Documents written before updatedAt was tracked fall back to createdAt
+		if updatedAt, ok := s.getTimestampValue(fields, "updatedAt"); ok {
+			v.UpdatedAt = updatedAt
+		} else {
+			v.UpdatedAt = v.CreatedAt
+		}
+		if v.BufferBefore != nil || v.BufferAfter != nil {
+			blockedStart, blockedEnd := v.BlockedWindow()
+			v.BlockedStart, v.BlockedEnd = &blockedStart, &blockedEnd
+		}
+
+	case *models.Reminder:
+		if userId, ok := s.getStringValue(fields, "userId"); ok {
+			v.UserID = userId
+		}
+		if title, ok := s.getStringValue(fields, "title"); ok {
+			v.Title = title
+		}
+		if description, ok := s.getStringValue(fields, "description"); ok {
+			v.Description = &description
+		}
+		if reminderTime, ok := s.getTimestampValue(fields, "reminderTime"); ok {
+			v.ReminderTime = reminderTime
+		}
+		if reminderType, ok := s.getStringValue(fields, "reminderType"); ok {
+			v.ReminderType = reminderType
+		}
+		if isCompleted, ok := s.getBooleanValue(fields, "isCompleted"); ok {
+			v.IsCompleted = isCompleted
+		}
+		if priority, ok := s.getStringValue(fields, "priority"); ok {
+			v.Priority = priority
+		}
+		if rolledOverCount, ok := s.getIntegerValue(fields, "rolledOverCount"); ok {
+			v.RolledOverCount = rolledOverCount
+		}
+		if rule, ok := s.getRecurrenceValue(fields, "recurrence"); ok {
+			v.Recurrence = rule
+		}
+		if completedAt, ok := s.getTimestampValue(fields, "completedAt"); ok {
+			v.CompletedAt = &completedAt
+		}
+		if googleEventID, ok := s.getStringValue(fields, "googleEventId"); ok {
+			v.GoogleEventID = &googleEventID
+		}
+		if taskID, ok := s.getStringValue(fields, "taskId"); ok {
+			v.TaskID = &taskID
+		}
 		if createdAt, ok := s.getTimestampValue(fields, "createdAt"); ok {
 			v.CreatedAt = createdAt
 		}
 		if updatedAt, ok := s.getTimestampValue(fields, "updatedAt"); ok {
 			v.UpdatedAt = updatedAt
+		} else {
+			v.UpdatedAt = v.CreatedAt
+		}
+
+	case *models.Tombstone:
+		if userId, ok := s.getStringValue(fields, "userId"); ok {
+			v.UserID = userId
+		}
+		if itemType, ok := s.getStringValue(fields, "type"); ok {
+			v.Type = itemType
+		}
+		if itemID, ok := s.getStringValue(fields, "itemId"); ok {
+			v.ItemID = itemID
+		}
+		if deletedAt, ok := s.getTimestampValue(fields, "deletedAt"); ok {
+			v.DeletedAt = deletedAt
 		}
 	}
 
 	return nil
 }
 
-// Helper functions to extract values from Firestore fields
-func (s *FirebaseService) getStringValue(fields map[string]interface{}, key string) (string, bool) {
-	if field, ok := fields[key].(map[string]interface{}); ok {
-		if value, ok := field["stringValue"].(string); ok {
-			return value, true
+// DeleteField can be used as an update value to remove a field from a document,
+// mirroring firestore.Delete in the official client
+var DeleteField = deleteField{}
+
+type deleteField struct{}
+
+// Convert a plain update map to Firestore field values
+func (s *FirebaseService) toFirestoreFields(updates map[string]interface{}) map[string]interface{} {
+	fields := make(map[string]interface{})
+	for key, value := range updates {
+		if v := s.toFirestoreValue(value); v != nil {
+			fields[key] = v
 		}
 	}
-	return "", false
+	return fields
 }
 
-func (s *FirebaseService) getBooleanValue(fields map[string]interface{}, key string) (bool, bool) {
-	if field, ok := fields[key].(map[string]interface{}); ok {
-		if value, ok := field["booleanValue"].(bool); ok {
-			return value, true
+// Convert a single Go value to a Firestore value, or nil if unsupported
+func (s *FirebaseService) toFirestoreValue(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case string:
+		return map[string]interface{}{"stringValue": v}
+	case *string:
+		if v != nil {
+			return map[string]interface{}{"stringValue": *v}
+		}
+	case time.Time:
+		return map[string]interface{}{"timestampValue": v.Format(time.RFC3339)}
+	case bool:
+		return map[string]interface{}{"booleanValue": v}
+	case int:
+		return map[string]interface{}{"integerValue": fmt.Sprintf("%d", v)}
+	case float64:
+		return map[string]interface{}{"doubleValue": v}
+	case []string:
+		values := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			values = append(values, map[string]interface{}{"stringValue": item})
+		}
+		return map[string]interface{}{"arrayValue": map[string]interface{}{"values": values}}
+	case map[string]string:
+		fields := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			fields[key] = map[string]interface{}{"stringValue": item}
+		}
+		return map[string]interface{}{"mapValue": map[string]interface{}{"fields": fields}}
+	case []models.ActionItem:
+		values := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			fields := map[string]interface{}{
+				"id":   map[string]interface{}{"stringValue": item.ID},
+				"text": map[string]interface{}{"stringValue": item.Text},
+				"done": map[string]interface{}{"booleanValue": item.Done},
+			}
+			if item.Assignee != nil {
+				fields["assignee"] = map[string]interface{}{"stringValue": *item.Assignee}
+			}
+			if item.TaskID != nil {
+				fields["taskId"] = map[string]interface{}{"stringValue": *item.TaskID}
+			}
+			values = append(values, map[string]interface{}{"mapValue": map[string]interface{}{"fields": fields}})
+		}
+		return map[string]interface{}{"arrayValue": map[string]interface{}{"values": values}}
+	case *recurrence.Rule:
+		if v == nil {
+			return nil
+		}
+		fields := map[string]interface{}{
+			"frequency": map[string]interface{}{"stringValue": string(v.Frequency)},
+		}
+		if v.Interval > 0 {
+			fields["interval"] = map[string]interface{}{"integerValue": fmt.Sprintf("%d", v.Interval)}
+		}
+		if v.Count > 0 {
+			fields["count"] = map[string]interface{}{"integerValue": fmt.Sprintf("%d", v.Count)}
+		}
+		if v.Until != nil {
+			fields["until"] = map[string]interface{}{"timestampValue": v.Until.Format(time.RFC3339)}
 		}
+		return map[string]interface{}{"mapValue": map[string]interface{}{"fields": fields}}
+	case []models.EventReminder:
+		values := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			fields := map[string]interface{}{
+				"method":  map[string]interface{}{"stringValue": item.Method},
+				"minutes": map[string]interface{}{"integerValue": fmt.Sprintf("%d", item.Minutes)},
+			}
+			values = append(values, map[string]interface{}{"mapValue": map[string]interface{}{"fields": fields}})
+		}
+		return map[string]interface{}{"arrayValue": map[string]interface{}{"values": values}}
 	}
-	return false, false
+	return nil
 }
 
-func (s *FirebaseService) getTimestampValue(fields map[string]interface{}, key string) (time.Time, bool) {
-	if field, ok := fields[key].(map[string]interface{}); ok {
-		if value, ok := field["timestampValue"].(string); ok {
-			if t, err := time.Parse(time.RFC3339, value); err == nil {
-				return t, true
-			}
-		}
+// Extract the document ID from a Firestore document's resource name
+func (s *FirebaseService) docID(doc map[string]interface{}) string {
+	if name, ok := doc["name"].(string); ok {
+		parts := strings.Split(name, "/")
+		return parts[len(parts)-1]
 	}
-	return time.Time{}, false
+	return ""
 }
 
-// User operations
-func (s *FirebaseService) CreateUser(user *models.UserSession) error {
-	doc := s.toFirestoreDoc(user)
-	resp, err := s.makeRequest("POST", "/users", doc)
+// Create a document in a collection and return its generated ID
+func (s *FirebaseService) createDocument(collection string, doc map[string]interface{}) (string, error) {
+	resp, err := s.makeRequest("POST", "/"+collection, doc)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to create user: %s", body)
+		return "", fmt.Errorf("failed to create document in %s: %s", collection, body)
 	}
 
-	log.Printf("✅ User created: %s", user.Email)
-	return nil
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+
+	if docID := s.docID(result); docID != "" {
+		return docID, nil
+	}
+	return "", fmt.Errorf("failed to extract document ID")
 }
 
-func (s *FirebaseService) GetUser(userID string) (*models.UserSession, error) {
-	resp, err := s.makeRequest("GET", "/users/"+userID, nil)
+// Fetch a single document by ID
+func (s *FirebaseService) getDocument(collection, docID string) (map[string]interface{}, error) {
+	resp, err := s.makeRequest("GET", "/"+collection+"/"+docID, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 404 {
-		return nil, fmt.Errorf("user not found")
+		return nil, ErrNotFound
 	}
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("failed to get user")
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get %s: %s", collection, body)
 	}
 
 	var doc map[string]interface{}
 	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
 		return nil, err
 	}
-
-	var user models.UserSession
-	if err := s.fromFirestoreDoc(doc, &user); err != nil {
-		return nil, err
-	}
-
-	return &user, nil
+	return doc, nil
 }
 
-func (s *FirebaseService) UpdateUser(userID string, updates map[string]interface{}) error {
-	// Create update document
-	doc := map[string]interface{}{
-		"fields": make(map[string]interface{}),
-	}
-	fields := doc["fields"].(map[string]interface{})
-
-	// Add lastLogin timestamp
-	updates["lastLogin"] = time.Now()
-
+// Apply a partial update to an existing document. Only the keys present in
+// updates are touched; keys set to DeleteField are removed from the document.
+func (s *FirebaseService) updateDocument(collection, docID string, updates map[string]interface{}) error {
+	params := url.Values{}
 	for key, value := range updates {
-		switch v := value.(type) {
-		case string:
-			fields[key] = map[string]interface{}{"stringValue": v}
-		case time.Time:
-			fields[key] = map[string]interface{}{"timestampValue": v.Format(time.RFC3339)}
-		case bool:
-			fields[key] = map[string]interface{}{"booleanValue": v}
+		// Skip values we can't encode rather than clearing them by accident
+		if value != DeleteField && s.toFirestoreValue(value) == nil {
+			continue
 		}
+		params.Add("updateMask.fieldPaths", key)
+	}
+	params.Set("currentDocument.exists", "true")
+
+	doc := map[string]interface{}{
+		"fields": s.toFirestoreFields(updates),
 	}
 
-	resp, err := s.makeRequest("PATCH", "/users/"+userID, doc)
+	resp, err := s.makeRequest("PATCH", "/"+collection+"/"+docID+"?"+params.Encode(), doc)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == 404 {
+		return ErrNotFound
+	}
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to update user: %s", body)
+		return fmt.Errorf("failed to update %s: %s", collection, body)
 	}
 
 	return nil
 }
 
-// Task operations
-func (s *FirebaseService) CreateTask(task *models.Task) (string, error) {
-	task.CreatedAt = time.Now()
-	task.UpdatedAt = time.Now()
-
-	doc := s.toFirestoreDoc(task)
-	resp, err := s.makeRequest("POST", "/tasks", doc)
+// Run a structured query and return the matching documents
+func (s *FirebaseService) runQuery(query map[string]interface{}) ([]map[string]interface{}, error) {
+	resp, err := s.makeRequest("POST", ":runQuery", map[string]interface{}{"structuredQuery": query})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("failed to create task: %s", body)
+		return nil, fmt.Errorf("failed to run query: %s", body)
 	}
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", err
+	var results []map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, err
 	}
 
-	// Extract document ID from the response
-	if name, ok := result["name"].(string); ok {
-		parts := strings.Split(name, "/")
-		if len(parts) > 0 {
-			docID := parts[len(parts)-1]
-			log.Printf("✅ Task created: %s (ID: %s)", task.Title, docID)
-			return docID, nil
+	var docs []map[string]interface{}
+	for _, result := range results {
+		if doc, ok := result["document"].(map[string]interface{}); ok {
+			docs = append(docs, doc)
 		}
 	}
-
-	return "", fmt.Errorf("failed to extract document ID")
+	return docs, nil
 }
 
-func (s *FirebaseService) GetTasks(userID string) ([]*models.Task, error) {
-	log.Printf("🔍 Fetching tasks for user: %s", userID)
+// Fetch documents by ID with a single batchGet call, in no particular order.
+// IDs that don't exist are left out of the result.
+func (s *FirebaseService) batchGet(collection string, ids []string) ([]map[string]interface{}, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
 
-	// For simplicity, we'll get all tasks and filter client-side
-	// In a real implementation, you'd use Firestore queries
-	resp, err := s.makeRequest("GET", "/tasks", nil)
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		names = append(names, s.documentName(collection, id))
+	}
+
+	resp, err := s.makeRequest("POST", ":batchGet", map[string]interface{}{"documents": names})
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return []*models.Task{}, nil // Return empty array instead of error
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to batch get %s: %s", collection, body)
 	}
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return []*models.Task{}, nil
-	}
-
-	var tasks []*models.Task
-	if documents, ok := result["documents"].([]interface{}); ok {
-		for _, docInterface := range documents {
-			if doc, ok := docInterface.(map[string]interface{}); ok {
-				var task models.Task
-				if err := s.fromFirestoreDoc(doc, &task); err == nil {
-					// Filter by user ID
-					if task.UserID == userID {
-						// Extract document ID
-						if name, ok := doc["name"].(string); ok {
-							parts := strings.Split(name, "/")
-							if len(parts) > 0 {
-								task.ID = parts[len(parts)-1]
-							}
-						}
-						tasks = append(tasks, &task)
+	var results []map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, err
+	}
+
+	var docs []map[string]interface{}
+	for _, result := range results {
+		if doc, ok := result["found"].(map[string]interface{}); ok {
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+
+// Build a query over a collection restricted to documents owned by userID
+func (s *FirebaseService) userQuery(collection, userID string) map[string]interface{} {
+	return map[string]interface{}{
+		"from":  []interface{}{map[string]interface{}{"collectionId": collection}},
+		"where": s.fieldFilter("userId", "EQUAL", userID),
+	}
+}
+
+// Count the documents matching a structured query using an aggregation,
+// without transferring the documents themselves
+func (s *FirebaseService) countQuery(query map[string]interface{}) (int, error) {
+	body := map[string]interface{}{
+		"structuredAggregationQuery": map[string]interface{}{
+			"structuredQuery": query,
+			"aggregations": []interface{}{
+				map[string]interface{}{"alias": "count", "count": map[string]interface{}{}},
+			},
+		},
+	}
+
+	resp, err := s.makeRequest("POST", ":runAggregationQuery", body)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("failed to run aggregation query: %s", respBody)
+	}
+
+	var results []map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return 0, err
+	}
+
+	for _, result := range results {
+		if aggregate, ok := result["result"].(map[string]interface{}); ok {
+			if fields, ok := aggregate["aggregateFields"].(map[string]interface{}); ok {
+				if count, ok := s.getIntegerValue(fields, "count"); ok {
+					return count, nil
+				}
+			}
+		}
+	}
+	return 0, nil
+}
+
+// Build a query over every document in a collection
+func (s *FirebaseService) collectionQuery(collection string) map[string]interface{} {
+	return map[string]interface{}{
+		"from": []interface{}{map[string]interface{}{"collectionId": collection}},
+	}
+}
+
+// Combine several filters so that all of them must match
+func (s *FirebaseService) andFilter(filters ...map[string]interface{}) map[string]interface{} {
+	list := make([]interface{}, 0, len(filters))
+	for _, filter := range filters {
+		list = append(list, filter)
+	}
+	return map[string]interface{}{
+		"compositeFilter": map[string]interface{}{
+			"op":      "AND",
+			"filters": list,
+		},
+	}
+}
+
+// Build a single-field filter for structured queries
+func (s *FirebaseService) fieldFilter(field, op string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"fieldFilter": map[string]interface{}{
+			"field": map[string]interface{}{"fieldPath": field},
+			"op":    op,
+			"value": s.toFirestoreValue(value),
+		},
+	}
+}
+
+// maxBatchWrites is Firestore's limit on writes per commit
+const maxBatchWrites = 500
+
+// Apply writes, splitting into several commits when over the limit. Each
+// commit is atomic but the whole call is not: an error can follow earlier
+// chunks that were already applied.
+func (s *FirebaseService) commit(writes []interface{}) error {
+	for start := 0; start < len(writes); start += maxBatchWrites {
+		end := start + maxBatchWrites
+		if end > len(writes) {
+			end = len(writes)
+		}
+
+		resp, err := s.makeRequest("POST", ":commit", map[string]interface{}{"writes": writes[start:end]})
+		if err != nil {
+			return err
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode >= 400 {
+			return fmt.Errorf("failed to commit batch: %s", body)
+		}
+	}
+	return nil
+}
+
+// NewDocumentID generates a random ID in the same alphabet Firestore uses
+func NewDocumentID() (string, error) {
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	random := make([]byte, 20)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	for i, b := range random {
+		random[i] = alphabet[int(b)%len(alphabet)]
+	}
+	return string(random), nil
+}
+
+// Full resource name of a document, as required by batch writes
+func (s *FirebaseService) documentName(collection, docID string) string {
+	return fmt.Sprintf("projects/%s/databases/(default)/documents/%s/%s", s.projectID, collection, docID)
+}
+
+// Helper functions to extract values from Firestore fields
+func (s *FirebaseService) getStringValue(fields map[string]interface{}, key string) (string, bool) {
+	if field, ok := fields[key].(map[string]interface{}); ok {
+		if value, ok := field["stringValue"].(string); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+func (s *FirebaseService) getBooleanValue(fields map[string]interface{}, key string) (bool, bool) {
+	if field, ok := fields[key].(map[string]interface{}); ok {
+		if value, ok := field["booleanValue"].(bool); ok {
+			return value, true
+		}
+	}
+	return false, false
+}
+
+func (s *FirebaseService) getIntegerValue(fields map[string]interface{}, key string) (int, bool) {
+	if field, ok := fields[key].(map[string]interface{}); ok {
+		// The REST API encodes 64-bit integers as strings
+		if value, ok := field["integerValue"].(string); ok {
+			if n, err := strconv.Atoi(value); err == nil {
+				return n, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// getNumberValue reads a double, or an integer as written before the field
+// allowed fractions
+func (s *FirebaseService) getNumberValue(fields map[string]interface{}, key string) (float64, bool) {
+	if field, ok := fields[key].(map[string]interface{}); ok {
+		if value, ok := field["doubleValue"].(float64); ok {
+			return value, true
+		}
+	}
+	if n, ok := s.getIntegerValue(fields, key); ok {
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func (s *FirebaseService) getStringArrayValue(fields map[string]interface{}, key string) ([]string, bool) {
+	if field, ok := fields[key].(map[string]interface{}); ok {
+		if array, ok := field["arrayValue"].(map[string]interface{}); ok {
+			var values []string
+			if items, ok := array["values"].([]interface{}); ok {
+				for _, item := range items {
+					if value, ok := item.(map[string]interface{})["stringValue"].(string); ok {
+						values = append(values, value)
 					}
 				}
 			}
+			return values, true
 		}
 	}
+	return nil, false
+}
 
-	log.Printf("✅ Found %d tasks for user %s", len(tasks), userID)
-	return tasks, nil
+func (s *FirebaseService) getStringMapValue(fields map[string]interface{}, key string) (map[string]string, bool) {
+	field, ok := fields[key].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	mapValue, ok := field["mapValue"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	entries, _ := mapValue["fields"].(map[string]interface{})
+
+	values := make(map[string]string, len(entries))
+	for name := range entries {
+		if value, ok := s.getStringValue(entries, name); ok {
+			values[name] = value
+		}
+	}
+	return values, true
 }
 
-func (s *FirebaseService) UpdateTask(taskID string, updates map[string]interface{}) error {
-	// Create update document
-	doc := map[string]interface{}{
-		"fields": make(map[string]interface{}),
+func (s *FirebaseService) getActionItemsValue(fields map[string]interface{}, key string) ([]models.ActionItem, bool) {
+	if field, ok := fields[key].(map[string]interface{}); ok {
+		if array, ok := field["arrayValue"].(map[string]interface{}); ok {
+			var values []models.ActionItem
+			if items, ok := array["values"].([]interface{}); ok {
+				for _, item := range items {
+					mapValue, ok := item.(map[string]interface{})["mapValue"].(map[string]interface{})
+					if !ok {
+						continue
+					}
+					itemFields, _ := mapValue["fields"].(map[string]interface{})
+					var actionItem models.ActionItem
+					actionItem.ID, _ = s.getStringValue(itemFields, "id")
+					actionItem.Text, _ = s.getStringValue(itemFields, "text")
+					actionItem.Done, _ = s.getBooleanValue(itemFields, "done")
+					if assignee, ok := s.getStringValue(itemFields, "assignee"); ok {
+						actionItem.Assignee = &assignee
+					}
+					if taskID, ok := s.getStringValue(itemFields, "taskId"); ok {
+						actionItem.TaskID = &taskID
+					}
+					values = append(values, actionItem)
+				}
+			}
+			return values, true
+		}
 	}
-	fields := doc["fields"].(map[string]interface{})
+	return nil, false
+}
 
-	updates["updatedAt"] = time.Now()
+func (s *FirebaseService) getEventRemindersValue(fields map[string]interface{}, key string) ([]models.EventReminder, bool) {
+	if field, ok := fields[key].(map[string]interface{}); ok {
+		if array, ok := field["arrayValue"].(map[string]interface{}); ok {
+			var values []models.EventReminder
+			if items, ok := array["values"].([]interface{}); ok {
+				for _, item := range items {
+					mapValue, ok := item.(map[string]interface{})["mapValue"].(map[string]interface{})
+					if !ok {
+						continue
+					}
+					itemFields, _ := mapValue["fields"].(map[string]interface{})
+					var reminder models.EventReminder
+					reminder.Method, _ = s.getStringValue(itemFields, "method")
+					reminder.Minutes, _ = s.getIntegerValue(itemFields, "minutes")
+					values = append(values, reminder)
+				}
+			}
+			return values, true
+		}
+	}
+	return nil, false
+}
 
-	for key, value := range updates {
-		switch v := value.(type) {
-		case string:
-			fields[key] = map[string]interface{}{"stringValue": v}
-		case time.Time:
-			fields[key] = map[string]interface{}{"timestampValue": v.Format(time.RFC3339)}
-		case bool:
-			fields[key] = map[string]interface{}{"booleanValue": v}
+func (s *FirebaseService) getRecurrenceValue(fields map[string]interface{}, key string) (*recurrence.Rule, bool) {
+	field, ok := fields[key].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	mapValue, ok := field["mapValue"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	ruleFields, _ := mapValue["fields"].(map[string]interface{})
+
+	var rule recurrence.Rule
+	frequency, _ := s.getStringValue(ruleFields, "frequency")
+	rule.Frequency = recurrence.Frequency(frequency)
+	rule.Interval, _ = s.getIntegerValue(ruleFields, "interval")
+	rule.Count, _ = s.getIntegerValue(ruleFields, "count")
+	if until, ok := s.getTimestampValue(ruleFields, "until"); ok {
+		rule.Until = &until
+	}
+	return &rule, true
+}
+
+func (s *FirebaseService) getTimestampValue(fields map[string]interface{}, key string) (time.Time, bool) {
+	if field, ok := fields[key].(map[string]interface{}); ok {
+		if value, ok := field["timestampValue"].(string); ok {
+			if t, err := time.Parse(time.RFC3339, value); err == nil {
+				return t, true
+			}
 		}
 	}
+	return time.Time{}, false
+}
 
-	resp, err := s.makeRequest("PATCH", "/tasks/"+taskID, doc)
+// User operations
+func (s *FirebaseService) CreateUser(user *models.UserSession) error {
+	// Key the document by user ID so GetUser/UpdateUser can find it again
+	doc := s.toFirestoreDoc(user)
+	resp, err := s.makeRequest("POST", "/users?documentId="+url.QueryEscape(user.UserID), doc)
 	if err != nil {
 		return err
 	}
@@ -400,56 +1134,637 @@ func (s *FirebaseService) UpdateTask(taskID string, updates map[string]interface
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to update task: %s", body)
+		return fmt.Errorf("failed to create user: %s", body)
 	}
 
+	log.Printf("✅ User created: %s", user.Email)
+	s.notifyUserChanged(user.UserID)
 	return nil
 }
 
-func (s *FirebaseService) DeleteTask(taskID string) error {
-	resp, err := s.makeRequest("DELETE", "/tasks/"+taskID, nil)
+func (s *FirebaseService) GetUser(userID string) (*models.UserSession, error) {
+	resp, err := s.makeRequest("GET", "/users/"+userID, nil)
 	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("failed to get user")
+	}
+
+	var doc map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	var user models.UserSession
+	if err := s.fromFirestoreDoc(doc, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (s *FirebaseService) UpdateUser(userID string, updates map[string]interface{}) error {
+	if err := s.updateDocument("users", userID, updates); err != nil {
 		return err
 	}
+	s.notifyUserChanged(userID)
+	return nil
+}
+
+// OnUserChange registers fn to run after a user document is written, so
+// caches keyed by user ID can drop stale entries. Register before serving.
+func (s *FirebaseService) OnUserChange(fn func(userID string)) {
+	s.userChanged = append(s.userChanged, fn)
+}
+
+func (s *FirebaseService) notifyUserChanged(userID string) {
+	for _, fn := range s.userChanged {
+		fn(userID)
+	}
+}
+
+// Task operations
+func (s *FirebaseService) CreateTask(task *models.Task) (string, error) {
+	task.CreatedAt = s.clock.Now()
+	task.UpdatedAt = s.clock.Now()
+
+	doc := s.toFirestoreDoc(task)
+	resp, err := s.makeRequest("POST", "/tasks", doc)
+	if err != nil {
+		return "", err
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to delete task: %s", body)
+		return "", fmt.Errorf("failed to create task: %s", body)
 	}
 
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+
+	// Extract document ID from the response
+	if name, ok := result["name"].(string); ok {
+		parts := strings.Split(name, "/")
+		if len(parts) > 0 {
+			docID := parts[len(parts)-1]
+			log.Printf("✅ Task created: %s (ID: %s)", task.Title, docID)
+			return docID, nil
+		}
+	}
+
+	return "", fmt.Errorf("failed to extract document ID")
+}
+
+func (s *FirebaseService) GetTasks(userID string) ([]*models.Task, error) {
+	log.Printf("🔍 Fetching tasks for user: %s", userID)
+
+	docs, err := s.runQuery(s.userQuery("tasks", userID))
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := s.tasksFromDocs(docs)
+	log.Printf("✅ Found %d tasks for user %s", len(tasks), userID)
+	return tasks, nil
+}
+
+// GetTasksByIDs fetches several tasks in one round-trip. Missing IDs are
+// skipped, so the result may be shorter than ids.
+func (s *FirebaseService) GetTasksByIDs(ids []string) ([]*models.Task, error) {
+	docs, err := s.batchGet("tasks", ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tasksFromDocs(docs), nil
+}
+
+func (s *FirebaseService) GetTask(taskID string) (*models.Task, error) {
+	doc, err := s.getDocument("tasks", taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	var task models.Task
+	if err := s.fromFirestoreDoc(doc, &task); err != nil {
+		return nil, err
+	}
+	task.ID = taskID
+
+	return &task, nil
+}
+
+// GetTasksDueBetween returns the user's tasks with a due date in [from, to],
+// ordered by due date. Tasks without a due date never match.
+func (s *FirebaseService) GetTasksDueBetween(userID string, from, to time.Time) ([]*models.Task, error) {
+	query := s.userQuery("tasks", userID)
+	query["where"] = s.andFilter(
+		s.fieldFilter("userId", "EQUAL", userID),
+		s.fieldFilter("dueDate", "GREATER_THAN_OR_EQUAL", from),
+		s.fieldFilter("dueDate", "LESS_THAN_OR_EQUAL", to),
+	)
+	query["orderBy"] = []interface{}{
+		map[string]interface{}{"field": map[string]interface{}{"fieldPath": "dueDate"}, "direction": "ASCENDING"},
+	}
+
+	docs, err := s.runQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tasksFromDocs(docs), nil
+}
+
+// GetTasksCompletedBetween returns the user's tasks completed in [from, to],
+// ordered by completion time. Tasks that were never completed have no
+// completedAt and never match. Served by the userId+completedAt index in
+// firestore.indexes.json. A positive limit returns one page, continuing after
+// the cursor when one is given.
+func (s *FirebaseService) GetTasksCompletedBetween(userID string, from, to time.Time, limit int, after *PageCursor) ([]*models.Task, error) {
+	query := s.userQuery("tasks", userID)
+	query["where"] = s.andFilter(
+		s.fieldFilter("userId", "EQUAL", userID),
+		s.fieldFilter("completedAt", "GREATER_THAN_OR_EQUAL", from),
+		s.fieldFilter("completedAt", "LESS_THAN_OR_EQUAL", to),
+	)
+	query["orderBy"] = []interface{}{
+		map[string]interface{}{"field": map[string]interface{}{"fieldPath": "completedAt"}, "direction": "ASCENDING"},
+		map[string]interface{}{"field": map[string]interface{}{"fieldPath": "__name__"}, "direction": "ASCENDING"},
+	}
+	if after != nil {
+		query["startAt"] = map[string]interface{}{
+			"values": []interface{}{
+				map[string]interface{}{"timestampValue": after.Value.Format(time.RFC3339Nano)},
+				map[string]interface{}{"referenceValue": s.documentName("tasks", after.ID)},
+			},
+			"before": false,
+		}
+	}
+	if limit > 0 {
+		query["limit"] = limit
+	}
+
+	docs, err := s.runQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tasksFromDocs(docs), nil
+}
+
+// GetChangesSince returns the user's tasks, meetings and reminders updated at
+// or after since, and the tombstones of items deleted in that time. Documents
+// written before updatedAt was tracked on meetings and reminders only show up
+// in a full sync.
+func (s *FirebaseService) GetChangesSince(userID string, since time.Time) (*models.SyncChanges, error) {
+	changed := func(collection, field string) ([]map[string]interface{}, error) {
+		query := s.userQuery(collection, userID)
+		query["where"] = s.andFilter(
+			s.fieldFilter("userId", "EQUAL", userID),
+			s.fieldFilter(field, "GREATER_THAN_OR_EQUAL", since),
+		)
+		return s.runQuery(query)
+	}
+
+	changes := &models.SyncChanges{Deleted: []*models.Tombstone{}}
+
+	docs, err := changed("tasks", "updatedAt")
+	if err != nil {
+		return nil, err
+	}
+	changes.Tasks = s.tasksFromDocs(docs)
+
+	if docs, err = changed("meetings", "updatedAt"); err != nil {
+		return nil, err
+	}
+	changes.Meetings = s.meetingsFromDocs(docs)
+
+	if docs, err = changed("reminders", "updatedAt"); err != nil {
+		return nil, err
+	}
+	changes.Reminders = s.remindersFromDocs(docs)
+
+	if docs, err = changed("tombstones", "deletedAt"); err != nil {
+		return nil, err
+	}
+	for _, doc := range docs {
+		var tombstone models.Tombstone
+		if err := s.fromFirestoreDoc(doc, &tombstone); err == nil {
+			changes.Deleted = append(changes.Deleted, &tombstone)
+		}
+	}
+
+	return changes, nil
+}
+
+// Decode query results into tasks, skipping malformed documents
+func (s *FirebaseService) tasksFromDocs(docs []map[string]interface{}) []*models.Task {
+	tasks := []*models.Task{}
+	for _, doc := range docs {
+		var task models.Task
+		if err := s.fromFirestoreDoc(doc, &task); err == nil {
+			task.ID = s.docID(doc)
+			tasks = append(tasks, &task)
+		}
+	}
+	return tasks
+}
+
+func (s *FirebaseService) UpdateTask(taskID string, updates map[string]interface{}) error {
+	updates["updatedAt"] = s.clock.Now()
+
+	return s.updateDocument("tasks", taskID, updates)
+}
+
+// DeleteTask removes a task and, in the same commit, leaves a tombstone so
+// syncing clients learn about the delete
+func (s *FirebaseService) DeleteTask(task *models.Task) error {
+	if err := s.commit(s.taskDeleteWrites(task)); err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+
+	return nil
+}
+
+// BatchDeleteTasks deletes several tasks, with their tombstones, in as few
+// commits as possible. A task and its tombstone always share a commit.
+func (s *FirebaseService) BatchDeleteTasks(tasks []*models.Task) error {
+	var writes []interface{}
+	for _, task := range tasks {
+		writes = append(writes, s.taskDeleteWrites(task)...)
+	}
+	if err := s.commit(writes); err != nil {
+		return fmt.Errorf("failed to delete tasks: %w", err)
+	}
+
+	log.Printf("🗑️ Batch deleted %d tasks", len(tasks))
+	return nil
+}
+
+func (s *FirebaseService) taskDeleteWrites(task *models.Task) []interface{} {
+	return s.deleteWrites("tasks", "task", task.UserID, task.ID)
+}
+
+// BatchDeleteReminders deletes several reminders, with their tombstones, in as
+// few commits as possible
+func (s *FirebaseService) BatchDeleteReminders(reminders []*models.Reminder) error {
+	var writes []interface{}
+	for _, reminder := range reminders {
+		writes = append(writes, s.deleteWrites("reminders", "reminder", reminder.UserID, reminder.ID)...)
+	}
+	if err := s.commit(writes); err != nil {
+		return fmt.Errorf("failed to delete reminders: %w", err)
+	}
+
+	log.Printf("🗑️ Batch deleted %d reminders", len(reminders))
+	return nil
+}
+
+// The delete and tombstone writes for one item; always an even pair so
+// commit's chunking never separates them
+func (s *FirebaseService) deleteWrites(collection, itemType, userID, itemID string) []interface{} {
+	tombstone := s.toFirestoreDoc(&models.Tombstone{
+		UserID:    userID,
+		Type:      itemType,
+		ItemID:    itemID,
+		DeletedAt: s.clock.Now(),
+	})
+	tombstone["name"] = s.documentName("tombstones", itemType+"-"+itemID)
+
+	return []interface{}{
+		map[string]interface{}{"delete": s.documentName(collection, itemID)},
+		map[string]interface{}{"update": tombstone},
+	}
+}
+
+// BatchUpdateTasks applies updates to several tasks. Only the fields present in
+// each update map are written. Up to maxBatchWrites tasks go in one atomic
+// commit; a larger batch is split, so an error can leave earlier chunks applied.
+func (s *FirebaseService) BatchUpdateTasks(updates map[string]map[string]interface{}) error {
+	for _, fields := range updates {
+		fields["updatedAt"] = s.clock.Now()
+	}
+	return s.batchUpdate("tasks", updates)
+}
+
+// BatchUpdateMeetings applies updates to several meetings, atomically per
+// maxBatchWrites meetings as for BatchUpdateTasks
+func (s *FirebaseService) BatchUpdateMeetings(updates map[string]map[string]interface{}) error {
+	for _, fields := range updates {
+		fields["updatedAt"] = s.clock.Now()
+	}
+	return s.batchUpdate("meetings", updates)
+}
+
+// BatchUpdateReminders applies updates to several reminders, atomically per
+// maxBatchWrites reminders as for BatchUpdateTasks
+func (s *FirebaseService) BatchUpdateReminders(updates map[string]map[string]interface{}) error {
+	for _, fields := range updates {
+		fields["updatedAt"] = s.clock.Now()
+	}
+	return s.batchUpdate("reminders", updates)
+}
+
+func (s *FirebaseService) batchUpdate(collection string, updates map[string]map[string]interface{}) error {
+	var writes []interface{}
+	for id, fields := range updates {
+		fieldPaths := make([]string, 0, len(fields))
+		for key := range fields {
+			fieldPaths = append(fieldPaths, key)
+		}
+
+		writes = append(writes, map[string]interface{}{
+			"update": map[string]interface{}{
+				"name":   s.documentName(collection, id),
+				"fields": s.toFirestoreFields(fields),
+			},
+			"updateMask": map[string]interface{}{"fieldPaths": fieldPaths},
+		})
+	}
+
+	if err := s.commit(writes); err != nil {
+		return err
+	}
+
+	log.Printf("✅ Batch updated %d %s", len(writes), collection)
 	return nil
 }
 
-// Simplified implementations for meetings and reminders
+// BatchCreate writes new task, meeting or reminder documents to a collection
+// using generated IDs, returned in the same order as items
+func (s *FirebaseService) BatchCreate(collection string, items []interface{}) ([]string, error) {
+	ids := make([]string, 0, len(items))
+	var writes []interface{}
+	for _, item := range items {
+		docID, err := NewDocumentID()
+		if err != nil {
+			return nil, err
+		}
+
+		doc := s.toFirestoreDoc(item)
+		doc["name"] = s.documentName(collection, docID)
+		writes = append(writes, map[string]interface{}{
+			"update":          doc,
+			"currentDocument": map[string]interface{}{"exists": false},
+		})
+		ids = append(ids, docID)
+	}
+
+	if err := s.commit(writes); err != nil {
+		return nil, err
+	}
+
+	log.Printf("✅ Batch created %d documents in %s", len(ids), collection)
+	return ids, nil
+}
+
+// Meeting operations
 func (s *FirebaseService) CreateMeeting(meeting *models.Meeting) (string, error) {
-	log.Printf("📅 Meeting creation not fully implemented yet")
-	return "meeting-id", nil
+	meeting.CreatedAt = s.clock.Now()
+	meeting.UpdatedAt = s.clock.Now()
+
+	meetingID, err := s.createDocument("meetings", s.toFirestoreDoc(meeting))
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("📅 Meeting created: %s (ID: %s)", meeting.Title, meetingID)
+	return meetingID, nil
 }
 
 func (s *FirebaseService) GetMeetings(userID string) ([]*models.Meeting, error) {
 	log.Printf("📅 Getting meetings for user: %s", userID)
-	return []*models.Meeting{}, nil
+
+	docs, err := s.runQuery(s.userQuery("meetings", userID))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.meetingsFromDocs(docs), nil
+}
+
+// GetMeetingsWithAttendee returns the user's meetings whose attendee list
+// contains the given email, compared exactly as stored
+func (s *FirebaseService) GetMeetingsWithAttendee(userID, attendee string) ([]*models.Meeting, error) {
+	query := s.userQuery("meetings", userID)
+	query["where"] = s.andFilter(
+		s.fieldFilter("userId", "EQUAL", userID),
+		s.fieldFilter("attendees", "ARRAY_CONTAINS", attendee),
+	)
+
+	docs, err := s.runQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.meetingsFromDocs(docs), nil
+}
+
+// Decode query results into meetings, skipping malformed documents
+func (s *FirebaseService) meetingsFromDocs(docs []map[string]interface{}) []*models.Meeting {
+	meetings := []*models.Meeting{}
+	for _, doc := range docs {
+		var meeting models.Meeting
+		if err := s.fromFirestoreDoc(doc, &meeting); err == nil {
+			meeting.ID = s.docID(doc)
+			meetings = append(meetings, &meeting)
+		}
+	}
+	return meetings
+}
+
+func (s *FirebaseService) GetMeeting(meetingID string) (*models.Meeting, error) {
+	doc, err := s.getDocument("meetings", meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	var meeting models.Meeting
+	if err := s.fromFirestoreDoc(doc, &meeting); err != nil {
+		return nil, err
+	}
+	meeting.ID = meetingID
+
+	return &meeting, nil
 }
 
 func (s *FirebaseService) UpdateMeeting(meetingID string, updates map[string]interface{}) error {
-	log.Printf("📅 Meeting update not fully implemented yet")
-	return nil
+	updates["updatedAt"] = s.clock.Now()
+
+	return s.updateDocument("meetings", meetingID, updates)
 }
 
+// Reminder operations
 func (s *FirebaseService) CreateReminder(reminder *models.Reminder) (string, error) {
-	log.Printf("⏰ Reminder creation not fully implemented yet")
-	return "reminder-id", nil
+	reminder.CreatedAt = s.clock.Now()
+	reminder.UpdatedAt = s.clock.Now()
+
+	reminderID, err := s.createDocument("reminders", s.toFirestoreDoc(reminder))
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("⏰ Reminder created: %s (ID: %s)", reminder.Title, reminderID)
+	return reminderID, nil
 }
 
 func (s *FirebaseService) GetReminders(userID string) ([]*models.Reminder, error) {
 	log.Printf("⏰ Getting reminders for user: %s", userID)
-	return []*models.Reminder{}, nil
+
+	docs, err := s.runQuery(s.userQuery("reminders", userID))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.remindersFromDocs(docs), nil
+}
+
+// GetRemindersByIDs fetches several reminders in one round-trip. Missing IDs
+// are skipped, so the result may be shorter than ids.
+func (s *FirebaseService) GetRemindersByIDs(ids []string) ([]*models.Reminder, error) {
+	docs, err := s.batchGet("reminders", ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.remindersFromDocs(docs), nil
+}
+
+// Decode query results into reminders, skipping malformed documents
+func (s *FirebaseService) remindersFromDocs(docs []map[string]interface{}) []*models.Reminder {
+	reminders := []*models.Reminder{}
+	for _, doc := range docs {
+		var reminder models.Reminder
+		if err := s.fromFirestoreDoc(doc, &reminder); err == nil {
+			reminder.ID = s.docID(doc)
+			reminders = append(reminders, &reminder)
+		}
+	}
+	return reminders
+}
+
+func (s *FirebaseService) GetReminder(reminderID string) (*models.Reminder, error) {
+	doc, err := s.getDocument("reminders", reminderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var reminder models.Reminder
+	if err := s.fromFirestoreDoc(doc, &reminder); err != nil {
+		return nil, err
+	}
+	reminder.ID = reminderID
+
+	return &reminder, nil
 }
 
 func (s *FirebaseService) UpdateReminder(reminderID string, updates map[string]interface{}) error {
-	log.Printf("⏰ Reminder update not fully implemented yet")
-	return nil
+	updates["updatedAt"] = s.clock.Now()
+
+	return s.updateDocument("reminders", reminderID, updates)
+}
+
+// GetUsageStats returns aggregate document counts for operators
+func (s *FirebaseService) GetUsageStats() (*models.UsageStats, error) {
+	var stats models.UsageStats
+
+	counts := map[string]*int{
+		"users":     &stats.Users,
+		"tasks":     &stats.Tasks,
+		"meetings":  &stats.Meetings,
+		"reminders": &stats.Reminders,
+	}
+	for collection, target := range counts {
+		count, err := s.countQuery(s.collectionQuery(collection))
+		if err != nil {
+			return nil, err
+		}
+		*target = count
+	}
+
+	activeQuery := s.collectionQuery("users")
+	activeQuery["where"] = s.fieldFilter("lastLogin", "GREATER_THAN_OR_EQUAL", s.clock.Now().AddDate(0, 0, -7))
+	activeUsers, err := s.countQuery(activeQuery)
+	if err != nil {
+		return nil, err
+	}
+	stats.ActiveUsers7d = activeUsers
+
+	return &stats, nil
+}
+
+// GetBadgeCounts counts a user's open items with aggregation queries only.
+// Meetings are counted when they start within [dayStart, dayEnd); open tasks
+// due before tasksDueBefore and pending reminders due before
+// remindersDueBefore are overdue.
+func (s *FirebaseService) GetBadgeCounts(userID string, dayStart, dayEnd, tasksDueBefore, remindersDueBefore time.Time) (*models.BadgeCounts, error) {
+	var counts models.BadgeCounts
+	openStatuses := []string{"todo", "in-progress"}
+	var overdueTasks, overdueReminders int
+
+	queries := []struct {
+		collection string
+		filters    []map[string]interface{}
+		target     *int
+	}{
+		{"tasks", []map[string]interface{}{
+			s.fieldFilter("status", "IN", openStatuses),
+		}, &counts.OpenTasks},
+		{"meetings", []map[string]interface{}{
+			s.fieldFilter("startTime", "GREATER_THAN_OR_EQUAL", dayStart),
+			s.fieldFilter("startTime", "LESS_THAN", dayEnd),
+		}, &counts.MeetingsToday},
+		{"reminders", []map[string]interface{}{
+			s.fieldFilter("isCompleted", "EQUAL", false),
+		}, &counts.PendingReminders},
+		{"tasks", []map[string]interface{}{
+			s.fieldFilter("status", "IN", openStatuses),
+			s.fieldFilter("dueDate", "LESS_THAN", tasksDueBefore),
+		}, &overdueTasks},
+		{"reminders", []map[string]interface{}{
+			s.fieldFilter("isCompleted", "EQUAL", false),
+			s.fieldFilter("reminderTime", "LESS_THAN", remindersDueBefore),
+		}, &overdueReminders},
+	}
+	for _, q := range queries {
+		query := s.userQuery(q.collection, userID)
+		query["where"] = s.andFilter(append([]map[string]interface{}{s.fieldFilter("userId", "EQUAL", userID)}, q.filters...)...)
+
+		count, err := s.countQuery(query)
+		if err != nil {
+			return nil, err
+		}
+		*q.target = count
+	}
+
+	counts.Overdue = overdueTasks + overdueReminders
+	return &counts, nil
+}
+
+// GetCarryForwardUserIDs lists the users who opted in to reminder carry-forward
+func (s *FirebaseService) GetCarryForwardUserIDs() ([]string, error) {
+	query := s.collectionQuery("users")
+	query["where"] = s.fieldFilter("carryForwardReminders", "EQUAL", true)
+
+	docs, err := s.runQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		userIDs = append(userIDs, s.docID(doc))
+	}
+	return userIDs, nil
 }
 
 func (s *FirebaseService) GetAllTasks() ([]*models.Task, error) {
@@ -462,4 +1777,4 @@ func (s *FirebaseService) GetAllMeetings() ([]*models.Meeting, error) {
 
 func (s *FirebaseService) GetAllReminders() ([]*models.Reminder, error) {
 	return []*models.Reminder{}, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"focusflow-be/internal/clock"
+	"focusflow-be/internal/models"
+)
+
+// newTestFirebase points a FirebaseService at handler in place of Firestore,
+// sending each request once
+func newTestFirebase(t *testing.T, handler http.HandlerFunc) *FirebaseService {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &FirebaseService{
+		projectID: "test",
+		baseURL:   server.URL + "/v1/projects/test/databases/(default)/documents",
+		client:    server.Client(),
+		retry:     retryPolicy{maxAttempts: 1},
+		clock:     clock.NewFake(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)),
+	}
+}
+
+func TestGetTasks(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		wantTasks int
+		wantErr   bool
+	}{
+		{
+			name:      "tasks found",
+			status:    http.StatusOK,
+			body:      `[{"document": {"name": "projects/test/databases/(default)/documents/tasks/t1", "fields": {"userId": {"stringValue": "user-1"}, "title": {"stringValue": "Write report"}}}}]`,
+			wantTasks: 1,
+		},
+		{name: "no tasks", status: http.StatusOK, body: `[{"readTime": "2026-10-16T09:00:00Z"}]`},
+		{name: "server error", status: http.StatusInternalServerError, body: `{"error": "boom"}`, wantErr: true},
+		{name: "permission denied", status: http.StatusForbidden, body: `{"error": "denied"}`, wantErr: true},
+		{name: "malformed response", status: http.StatusOK, body: `{not json`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestFirebase(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			tasks, err := s.GetTasks("user-1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if len(tasks) != tt.wantTasks {
+				t.Errorf("got %d tasks, want %d", len(tasks), tt.wantTasks)
+			}
+		})
+	}
+}
+
+func TestWithContextTimesFirestoreCalls(t *testing.T) {
+	s := newTestFirebase(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * time.Millisecond)
+		w.Write([]byte(`[]`))
+	})
+
+	var calls int
+	var total time.Duration
+	ctx := WithFirestoreTimer(context.Background(), func(d time.Duration) {
+		calls++
+		total += d
+	})
+
+	if _, err := s.GetTasks("user-1"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 0 {
+		t.Fatalf("unbound store reported %d calls, want 0", calls)
+	}
+
+	if _, err := s.WithContext(ctx).GetTasks("user-1"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Fatalf("bound store reported %d calls, want 1", calls)
+	}
+	if total < 2*time.Millisecond {
+		t.Errorf("reported %s, want at least the 2ms the call took", total)
+	}
+}
+
+func TestUpdateUserWritesOnlyGivenFields(t *testing.T) {
+	var fieldPaths []string
+	s := newTestFirebase(t, func(w http.ResponseWriter, r *http.Request) {
+		fieldPaths = r.URL.Query()["updateMask.fieldPaths"]
+		w.Write([]byte(`{}`))
+	})
+
+	if err := s.UpdateUser("user-1", map[string]interface{}{"timezone": "Europe/Berlin"}); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	// Saving preferences is not a login
+	if len(fieldPaths) != 1 || fieldPaths[0] != "timezone" {
+		t.Errorf("updated fields %v, want [timezone]", fieldPaths)
+	}
+}
+
+func TestBatchUpdateSplitsCommits(t *testing.T) {
+	tests := []struct {
+		name        string
+		items       int
+		wantCommits []int
+	}{
+		{"one item", 1, []int{1}},
+		{"at the limit", maxBatchWrites, []int{maxBatchWrites}},
+		{"over the limit", maxBatchWrites + 1, []int{maxBatchWrites, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commits []int
+			s := newTestFirebase(t, func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Writes []json.RawMessage `json:"writes"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("decoding commit: %v", err)
+				}
+				commits = append(commits, len(body.Writes))
+				w.Write([]byte(`{}`))
+			})
+
+			updates := make(map[string]map[string]interface{}, tt.items)
+			for i := 0; i < tt.items; i++ {
+				updates[fmt.Sprintf("task-%d", i)] = map[string]interface{}{"status": "completed"}
+			}
+			if err := s.BatchUpdateTasks(updates); err != nil {
+				t.Fatalf("BatchUpdateTasks: %v", err)
+			}
+			if fmt.Sprint(commits) != fmt.Sprint(tt.wantCommits) {
+				t.Errorf("commit sizes = %v, want %v", commits, tt.wantCommits)
+			}
+		})
+	}
+}
+
+func TestUserWritesNotifyListeners(t *testing.T) {
+	s := newTestFirebase(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+	var changed []string
+	s.OnUserChange(func(userID string) { changed = append(changed, userID) })
+
+	if err := s.CreateUser(&models.UserSession{UserID: "user-1"}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if err := s.WithContext(context.Background()).UpdateUser("user-2", map[string]interface{}{"timezone": "UTC"}); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	if want := []string{"user-1", "user-2"}; fmt.Sprint(changed) != fmt.Sprint(want) {
+		t.Errorf("notified %v, want %v", changed, want)
+	}
+}
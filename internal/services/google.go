@@ -2,8 +2,13 @@ package services
 
 import (
 	"context"
+	"crypto/hmac"
 	"encoding/json"
+	"fmt"
 	"io"
+	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/oauth2"
@@ -18,6 +23,9 @@ import (
 type GoogleService struct {
 	config      *config.Config
 	oauthConfig *oauth2.Config
+
+	// Calendar API base URL; empty means Google's. Tests point it at a stub.
+	calendarEndpoint string
 }
 
 func NewGoogleService(cfg *config.Config) *GoogleService {
@@ -39,17 +47,119 @@ func NewGoogleService(cfg *config.Config) *GoogleService {
 	}
 }
 
-func (s *GoogleService) GetAuthURL() string {
-	return s.oauthConfig.AuthCodeURL("state", oauth2.AccessTypeOffline)
+// ResolveRedirectURI returns the requested redirect URI if it is on the
+// allowlist, or the default one when none was requested
+func (s *GoogleService) ResolveRedirectURI(requested string) (string, error) {
+	if requested == "" {
+		return s.config.GoogleRedirectURI, nil
+	}
+	for _, allowed := range s.config.GoogleRedirectURIs {
+		if requested == allowed {
+			return requested, nil
+		}
+	}
+	return "", fmt.Errorf("redirect URI %q is not allowed", requested)
+}
+
+// ResolveFrontendURL returns the requested front-end callback if it is on the
+// allowlist, or the configured default (which may be empty) when none was requested
+func (s *GoogleService) ResolveFrontendURL(requested string) (string, error) {
+	if requested == "" {
+		return s.config.FrontendCallbackURL, nil
+	}
+	for _, allowed := range s.config.FrontendCallbackURLs {
+		if requested == allowed {
+			return requested, nil
+		}
+	}
+	return "", fmt.Errorf("frontend URL %q is not allowed", requested)
 }
 
-func (s *GoogleService) ExchangeCodeForToken(code string) (*oauth2.Token, error) {
-	return s.oauthConfig.Exchange(context.Background(), code)
+// NewOAuthState starts a state for a login flow using the given redirect URI
+func (s *GoogleService) NewOAuthState(redirectURI string) (*OAuthState, error) {
+	return newOAuthState(redirectURI, s.config.Clock.Now())
+}
+
+// GetAuthURL builds the consent URL, carrying the flow's state in signed form
+func (s *GoogleService) GetAuthURL(state *OAuthState) (string, error) {
+	encoded, err := state.encode(s.stateKey())
+	if err != nil {
+		return "", err
+	}
+
+	opts := []oauth2.AuthCodeOption{oauth2.AccessTypeOffline, oauth2.SetAuthURLParam("redirect_uri", state.RedirectURI)}
+	if s.config.GoogleOAuthPKCE {
+		opts = append(opts, oauth2.S256ChallengeOption(state.verifier))
+	}
+	return s.oauthConfig.AuthCodeURL(encoded, opts...), nil
+}
+
+// ParseState verifies the state returned to the callback
+func (s *GoogleService) ParseState(state string) (*OAuthState, error) {
+	return decodeOAuthState(state, s.stateKey(), s.config.Clock.Now())
+}
+
+// The state is signed with the client secret, which only this server knows
+func (s *GoogleService) stateKey() []byte {
+	return []byte(s.config.GoogleClientSecret)
+}
+
+// VerifierCookie keeps the flow's PKCE verifier in the browser that started
+// it, signed together with the state nonce so it only completes this flow. A
+// code and state replayed from anywhere else arrive without it.
+func (s *GoogleService) VerifierCookie(state *OAuthState, secure bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     PKCECookie,
+		Value:    state.verifier + "." + s.signVerifier(state.Nonce, state.verifier),
+		Path:     "/",
+		MaxAge:   int(stateTTL.Seconds()),
+		Secure:   secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// RestoreVerifier takes the PKCE verifier back from the cookie set by
+// VerifierCookie, checking it belongs to state
+func (s *GoogleService) RestoreVerifier(state *OAuthState, cookie string) error {
+	verifier, signature, ok := strings.Cut(cookie, ".")
+	if !ok || verifier == "" || !hmac.Equal([]byte(signature), []byte(s.signVerifier(state.Nonce, verifier))) {
+		return ErrMissingVerifier
+	}
+	state.verifier = verifier
+	return nil
+}
+
+func (s *GoogleService) signVerifier(nonce, verifier string) string {
+	return signState("pkce."+nonce+"."+verifier, s.stateKey())
+}
+
+// ExchangeCodeForToken must use the same redirect URI, and PKCE verifier, the
+// flow started with
+func (s *GoogleService) ExchangeCodeForToken(code string, state *OAuthState) (*oauth2.Token, error) {
+	opts := []oauth2.AuthCodeOption{oauth2.SetAuthURLParam("redirect_uri", state.RedirectURI)}
+	if s.config.GoogleOAuthPKCE {
+		if state.verifier == "" {
+			return nil, ErrMissingVerifier
+		}
+		opts = append(opts, oauth2.VerifierOption(state.verifier))
+	}
+	return s.oauthConfig.Exchange(context.Background(), code, opts...)
+}
+
+// calendarService is a Calendar API client acting with the user's token
+func (s *GoogleService) calendarService(token *oauth2.Token) (*calendar.Service, error) {
+	ctx := context.Background()
+	opts := []option.ClientOption{option.WithHTTPClient(s.oauthConfig.Client(ctx, token))}
+	if s.calendarEndpoint != "" {
+		opts = append(opts, option.WithEndpoint(s.calendarEndpoint))
+	}
+	return calendar.NewService(ctx, opts...)
 }
 
 func (s *GoogleService) GetUserInfo(token *oauth2.Token) (*models.GoogleUserInfo, error) {
 	client := s.oauthConfig.Client(context.Background(), token)
-	
+
 	resp, err := client.Get("https://www.googleapis.com/oauth2/v2/userinfo")
 	if err != nil {
 		return nil, err
@@ -69,26 +179,102 @@ func (s *GoogleService) GetUserInfo(token *oauth2.Token) (*models.GoogleUserInfo
 	return &userInfo, nil
 }
 
+// UserToken rebuilds the OAuth token stored on a user so calendar calls can be
+// made on their behalf; the client refreshes it when it has expired
+func (s *GoogleService) UserToken(user *models.UserSession) *oauth2.Token {
+	token := &oauth2.Token{AccessToken: user.AccessToken}
+	if user.RefreshToken != nil {
+		token.RefreshToken = *user.RefreshToken
+	}
+	return token
+}
+
 func (s *GoogleService) CreateCalendarEvent(token *oauth2.Token, task *models.Task) (string, error) {
 	if task.DueDate == nil {
 		return "", nil
 	}
 
-	ctx := context.Background()
-	client := s.oauthConfig.Client(ctx, token)
-	
-	calendarService, err := calendar.NewService(ctx, option.WithHTTPClient(client))
+	calendarService, err := s.calendarService(token)
+	if err != nil {
+		return "", err
+	}
+
+	createdEvent, err := calendarService.Events.Insert("primary", s.taskEvent(task)).Do()
 	if err != nil {
 		return "", err
 	}
 
-	startTime := time.Now()
+	return createdEvent.Id, nil
+}
+
+// SyncTasks inserts a calendar event for each task. Tasks are processed
+// independently, so one failed insert doesn't stop the rest; at most
+// CalendarSyncConcurrency inserts run at once to stay under Google's rate
+// limits. It returns the new event ID per task ID along with the failures, in
+// input order. Tasks without a due date are skipped.
+func (s *GoogleService) SyncTasks(token *oauth2.Token, calendarID string, tasks []*models.Task) (map[string]string, []models.SyncFailure, error) {
+	calendarService, err := s.calendarService(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+
+	workers := s.config.CalendarSyncConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	eventIDs := make(map[string]string)
+	errs := make([]error, len(tasks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan int)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				createdEvent, err := calendarService.Events.Insert(calendarID, s.taskEvent(tasks[i])).Do()
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				mu.Lock()
+				eventIDs[tasks[i].ID] = createdEvent.Id
+				mu.Unlock()
+			}
+		}()
+	}
+	for i, task := range tasks {
+		if task.DueDate != nil {
+			jobs <- i
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	failed := []models.SyncFailure{}
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, models.SyncFailure{ID: tasks[i].ID, Error: err.Error()})
+		}
+	}
+	return eventIDs, failed, nil
+}
+
+// taskEvent describes a task as a calendar event spanning its start (or now)
+// to its due date
+func (s *GoogleService) taskEvent(task *models.Task) *calendar.Event {
+	startTime := s.config.Clock.Now()
 	if task.StartDate != nil {
 		startTime = *task.StartDate
 	}
 
-	event := &calendar.Event{
-		Summary:     task.Title,
+	return &calendar.Event{
+		Summary: task.Title,
 		Description: func() string {
 			if task.Description != nil {
 				return *task.Description
@@ -105,6 +291,8 @@ func (s *GoogleService) CreateCalendarEvent(token *oauth2.Token, task *models.Ta
 		},
 		ColorId: func() string {
 			switch task.Priority {
+			case "urgent":
+				return "4"
 			case "high":
 				return "11"
 			case "medium":
@@ -114,26 +302,16 @@ func (s *GoogleService) CreateCalendarEvent(token *oauth2.Token, task *models.Ta
 			}
 		}(),
 	}
-
-	createdEvent, err := calendarService.Events.Insert("primary", event).Do()
-	if err != nil {
-		return "", err
-	}
-
-	return createdEvent.Id, nil
 }
 
 func (s *GoogleService) CreateCalendarMeeting(token *oauth2.Token, meeting *models.Meeting) (string, error) {
-	ctx := context.Background()
-	client := s.oauthConfig.Client(ctx, token)
-	
-	calendarService, err := calendar.NewService(ctx, option.WithHTTPClient(client))
+	calendarService, err := s.calendarService(token)
 	if err != nil {
 		return "", err
 	}
 
 	event := &calendar.Event{
-		Summary:     meeting.Title,
+		Summary: meeting.Title,
 		Description: func() string {
 			if meeting.Description != nil {
 				return *meeting.Description
@@ -163,30 +341,112 @@ func (s *GoogleService) CreateCalendarMeeting(token *oauth2.Token, meeting *mode
 			}
 			return attendees
 		}(),
-		ColorId: "9",
+		Reminders: eventReminders(meeting.ReminderMinutes),
+		ColorId:   "9",
+	}
+	if meeting.Tentative {
+		event.Status = "tentative"
 	}
 
-	createdEvent, err := calendarService.Events.Insert("primary", event).Do()
+	createdEvent, err := calendarService.Events.Insert("primary", event).SendUpdates(SendUpdates(meeting.NotifyAttendees)).Do()
 	if err != nil {
 		return "", err
 	}
 
+	// Block travel time around the meeting as separate events
+	blockedStart, blockedEnd := meeting.BlockedWindow()
+	travel := []struct{ start, end time.Time }{
+		{blockedStart, meeting.StartTime},
+		{meeting.EndTime, blockedEnd},
+	}
+	for _, block := range travel {
+		if !block.end.After(block.start) {
+			continue
+		}
+		travelEvent := &calendar.Event{
+			Summary: "Travel: " + meeting.Title,
+			Start: &calendar.EventDateTime{
+				DateTime: block.start.Format(time.RFC3339),
+				TimeZone: "UTC",
+			},
+			End: &calendar.EventDateTime{
+				DateTime: block.end.Format(time.RFC3339),
+				TimeZone: "UTC",
+			},
+			ColorId:      "8",
+			Transparency: "opaque",
+		}
+		if _, err := calendarService.Events.Insert("primary", travelEvent).Do(); err != nil {
+			return createdEvent.Id, err
+		}
+	}
+
 	return createdEvent.Id, nil
 }
 
+// SendUpdates maps a meeting's notifyAttendees preference onto the calendar
+// API's sendUpdates parameter. Attendees are notified unless it's false.
+func SendUpdates(notify *bool) string {
+	if notify != nil && !*notify {
+		return "none"
+	}
+	return "all"
+}
+
+// MoveCalendarEvent changes the times of an existing event. sendUpdates is
+// passed to Google as is: all, externalOnly or none.
+func (s *GoogleService) MoveCalendarEvent(token *oauth2.Token, eventID string, start, end time.Time, sendUpdates string) error {
+	calendarService, err := s.calendarService(token)
+	if err != nil {
+		return err
+	}
+
+	patch := &calendar.Event{
+		Start: &calendar.EventDateTime{
+			DateTime: start.Format(time.RFC3339),
+			TimeZone: "UTC",
+		},
+		End: &calendar.EventDateTime{
+			DateTime: end.Format(time.RFC3339),
+			TimeZone: "UTC",
+		},
+	}
+	_, err = calendarService.Events.Patch("primary", eventID, patch).SendUpdates(sendUpdates).Do()
+	return err
+}
+
+// eventReminders turns a meeting's notification preferences into calendar
+// overrides, or leaves the calendar's defaults in place when there are none.
+func eventReminders(reminders []models.EventReminder) *calendar.EventReminders {
+	if len(reminders) == 0 {
+		return nil
+	}
+	overrides := make([]*calendar.EventReminder, 0, len(reminders))
+	for _, reminder := range reminders {
+		overrides = append(overrides, &calendar.EventReminder{
+			Method:  reminder.Method,
+			Minutes: int64(reminder.Minutes),
+			// Zero means "at the start" and must not be dropped as empty
+			ForceSendFields: []string{"Minutes"},
+		})
+	}
+	// UseDefault must be sent as false for the overrides to apply
+	return &calendar.EventReminders{Overrides: overrides, ForceSendFields: []string{"UseDefault"}}
+}
+
+// ReminderEventLength is how long the calendar event for a reminder lasts
+const ReminderEventLength = 15 * time.Minute
+
 func (s *GoogleService) CreateCalendarReminder(token *oauth2.Token, reminder *models.Reminder) (string, error) {
-	ctx := context.Background()
-	client := s.oauthConfig.Client(ctx, token)
-	
-	calendarService, err := calendar.NewService(ctx, option.WithHTTPClient(client))
+	calendarService, err := s.calendarService(token)
 	if err != nil {
 		return "", err
 	}
 
-	endTime := reminder.ReminderTime.Add(15 * time.Minute)
+	endTime := reminder.ReminderTime.Add(ReminderEventLength)
 
 	event := &calendar.Event{
-		Summary:     reminder.Title,
+		Summary: reminder.Title,
 		Description: func() string {
 			if reminder.Description != nil {
 				return *reminder.Description
@@ -223,4 +483,4 @@ func (s *GoogleService) CreateCalendarReminder(token *oauth2.Token, reminder *mo
 	}
 
 	return createdEvent.Id, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"focusflow-be/internal/clock"
+	"focusflow-be/internal/config"
+	"focusflow-be/internal/models"
+)
+
+// newTestGoogle returns a Google service whose token endpoint is handler
+func newTestGoogle(t *testing.T, pkce bool, handler http.HandlerFunc) *GoogleService {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	s := NewGoogleService(&config.Config{
+		GoogleClientID:     "client-id",
+		GoogleClientSecret: "client-secret",
+		GoogleRedirectURI:  "http://localhost:8080/auth/callback",
+		GoogleOAuthPKCE:    pkce,
+		Clock:              clock.NewFake(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)),
+	})
+	s.oauthConfig.Endpoint = oauth2.Endpoint{AuthURL: server.URL + "/auth", TokenURL: server.URL + "/token"}
+	return s
+}
+
+// tokenEndpoint answers every exchange with a token, recording the form sent
+func tokenEndpoint(form *url.Values) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		*form = r.PostForm
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token": "access", "token_type": "Bearer", "expires_in": 3600}`))
+	}
+}
+
+// startFlow runs /auth/google's half of the flow and returns the consent URL's
+// query, the state as the callback receives it and the PKCE cookie
+func startFlow(t *testing.T, s *GoogleService) (url.Values, *OAuthState, *http.Cookie) {
+	t.Helper()
+	state, err := s.NewOAuthState("http://localhost:8080/auth/callback")
+	if err != nil {
+		t.Fatal(err)
+	}
+	authURL, err := s.GetAuthURL(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cookie := s.VerifierCookie(state, true)
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := parsed.Query()
+	returned, err := s.ParseState(query.Get("state"))
+	if err != nil {
+		t.Fatalf("ParseState: %v", err)
+	}
+	return query, returned, cookie
+}
+
+func TestPKCE(t *testing.T) {
+	var form url.Values
+	s := newTestGoogle(t, true, tokenEndpoint(&form))
+	query, state, cookie := startFlow(t, s)
+
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		t.Fatalf("auth URL query %v has no S256 challenge", query)
+	}
+	if !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteLaxMode {
+		t.Errorf("cookie = %+v, want HttpOnly, Secure and SameSite=Lax", cookie)
+	}
+
+	if err := s.RestoreVerifier(state, cookie.Value); err != nil {
+		t.Fatalf("RestoreVerifier: %v", err)
+	}
+	if _, err := s.ExchangeCodeForToken("code-1", state); err != nil {
+		t.Fatalf("ExchangeCodeForToken: %v", err)
+	}
+
+	verifier := form.Get("code_verifier")
+	if verifier == "" {
+		t.Fatalf("exchange form %v has no code_verifier", form)
+	}
+	if got := oauth2.S256ChallengeFromVerifier(verifier); got != query.Get("code_challenge") {
+		t.Errorf("verifier %q hashes to %q, want the challenge %q", verifier, got, query.Get("code_challenge"))
+	}
+	if form.Get("code") != "code-1" {
+		t.Errorf("code = %q, want code-1", form.Get("code"))
+	}
+}
+
+func TestPKCERejectsOtherBrowsers(t *testing.T) {
+	var form url.Values
+	s := newTestGoogle(t, true, tokenEndpoint(&form))
+	_, state, _ := startFlow(t, s)
+	_, _, otherCookie := startFlow(t, s)
+
+	tests := []struct {
+		name   string
+		cookie string
+	}{
+		{"no cookie", ""},
+		{"cookie from another flow", otherCookie.Value},
+		{"forged cookie", "attacker-verifier.c2lnbmF0dXJl"},
+		{"unsigned verifier", "attacker-verifier"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.RestoreVerifier(state, tt.cookie); err != ErrMissingVerifier {
+				t.Errorf("RestoreVerifier err = %v, want ErrMissingVerifier", err)
+			}
+		})
+	}
+
+	// A state replayed without its cookie has no verifier to exchange with
+	if _, err := s.ExchangeCodeForToken("code-1", state); err != ErrMissingVerifier {
+		t.Errorf("ExchangeCodeForToken err = %v, want ErrMissingVerifier", err)
+	}
+	if form != nil {
+		t.Errorf("token endpoint called with %v", form)
+	}
+}
+
+func TestPKCEDisabled(t *testing.T) {
+	var form url.Values
+	s := newTestGoogle(t, false, tokenEndpoint(&form))
+	query, state, _ := startFlow(t, s)
+
+	if query.Has("code_challenge") {
+		t.Errorf("auth URL has a challenge with PKCE off: %v", query)
+	}
+	if _, err := s.ExchangeCodeForToken("code-1", state); err != nil {
+		t.Fatalf("ExchangeCodeForToken: %v", err)
+	}
+	if form.Has("code_verifier") {
+		t.Errorf("exchange sent a verifier with PKCE off: %v", form)
+	}
+}
+
+// calendarStub accepts event inserts, failing those whose summary starts with
+// "fail", and records the most inserts it saw in flight at once
+type calendarStub struct {
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	inserted    []string
+}
+
+func (cs *calendarStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cs.mu.Lock()
+	cs.inFlight++
+	cs.maxInFlight = max(cs.maxInFlight, cs.inFlight)
+	cs.mu.Unlock()
+	defer func() {
+		cs.mu.Lock()
+		cs.inFlight--
+		cs.mu.Unlock()
+	}()
+
+	var event struct {
+		Summary string `json:"summary"`
+	}
+	json.NewDecoder(r.Body).Decode(&event)
+	// Hold the request so concurrent inserts overlap
+	time.Sleep(10 * time.Millisecond)
+
+	if strings.HasPrefix(event.Summary, "fail") {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": {"code": 400, "message": "Invalid event"}}`))
+		return
+	}
+	cs.mu.Lock()
+	cs.inserted = append(cs.inserted, event.Summary)
+	cs.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"id": "event-" + event.Summary})
+}
+
+func TestSyncTasksPartialFailure(t *testing.T) {
+	stub := &calendarStub{}
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+
+	s := newTestGoogle(t, false, http.NotFound)
+	s.config.CalendarSyncConcurrency = 2
+	s.calendarEndpoint = server.URL + "/calendar/v3/"
+
+	due := time.Date(2026, 10, 20, 17, 0, 0, 0, time.UTC)
+	var tasks []*models.Task
+	for _, title := range []string{"one", "fail-two", "three", "four", "fail-five", "six"} {
+		tasks = append(tasks, &models.Task{ID: "task-" + title, Title: title, DueDate: &due, Priority: "medium"})
+	}
+	tasks = append(tasks, &models.Task{ID: "task-undated", Title: "undated"})
+
+	eventIDs, failed, err := s.SyncTasks(&oauth2.Token{AccessToken: "access"}, "", tasks)
+	if err != nil {
+		t.Fatalf("SyncTasks: %v", err)
+	}
+
+	wantIDs := map[string]string{
+		"task-one":   "event-one",
+		"task-three": "event-three",
+		"task-four":  "event-four",
+		"task-six":   "event-six",
+	}
+	if !reflect.DeepEqual(eventIDs, wantIDs) {
+		t.Errorf("event IDs = %v, want %v", eventIDs, wantIDs)
+	}
+	if len(failed) != 2 || failed[0].ID != "task-fail-two" || failed[1].ID != "task-fail-five" {
+		t.Fatalf("failed = %+v, want task-fail-two and task-fail-five in order", failed)
+	}
+	for _, f := range failed {
+		if !strings.Contains(f.Error, "Invalid event") {
+			t.Errorf("%s error = %q, want Google's message", f.ID, f.Error)
+		}
+	}
+	if stub.maxInFlight > 2 {
+		t.Errorf("%d inserts ran at once, want at most 2", stub.maxInFlight)
+	}
+}
+
+func TestMoveCalendarEvent(t *testing.T) {
+	var method, path, sendUpdates string
+	var patch struct {
+		Start struct {
+			DateTime string `json:"dateTime"`
+		} `json:"start"`
+		End struct {
+			DateTime string `json:"dateTime"`
+		} `json:"end"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, sendUpdates = r.Method, r.URL.Path, r.URL.Query().Get("sendUpdates")
+		json.NewDecoder(r.Body).Decode(&patch)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "event-1"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	s := newTestGoogle(t, false, http.NotFound)
+	s.calendarEndpoint = server.URL + "/calendar/v3/"
+
+	start := time.Date(2026, 10, 17, 7, 0, 0, 0, time.UTC)
+	if err := s.MoveCalendarEvent(&oauth2.Token{AccessToken: "access"}, "event-1", start, start.Add(ReminderEventLength), "none"); err != nil {
+		t.Fatalf("MoveCalendarEvent: %v", err)
+	}
+	if method != http.MethodPatch || path != "/calendar/v3/calendars/primary/events/event-1" || sendUpdates != "none" {
+		t.Errorf("request = %s %s sendUpdates=%q, want a PATCH of event-1 sending none", method, path, sendUpdates)
+	}
+	if patch.Start.DateTime != "2026-10-17T07:00:00Z" || patch.End.DateTime != "2026-10-17T07:15:00Z" {
+		t.Errorf("moved to %s-%s, want 07:00-07:15", patch.Start.DateTime, patch.End.DateTime)
+	}
+}
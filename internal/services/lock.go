@@ -0,0 +1,159 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ErrLockNotHeld is returned when renewing a lock this instance doesn't own
+var ErrLockNotHeld = errors.New("lock is not held by this instance")
+
+// Lock requests are sent once. A contended lock should fail fast rather than
+// wait through jittered retries; the caller simply tries again next run.
+var lockRetry = retryPolicy{maxAttempts: 1}
+
+// AcquireLock takes the named lock for ttl so only one instance runs a
+// background job at a time. It returns false without error when another
+// instance holds an unexpired lock. Acquiring a lock this instance already
+// holds extends it.
+func (s *FirebaseService) AcquireLock(name string, ttl time.Duration) (bool, error) {
+	return s.lockTransaction(name, func(owner string, expiresAt time.Time, exists bool) (map[string]interface{}, bool) {
+		if exists && owner != s.owner && s.clock.Now().Before(expiresAt) {
+			return nil, false
+		}
+		return s.lockWrite(name, ttl), true
+	})
+}
+
+// RenewLock extends a lock held by this instance; long-running workers call it
+// before the ttl runs out
+func (s *FirebaseService) RenewLock(name string, ttl time.Duration) error {
+	held, err := s.lockTransaction(name, func(owner string, expiresAt time.Time, exists bool) (map[string]interface{}, bool) {
+		if !exists || owner != s.owner {
+			return nil, false
+		}
+		return s.lockWrite(name, ttl), true
+	})
+	if err != nil {
+		return err
+	}
+	if !held {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// ReleaseLock removes the named lock if this instance holds it. Releasing a
+// lock that has since been taken over is a no-op.
+func (s *FirebaseService) ReleaseLock(name string) error {
+	_, err := s.lockTransaction(name, func(owner string, expiresAt time.Time, exists bool) (map[string]interface{}, bool) {
+		if !exists || owner != s.owner {
+			return nil, false
+		}
+		return map[string]interface{}{"delete": s.documentName("locks", name)}, true
+	})
+	return err
+}
+
+func (s *FirebaseService) lockWrite(name string, ttl time.Duration) map[string]interface{} {
+	return map[string]interface{}{
+		"update": map[string]interface{}{
+			"name": s.documentName("locks", name),
+			"fields": map[string]interface{}{
+				"owner":     map[string]interface{}{"stringValue": s.owner},
+				"expiresAt": map[string]interface{}{"timestampValue": s.clock.Now().Add(ttl).Format(time.RFC3339Nano)},
+			},
+		},
+	}
+}
+
+// lockTransaction reads the lock document inside a transaction and commits the
+// write decide returns. It reports false when decide declines or another
+// instance changed the lock first, which Firestore signals by aborting.
+func (s *FirebaseService) lockTransaction(name string, decide func(owner string, expiresAt time.Time, exists bool) (map[string]interface{}, bool)) (bool, error) {
+	transaction, err := s.beginTransaction()
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := s.makeRequestWithRetry(lockRetry, "GET", "/locks/"+url.PathEscape(name)+"?transaction="+url.QueryEscape(transaction), nil)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	var owner string
+	var expiresAt time.Time
+	exists := resp.StatusCode != http.StatusNotFound
+	if exists {
+		if resp.StatusCode >= 400 {
+			body, _ := io.ReadAll(resp.Body)
+			return false, fmt.Errorf("failed to read lock %s: %s", name, body)
+		}
+		var doc map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+			return false, err
+		}
+		fields, _ := doc["fields"].(map[string]interface{})
+		owner, _ = s.getStringValue(fields, "owner")
+		expiresAt, _ = s.getTimestampValue(fields, "expiresAt")
+	}
+
+	write, ok := decide(owner, expiresAt, exists)
+	if !ok {
+		s.rollback(transaction)
+		return false, nil
+	}
+
+	commitResp, err := s.makeRequestWithRetry(lockRetry, "POST", ":commit", map[string]interface{}{
+		"writes":      []interface{}{write},
+		"transaction": transaction,
+	})
+	if err != nil {
+		return false, err
+	}
+	defer commitResp.Body.Close()
+
+	if commitResp.StatusCode == http.StatusConflict {
+		return false, nil
+	}
+	if commitResp.StatusCode >= 400 {
+		body, _ := io.ReadAll(commitResp.Body)
+		return false, fmt.Errorf("failed to commit lock %s: %s", name, body)
+	}
+	return true, nil
+}
+
+func (s *FirebaseService) beginTransaction() (string, error) {
+	resp, err := s.makeRequestWithRetry(lockRetry, "POST", ":beginTransaction", map[string]interface{}{})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to begin transaction: %s", body)
+	}
+
+	var result struct {
+		Transaction string `json:"transaction"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	return result.Transaction, nil
+}
+
+// Best effort: an abandoned transaction also expires on its own
+func (s *FirebaseService) rollback(transaction string) {
+	resp, err := s.makeRequestWithRetry(lockRetry, "POST", ":rollback", map[string]interface{}{"transaction": transaction})
+	if err == nil {
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"focusflow-be/internal/clock"
+)
+
+// fakeLocks stands in for the Firestore transaction endpoints the lock uses,
+// keeping lock documents in memory. Commits are applied as sent unless abort
+// is set, when they fail the way a contended transaction does.
+type fakeLocks struct {
+	mu           sync.Mutex
+	docs         map[string]map[string]interface{} // fields by lock name
+	transactions int
+	rollbacks    int
+	abort        bool
+}
+
+func (f *fakeLocks) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, path, _ := strings.Cut(r.URL.Path, "/documents")
+	switch {
+	case path == ":beginTransaction":
+		f.transactions++
+		json.NewEncoder(w).Encode(map[string]string{"transaction": fmt.Sprintf("tx-%d", f.transactions)})
+	case path == ":rollback":
+		f.rollbacks++
+		w.Write([]byte(`{}`))
+	case path == ":commit":
+		if f.abort {
+			http.Error(w, `{"error": {"status": "ABORTED"}}`, http.StatusConflict)
+			return
+		}
+		var body struct {
+			Writes []struct {
+				Update *struct {
+					Name   string                 `json:"name"`
+					Fields map[string]interface{} `json:"fields"`
+				} `json:"update"`
+				Delete string `json:"delete"`
+			} `json:"writes"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		for _, write := range body.Writes {
+			if write.Update != nil {
+				f.docs[lockName(write.Update.Name)] = write.Update.Fields
+			} else {
+				delete(f.docs, lockName(write.Delete))
+			}
+		}
+		w.Write([]byte(`{}`))
+	case strings.HasPrefix(path, "/locks/") && r.Method == http.MethodGet:
+		fields, ok := f.docs[strings.TrimPrefix(path, "/locks/")]
+		if !ok {
+			http.Error(w, `{"error": {"status": "NOT_FOUND"}}`, http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"fields": fields})
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}
+}
+
+func lockName(document string) string {
+	_, name, _ := strings.Cut(document, "/documents/locks/")
+	return name
+}
+
+// newLockInstances returns two instances sharing one lock store and clock
+func newLockInstances(t *testing.T) (*FirebaseService, *FirebaseService, *fakeLocks) {
+	t.Helper()
+	fake := &fakeLocks{docs: make(map[string]map[string]interface{})}
+	a := newTestFirebase(t, fake.serve)
+	b := newTestFirebase(t, fake.serve)
+	a.owner, b.owner = "instance-a", "instance-b"
+	b.clock = a.clock
+	return a, b, fake
+}
+
+func acquire(t *testing.T, s *FirebaseService, ttl time.Duration) bool {
+	t.Helper()
+	held, err := s.AcquireLock("reminders", ttl)
+	if err != nil {
+		t.Fatalf("AcquireLock(%s): %v", s.owner, err)
+	}
+	return held
+}
+
+func TestAcquireLock(t *testing.T) {
+	a, b, fake := newLockInstances(t)
+	clk := a.clock.(*clock.Fake)
+
+	if !acquire(t, a, time.Minute) {
+		t.Fatal("first acquirer did not get the lock")
+	}
+	if acquire(t, b, time.Minute) {
+		t.Fatal("second acquirer got a held lock")
+	}
+	if fake.rollbacks != 1 {
+		t.Errorf("rollbacks = %d, want the declined transaction rolled back", fake.rollbacks)
+	}
+
+	clk.Advance(59 * time.Second)
+	if acquire(t, b, time.Minute) {
+		t.Fatal("second acquirer got the lock before it expired")
+	}
+	if !acquire(t, a, time.Minute) {
+		t.Fatal("holder could not extend its own lock")
+	}
+
+	clk.Advance(61 * time.Second)
+	if !acquire(t, b, time.Minute) {
+		t.Fatal("second acquirer did not get the expired lock")
+	}
+	if err := a.RenewLock("reminders", time.Minute); err != ErrLockNotHeld {
+		t.Errorf("RenewLock by the previous holder = %v, want %v", err, ErrLockNotHeld)
+	}
+	if err := a.ReleaseLock("reminders"); err != nil {
+		t.Fatalf("ReleaseLock: %v", err)
+	}
+	if acquire(t, a, time.Minute) {
+		t.Fatal("release by the previous holder freed the new holder's lock")
+	}
+
+	if err := b.RenewLock("reminders", time.Minute); err != nil {
+		t.Fatalf("RenewLock: %v", err)
+	}
+	if err := b.ReleaseLock("reminders"); err != nil {
+		t.Fatalf("ReleaseLock: %v", err)
+	}
+	if !acquire(t, a, time.Minute) {
+		t.Fatal("lock was not free after release")
+	}
+}
+
+func TestAcquireLockContended(t *testing.T) {
+	a, _, fake := newLockInstances(t)
+	fake.abort = true
+
+	if acquire(t, a, time.Minute) {
+		t.Error("got the lock although the commit was aborted")
+	}
+	if len(fake.docs) != 0 {
+		t.Errorf("lock documents = %v, want none", fake.docs)
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// stateTTL bounds how long a user may sit on Google's consent screen
+const stateTTL = 10 * time.Minute
+
+// PKCECookie holds a flow's PKCE verifier between /auth/google and the callback
+const PKCECookie = "focusflow_pkce"
+
+var (
+	ErrInvalidState    = errors.New("invalid or expired OAuth state")
+	ErrMissingVerifier = errors.New("missing or mismatched PKCE verifier; start the login again from this browser")
+)
+
+// OAuthState is carried through Google's consent screen in the state
+// parameter. It is HMAC-signed so the callback can trust its contents.
+type OAuthState struct {
+	RedirectURI  string `json:"r"`
+	ResponseMode string `json:"m,omitempty"` // "json" returns the token as JSON instead of HTML
+	FrontendURL  string `json:"f,omitempty"` // redirect here with #token=<jwt> instead of rendering HTML
+	Nonce        string `json:"n"`
+	ExpiresAt    int64  `json:"e"`
+
+	// The PKCE verifier never travels in the state; it is kept in the
+	// browser's PKCECookie and restored at the callback
+	verifier string
+}
+
+func newOAuthState(redirectURI string, now time.Time) (*OAuthState, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return &OAuthState{
+		RedirectURI: redirectURI,
+		Nonce:       hex.EncodeToString(nonce),
+		ExpiresAt:   now.Add(stateTTL).Unix(),
+		verifier:    oauth2.GenerateVerifier(),
+	}, nil
+}
+
+func (st *OAuthState) encode(key []byte) (string, error) {
+	payload, err := json.Marshal(st)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + signState(encoded, key), nil
+}
+
+func decodeOAuthState(state string, key []byte, now time.Time) (*OAuthState, error) {
+	encoded, signature, ok := strings.Cut(state, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(signState(encoded, key))) {
+		return nil, ErrInvalidState
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidState
+	}
+
+	var st OAuthState
+	if err := json.Unmarshal(payload, &st); err != nil {
+		return nil, ErrInvalidState
+	}
+	if now.Unix() > st.ExpiresAt {
+		return nil, ErrInvalidState
+	}
+
+	return &st, nil
+}
+
+func signState(encoded string, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
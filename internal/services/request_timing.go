@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+type firestoreTimerKey struct{}
+
+// WithFirestoreTimer returns a context under which every Firestore call made
+// through a store bound with WithContext reports its duration to record. The
+// Server-Timing middleware uses it to show a firestore phase.
+func WithFirestoreTimer(ctx context.Context, record func(time.Duration)) context.Context {
+	return context.WithValue(ctx, firestoreTimerKey{}, record)
+}
+
+func firestoreTimer(ctx context.Context) func(time.Duration) {
+	if ctx == nil {
+		return nil
+	}
+	record, _ := ctx.Value(firestoreTimerKey{}).(func(time.Duration))
+	return record
+}
+
+// timedBody reports how long a call took, retries and reading the response
+// included, once its body is closed
+type timedBody struct {
+	io.ReadCloser
+	start  time.Time
+	record func(time.Duration)
+	once   sync.Once
+}
+
+func (b *timedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.record(time.Since(b.start)) })
+	return err
+}
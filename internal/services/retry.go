@@ -0,0 +1,81 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// retryPolicy retries Firestore REST calls that fail with a transient status.
+// Only UNAVAILABLE (503), ABORTED (409) and DEADLINE_EXCEEDED (504) are
+// retried; NOT_FOUND, PERMISSION_DENIED and friends fail immediately, as does
+// ALREADY_EXISTS, which shares 409 with ABORTED.
+type retryPolicy struct {
+	maxAttempts int
+	deadline    time.Duration
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+// idempotent reports whether sending a request twice is harmless. A POST to
+// a collection creates a document each time it lands (or fails the second
+// time when the ID is fixed), so it is sent once; the :runQuery-style calls
+// and :commit are safe to repeat.
+func idempotent(method, path string) bool {
+	return method != http.MethodPost || strings.HasPrefix(path, ":")
+}
+
+func isRetryable(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusConflict:
+		// Keep the body readable for the caller once it has been inspected
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return bytes.Contains(body, []byte("ABORTED"))
+	}
+	return false
+}
+
+// backoff returns a full-jitter exponential delay for the given attempt (starting at 1)
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	delay := p.baseDelay << (attempt - 1)
+	if delay <= 0 || delay > p.maxDelay {
+		delay = p.maxDelay
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+func (p retryPolicy) do(ctx context.Context, send func() (*http.Response, error)) (*http.Response, error) {
+	stopAt := time.Now().Add(p.deadline)
+
+	for attempt := 1; ; attempt++ {
+		resp, err := send()
+		if err != nil || attempt >= p.maxAttempts || !isRetryable(resp) {
+			return resp, err
+		}
+
+		delay := p.backoff(attempt)
+		if time.Now().Add(delay).After(stopAt) {
+			return resp, nil
+		}
+
+		// Drain so the connection can be reused for the next attempt
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
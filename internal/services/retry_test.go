@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newRetryingFirebase is newTestFirebase with a policy that retries quickly
+func newRetryingFirebase(t *testing.T, handler http.HandlerFunc) *FirebaseService {
+	t.Helper()
+	s := newTestFirebase(t, handler)
+	s.retry = retryPolicy{
+		maxAttempts: 4,
+		deadline:    time.Second,
+		baseDelay:   time.Millisecond,
+		maxDelay:    5 * time.Millisecond,
+	}
+	return s
+}
+
+// failing answers with status and body for the first failures requests, then 200
+func failing(calls *int32, failures int32, status int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(calls, 1) <= failures {
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+			return
+		}
+		w.Write([]byte(`{"name": "projects/test/databases/(default)/documents/tasks/t1", "fields": {}}`))
+	}
+}
+
+func TestRetry(t *testing.T) {
+	const aborted = `{"error": {"code": 409, "status": "ABORTED", "message": "Transaction lock timeout"}}`
+	const exists = `{"error": {"code": 409, "status": "ALREADY_EXISTS", "message": "Document already exists"}}`
+
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		status    int
+		body      string
+		wantCalls int32
+		wantErr   bool
+	}{
+		{"read recovers after two failures", "GET", "/tasks/t1", http.StatusServiceUnavailable, `{}`, 3, false},
+		{"query recovers after two timeouts", "POST", ":runQuery", http.StatusGatewayTimeout, `{}`, 3, false},
+		{"aborted commit is retried", "POST", ":commit", http.StatusConflict, aborted, 3, false},
+		{"already exists is not retried", "POST", ":commit", http.StatusConflict, exists, 1, true},
+		{"create is sent once", "POST", "/tasks", http.StatusServiceUnavailable, `{}`, 1, true},
+		{"create with an ID is sent once", "POST", "/users?documentId=u1", http.StatusServiceUnavailable, `{}`, 1, true},
+		{"not found is not retried", "GET", "/tasks/t1", http.StatusNotFound, `{}`, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			s := newRetryingFirebase(t, failing(&calls, 2, tt.status, tt.body))
+
+			resp, err := s.makeRequest(tt.method, tt.path, map[string]interface{}{})
+			if err != nil {
+				t.Fatalf("makeRequest: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+			if failed := resp.StatusCode >= 400; failed != tt.wantErr {
+				t.Errorf("status = %d, want failure %v", resp.StatusCode, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetryKeepsConflictBody(t *testing.T) {
+	var calls int32
+	s := newRetryingFirebase(t, failing(&calls, 5, http.StatusConflict, `{"error": {"status": "ALREADY_EXISTS"}}`))
+
+	_, err := s.createDocument("users?documentId=u1", map[string]interface{}{})
+	if err == nil || !strings.Contains(err.Error(), "ALREADY_EXISTS") {
+		t.Errorf("err = %v, want the ALREADY_EXISTS body", err)
+	}
+}
+
+func TestRetryStopsWhenContextEnds(t *testing.T) {
+	var calls int32
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	s := newRetryingFirebase(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	s.retry.baseDelay = time.Hour
+	s.retry.maxDelay = time.Hour
+	s.retry.deadline = 24 * time.Hour
+
+	start := time.Now()
+	_, err := s.WithContext(ctx).(*FirebaseService).makeRequest("GET", "/tasks/t1", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("returned after %v, want the backoff cut short", elapsed)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"focusflow-be/internal/models"
+)
+
+const snapshotCollection = "overviewSnapshots"
+
+// CreateOverviewSnapshot stores snapshot under a new random token, which it
+// sets on the snapshot and returns. The token is the only way to read the
+// snapshot back, so it is long enough that it can't be guessed.
+func (s *FirebaseService) CreateOverviewSnapshot(snapshot *models.OverviewSnapshot) (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(random)
+
+	// The overview is kept as JSON; a snapshot is never queried or updated,
+	// only served back as it was taken
+	overview, err := json.Marshal(snapshot.Overview)
+	if err != nil {
+		return "", err
+	}
+	doc := map[string]interface{}{
+		"fields": map[string]interface{}{
+			"userId":    map[string]interface{}{"stringValue": snapshot.UserID},
+			"overview":  map[string]interface{}{"stringValue": string(overview)},
+			"createdAt": map[string]interface{}{"timestampValue": snapshot.CreatedAt.Format(time.RFC3339)},
+			"expiresAt": map[string]interface{}{"timestampValue": snapshot.ExpiresAt.Format(time.RFC3339)},
+		},
+	}
+
+	resp, err := s.makeRequest("POST", "/"+snapshotCollection+"?documentId="+url.QueryEscape(token), doc)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to create overview snapshot: %s", body)
+	}
+
+	snapshot.Token = token
+	return token, nil
+}
+
+// GetOverviewSnapshot returns the snapshot stored under token, expired or
+// not; callers decide what to do with an expired one. An unknown token is
+// ErrNotFound.
+func (s *FirebaseService) GetOverviewSnapshot(token string) (*models.OverviewSnapshot, error) {
+	doc, err := s.getDocument(snapshotCollection, url.PathEscape(token))
+	if err != nil {
+		return nil, err
+	}
+
+	fields, _ := doc["fields"].(map[string]interface{})
+	snapshot := &models.OverviewSnapshot{Token: token}
+	snapshot.UserID, _ = s.getStringValue(fields, "userId")
+	snapshot.CreatedAt, _ = s.getTimestampValue(fields, "createdAt")
+	snapshot.ExpiresAt, _ = s.getTimestampValue(fields, "expiresAt")
+	if overview, ok := s.getStringValue(fields, "overview"); ok {
+		if err := json.Unmarshal([]byte(overview), &snapshot.Overview); err != nil {
+			return nil, fmt.Errorf("failed to decode overview snapshot: %w", err)
+		}
+	}
+	return snapshot, nil
+}
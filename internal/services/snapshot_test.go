@@ -0,0 +1,61 @@
+package services
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"focusflow-be/internal/models"
+)
+
+func TestOverviewSnapshot(t *testing.T) {
+	s, fake := newFakeDocuments(t)
+	created := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	snapshot := &models.OverviewSnapshot{
+		UserID: "user-1",
+		Overview: models.Overview{
+			Tasks:    models.TaskOverview{Total: 5, Completed: 2, Overdue: 1},
+			Meetings: models.MeetingOverview{Total: 3, Upcoming: 2, AttendanceRate: 0.5},
+		},
+		CreatedAt: created,
+		ExpiresAt: created.Add(72 * time.Hour),
+	}
+	token, err := s.CreateOverviewSnapshot(snapshot)
+	if err != nil {
+		t.Fatalf("CreateOverviewSnapshot: %v", err)
+	}
+	if len(token) != 64 || snapshot.Token != token {
+		t.Errorf("token = %q (set %q), want 64 hex characters set on the snapshot", token, snapshot.Token)
+	}
+
+	fields, ok := fake.docs[snapshotCollection+"/"+token]
+	if !ok {
+		t.Fatalf("no document stored under the token; have %v", fake.docs)
+	}
+	var keys []string
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	if want := []string{"createdAt", "expiresAt", "overview", "userId"}; !slices.Equal(keys, want) {
+		t.Errorf("stored fields = %v, want %v", keys, want)
+	}
+
+	got, err := s.GetOverviewSnapshot(token)
+	if err != nil {
+		t.Fatalf("GetOverviewSnapshot: %v", err)
+	}
+	if !reflect.DeepEqual(got, snapshot) {
+		t.Errorf("snapshot = %+v, want %+v", got, snapshot)
+	}
+
+	again, err := s.CreateOverviewSnapshot(&models.OverviewSnapshot{UserID: "user-1", CreatedAt: created, ExpiresAt: created})
+	if err != nil || again == token {
+		t.Errorf("second token = %q (%v), want a new one", again, err)
+	}
+	if _, err := s.GetOverviewSnapshot("unknown"); err != ErrNotFound {
+		t.Errorf("GetOverviewSnapshot(unknown) err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"focusflow-be/internal/models"
+)
+
+// Store is the persistence the HTTP handlers rely on. FirebaseService is the
+// production implementation; handlers depend on this interface so they can be
+// exercised against a fake without Firestore.
+type Store interface {
+	// WithContext binds the store to a request's context
+	WithContext(ctx context.Context) Store
+
+	// Users
+	CreateUser(user *models.UserSession) error
+	GetUser(userID string) (*models.UserSession, error)
+	UpdateUser(userID string, updates map[string]interface{}) error
+
+	// Tasks
+	CreateTask(task *models.Task) (string, error)
+	GetTask(taskID string) (*models.Task, error)
+	GetTasks(userID string) ([]*models.Task, error)
+	GetTasksDueBetween(userID string, from, to time.Time) ([]*models.Task, error)
+	GetTasksCompletedBetween(userID string, from, to time.Time, limit int, after *PageCursor) ([]*models.Task, error)
+	UpdateTask(taskID string, updates map[string]interface{}) error
+	BatchUpdateTasks(updates map[string]map[string]interface{}) error
+	DeleteTask(task *models.Task) error
+	BatchDeleteTasks(tasks []*models.Task) error
+
+	// Meetings
+	CreateMeeting(meeting *models.Meeting) (string, error)
+	GetMeeting(meetingID string) (*models.Meeting, error)
+	GetMeetings(userID string) ([]*models.Meeting, error)
+	GetMeetingsWithAttendee(userID, attendee string) ([]*models.Meeting, error)
+	UpdateMeeting(meetingID string, updates map[string]interface{}) error
+	BatchUpdateMeetings(updates map[string]map[string]interface{}) error
+
+	// Reminders
+	CreateReminder(reminder *models.Reminder) (string, error)
+	GetReminder(reminderID string) (*models.Reminder, error)
+	GetReminders(userID string) ([]*models.Reminder, error)
+	GetRemindersByIDs(ids []string) ([]*models.Reminder, error)
+	UpdateReminder(reminderID string, updates map[string]interface{}) error
+	BatchUpdateReminders(updates map[string]map[string]interface{}) error
+	BatchDeleteReminders(reminders []*models.Reminder) error
+	GetCarryForwardUserIDs() ([]string, error)
+
+	// Cross-collection
+	BatchCreate(collection string, items []interface{}) ([]string, error)
+	GetChangesSince(userID string, since time.Time) (*models.SyncChanges, error)
+	GetBadgeCounts(userID string, dayStart, dayEnd, tasksDueBefore, remindersDueBefore time.Time) (*models.BadgeCounts, error)
+	GetUsageStats() (*models.UsageStats, error)
+
+	// Shared overview snapshots
+	CreateOverviewSnapshot(snapshot *models.OverviewSnapshot) (string, error)
+	GetOverviewSnapshot(token string) (*models.OverviewSnapshot, error)
+
+	// Locks
+	AcquireLock(name string, ttl time.Duration) (bool, error)
+	RenewLock(name string, ttl time.Duration) error
+	ReleaseLock(name string) error
+}
+
+var _ Store = (*FirebaseService)(nil)
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookSignatureHeader carries the signature on outbound webhook deliveries.
+// Its value has the form "t=<unix seconds>,n=<nonce>,v1=<hex HMAC-SHA256>",
+// where the HMAC covers "<t>.<n>.<payload>".
+const WebhookSignatureHeader = "X-FocusFlow-Signature"
+
+// WebhookTolerance is how old a delivery may be before it is rejected as a replay
+const WebhookTolerance = 5 * time.Minute
+
+var (
+	ErrWebhookSignatureMalformed = errors.New("malformed webhook signature header")
+	ErrWebhookSignatureInvalid   = errors.New("webhook signature does not match")
+	ErrWebhookSignatureExpired   = errors.New("webhook delivery is outside the tolerance window")
+)
+
+// SignWebhookPayload builds the signature header value for a delivery using a
+// fresh random nonce and the current time
+func SignWebhookPayload(payload []byte, secret string) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return signWebhook(payload, secret, time.Now().Unix(), hex.EncodeToString(nonce)), nil
+}
+
+func signWebhook(payload []byte, secret string, timestamp int64, nonce string) string {
+	return fmt.Sprintf("t=%d,n=%s,v1=%s", timestamp, nonce, webhookMAC(payload, secret, timestamp, nonce))
+}
+
+func webhookMAC(payload []byte, secret string, timestamp int64, nonce string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.%s.", timestamp, nonce)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks a delivery's signature header against the
+// payload and rejects deliveries older than WebhookTolerance. Receivers should
+// also remember recently seen nonces to drop duplicates within the window.
+func VerifyWebhookSignature(payload []byte, header string, secret string) error {
+	var timestamp int64
+	var nonce, signature string
+
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return ErrWebhookSignatureMalformed
+		}
+		switch key {
+		case "t":
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return ErrWebhookSignatureMalformed
+			}
+			timestamp = parsed
+		case "n":
+			nonce = value
+		case "v1":
+			signature = value
+		}
+	}
+
+	if timestamp == 0 || nonce == "" || signature == "" {
+		return ErrWebhookSignatureMalformed
+	}
+
+	expected := webhookMAC(payload, secret, timestamp, nonce)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return ErrWebhookSignatureInvalid
+	}
+
+	age := time.Since(time.Unix(timestamp, 0))
+	if age > WebhookTolerance || age < -WebhookTolerance {
+		return ErrWebhookSignatureExpired
+	}
+
+	return nil
+}
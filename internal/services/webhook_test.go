@@ -0,0 +1,48 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	const secret = "whsec-test"
+	payload := []byte(`{"event": "task.completed", "taskId": "t1"}`)
+	now := time.Now().Unix()
+	tolerance := int64(WebhookTolerance / time.Second)
+
+	signed, err := SignWebhookPayload(payload, secret)
+	if err != nil {
+		t.Fatalf("SignWebhookPayload: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		payload []byte
+		header  string
+		secret  string
+		want    error
+	}{
+		{"valid", payload, signed, secret, nil},
+		{"valid with spaces", payload, strings.ReplaceAll(signed, ",", ", "), secret, nil},
+		{"just inside the window", payload, signWebhook(payload, secret, now-tolerance+5, "n1"), secret, nil},
+		{"tampered payload", []byte(`{"event": "task.completed", "taskId": "t2"}`), signed, secret, ErrWebhookSignatureInvalid},
+		{"wrong secret", payload, signed, "other-secret", ErrWebhookSignatureInvalid},
+		{"tampered timestamp", payload, strings.Replace(signWebhook(payload, secret, now, "n1"), "t=", "t=1", 1), secret, ErrWebhookSignatureInvalid},
+		{"tampered nonce", payload, strings.Replace(signWebhook(payload, secret, now, "n1"), "n=n1", "n=n2", 1), secret, ErrWebhookSignatureInvalid},
+		{"stale", payload, signWebhook(payload, secret, now-tolerance-5, "n1"), secret, ErrWebhookSignatureExpired},
+		{"from the future", payload, signWebhook(payload, secret, now+tolerance+5, "n1"), secret, ErrWebhookSignatureExpired},
+		{"missing nonce", payload, "t=1,v1=abc", secret, ErrWebhookSignatureMalformed},
+		{"missing signature", payload, "t=1,n=n1", secret, ErrWebhookSignatureMalformed},
+		{"bad timestamp", payload, "t=soon,n=n1,v1=abc", secret, ErrWebhookSignatureMalformed},
+		{"empty", payload, "", secret, ErrWebhookSignatureMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifyWebhookSignature(tt.payload, tt.header, tt.secret); err != tt.want {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
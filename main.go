@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 
+	"focusflow-be/internal/buildinfo"
 	"focusflow-be/internal/config"
 	"focusflow-be/internal/handlers"
 	"focusflow-be/internal/middleware"
@@ -23,9 +29,17 @@ func main() {
 	// Initialize configuration from environment variables
 	cfg := config.New()
 
-	// Validate required configuration
-	if cfg.FirebaseProjectID == "" || cfg.GoogleClientID == "" || cfg.JWTSecret == "" {
-		log.Fatal("Missing required environment variables. Please check FIREBASE_PROJECT_ID, GOOGLE_CLIENT_ID, and JWT_SECRET")
+	// Validate configuration, listing every problem before giving up
+	if err := cfg.Validate(); err != nil {
+		var invalid *config.ValidationError
+		if !errors.As(err, &invalid) {
+			log.Fatalf("Invalid configuration: %v", err)
+		}
+		log.Printf("❌ Invalid configuration, %d problem(s) found:", len(invalid.Problems))
+		for _, problem := range invalid.Problems {
+			log.Printf("   - %s", problem)
+		}
+		log.Fatal("Fix the settings above in the environment or .env (see .env.example) and restart")
 	}
 
 	// Initialize Firebase service
@@ -37,27 +51,82 @@ func main() {
 
 	// Initialize other services
 	googleService := services.NewGoogleService(cfg)
-	authService := services.NewAuthService(cfg)
+	authService, err := services.NewAuthService(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize auth service: %v", err)
+	}
+
+	if err := handlers.RegisterValidators(); err != nil {
+		log.Fatalf("Failed to register request validators: %v", err)
+	}
 
 	// Initialize all handlers with their dependencies
-	authHandler := handlers.NewAuthHandler(authService, googleService, firebaseService)
-	taskHandler := handlers.NewTaskHandler(firebaseService, authService)
-	meetingHandler := handlers.NewMeetingHandler(firebaseService, authService)
-	reminderHandler := handlers.NewReminderHandler(firebaseService, authService)
-	dashboardHandler := handlers.NewDashboardHandler(firebaseService, authService)
+	authHandler := handlers.NewAuthHandler(authService, googleService, firebaseService, cfg)
+	taskHandler := handlers.NewTaskHandler(firebaseService, authService, googleService, cfg)
+	meetingHandler := handlers.NewMeetingHandler(firebaseService, authService, googleService, cfg)
+	reminderHandler := handlers.NewReminderHandler(firebaseService, authService, googleService, cfg)
+	dashboardHandler := handlers.NewDashboardHandler(firebaseService, authService, cfg)
+	syncHandler := handlers.NewSyncHandler(firebaseService, authService, cfg)
+	readOnly := middleware.NewReadOnlyMode(cfg.ReadOnlyMode, cfg.ReadOnlyRetryAfter)
+	adminHandler := handlers.NewAdminHandler(firebaseService, readOnly, cfg)
+
+	// Token verification, optionally confirming the user still exists
+	var userChecker *middleware.UserChecker
+	if cfg.AuthCheckUserExists {
+		userChecker = middleware.NewUserChecker(firebaseService, cfg.Clock, cfg.AuthUserCacheTTL, cfg.AuthUserCacheSize)
+		firebaseService.OnUserChange(userChecker.Forget)
+	}
+	// Every route needs a user token except these. Admin routes check the
+	// admin API key instead.
+	publicRoutes := []string{
+		"/",
+		"/features",
+		"/version",
+		"/auth/google",
+		"/auth/callback",
+		"/auth/debug",
+		"/auth/validate",
+		"/admin/*",
+		"/shared/*",
+	}
+	requireAuth := middleware.AuthMiddleware(authService, userChecker, append(publicRoutes, cfg.AuthPublicRoutes...)...)
+
+	// Setup Gin router with middleware. Panics are recovered first so every
+	// other middleware runs inside the recovery handler.
+	r, err := newRouter(cfg)
+	if err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+	r.Use(middleware.Recovery(), gin.Logger())
 
-	// Setup Gin router with middleware
-	r := gin.Default()
+	// Time each request's phases before anything else runs
+	if cfg.ServerTiming || cfg.ResponseTimeBudget > 0 {
+		r.Use(middleware.ServerTiming(cfg.ServerTiming, cfg.ResponseTimeBudget))
+	}
 
 	// Configure CORS middleware
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"*"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "X-Next-Cursor", "X-Partial-Content", middleware.RequestIDHeader},
 		AllowCredentials: true,
 	}))
 
+	// Freeze writes in read-only mode; the admin toggle stays writable
+	r.Use(middleware.ReadOnly(readOnly, "/admin/read-only"))
+
+	// Drop cached dashboard overviews once a write has gone through
+	r.Use(dashboardHandler.InvalidateOverviews())
+
+	// Compress larger responses for clients that accept gzip
+	if cfg.GzipEnabled {
+		r.Use(middleware.Gzip(cfg.GzipMinSize))
+	}
+
+	// Authenticate everything outside publicRoutes
+	r.Use(requireAuth)
+
 	// Root health check endpoint
 	r.GET("/", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -69,76 +138,173 @@ func main() {
 				"authentication": gin.H{
 					"google_auth": "GET /auth/google",
 					"callback":    "GET /auth/callback",
+					"validate":    "GET /auth/validate",
 					"me":          "GET /auth/me",
+					"preferences": "PATCH /auth/me",
+					"profile":     "GET /auth/me/profile",
+					"export":      "GET /auth/me/export",
+					"import":      "POST /auth/me/import",
 					"debug":       "GET /auth/debug",
 				},
+				"features": "GET /features",
+				"version":  "GET /version",
 				"tasks": gin.H{
-					"list":     "GET /tasks",
-					"create":   "POST /tasks",
-					"update":   "PUT /tasks/:id",
-					"delete":   "DELETE /tasks/:id",
-					"start":    "PATCH /tasks/:id/start",
-					"complete": "PATCH /tasks/:id/complete",
+					"list":              "GET /tasks",
+					"dueSoon":           "GET /tasks/due?within=3d",
+					"board":             "GET /tasks/board",
+					"backlog":           "GET /tasks/backlog",
+					"agenda":            "GET /tasks/agenda?from=&to=",
+					"completed":         "GET /tasks/completed?from=&to=",
+					"create":            "POST /tasks",
+					"quickAdd":          "POST /tasks/quick-add",
+					"update":            "PUT /tasks/:id",
+					"delete":            "DELETE /tasks/:id",
+					"deleteCompleted":   "DELETE /tasks/completed",
+					"start":             "PATCH /tasks/:id/start",
+					"block":             "PATCH /tasks/:id/block",
+					"unblock":           "PATCH /tasks/:id/unblock",
+					"complete":          "PATCH /tasks/:id/complete",
+					"toggle":            "PATCH /tasks/:id/toggle",
+					"rescheduleOverdue": "POST /tasks/reschedule-overdue",
+					"syncCalendar":      "POST /tasks/sync-calendar",
 				},
 				"meetings": gin.H{
 					"list":         "GET /meetings",
 					"create":       "POST /meetings",
+					"get":          "GET /meetings/:id",
+					"update":       "PUT /meetings/:id",
+					"patch":        "PATCH /meetings/:id",
+					"duplicate":    "POST /meetings/:id/duplicate",
+					"importIcs":    "POST /meetings/import/ics",
+					"syncCalendar": "POST /meetings/sync-calendar",
+					"reschedule":   "PATCH /meetings/:id/reschedule",
 					"updateStatus": "PATCH /meetings/:id/status",
+					"attendance":   "PATCH /meetings/:id/attendance",
+					"notes":        "PUT /meetings/:id/notes",
+					"actionToTask": "POST /meetings/:id/action-items/:itemId/to-task",
 				},
 				"reminders": gin.H{
-					"list":     "GET /reminders",
-					"create":   "POST /reminders",
-					"complete": "PATCH /reminders/:id/complete",
+					"list":         "GET /reminders",
+					"create":       "POST /reminders",
+					"update":       "PUT /reminders/:id",
+					"complete":     "PATCH /reminders/:id/complete",
+					"dismiss":      "PATCH /reminders/:id/dismiss",
+					"bulkComplete": "POST /reminders/complete",
+					"reschedule":   "POST /reminders/reschedule-overdue",
 				},
 				"dashboard": gin.H{
 					"calendar": "GET /dashboard/calendar",
 					"gantt":    "GET /dashboard/gantt",
 					"overview": "GET /dashboard/overview",
+					"share":    "POST /dashboard/overview/share",
+					"badges":   "GET /dashboard/badges",
+					"activity": "GET /dashboard/activity?days=30",
+					"day":      "GET /dashboard/day/:date",
+					"workload": "GET /dashboard/workload?from=&to=",
+					"overdue":  "GET /dashboard/overdue",
+					"accuracy": "GET /dashboard/estimate-accuracy",
+					"freeBusy": "GET /dashboard/availability?date=&slotMinutes=30",
+					"conflict": "GET /dashboard/conflicts?days=30",
+					"schedule": "GET /dashboard/schedule?week=",
 				},
+				"sync":   "GET /sync?since=",
+				"shared": "GET /shared/overview/:token",
 			},
 		})
 	})
 
-	// Authentication routes (public)
+	// Which optional features this deployment has switched on
+	r.GET("/features", func(c *gin.Context) {
+		c.JSON(http.StatusOK, cfg.Features)
+	})
+
+	// Which build is running, for telling deployments apart
+	r.GET("/version", func(c *gin.Context) {
+		c.JSON(http.StatusOK, buildinfo.Get())
+	})
+
+	// Authentication routes; the sign-in flow is listed in publicRoutes
 	authGroup := r.Group("/auth")
 	{
 		authGroup.GET("/google", authHandler.GoogleAuth)
 		authGroup.GET("/callback", authHandler.GoogleCallback)
 		authGroup.GET("/debug", authHandler.Debug)
-		
-		// Protected auth routes
-		authGroup.GET("/me", middleware.AuthMiddleware(authService), authHandler.GetMe)
+		authGroup.GET("/validate", authHandler.ValidateToken)
+
+		// Routes for the signed-in user
+		authGroup.GET("/me", authHandler.GetMe)
+		authGroup.PATCH("/me", authHandler.UpdatePreferences)
+		authGroup.GET("/me/profile", authHandler.GetProfile)
+		authGroup.GET("/me/export", middleware.RequireFeature(cfg.Features.DataTransfer), authHandler.ExportData)
+		authGroup.POST("/me/import", middleware.RequireFeature(cfg.Features.DataTransfer), authHandler.ImportData)
+	}
+
+	// Operator routes (require the admin API key, not a user JWT)
+	adminGroup := r.Group("/admin")
+	adminGroup.Use(middleware.AdminMiddleware(cfg.AdminAPIKey))
+	{
+		adminGroup.GET("/stats", adminHandler.GetStats)
+		adminGroup.PUT("/read-only", adminHandler.SetReadOnly)
+		adminGroup.POST("/reminders/carry-forward", middleware.RequireFeature(cfg.Features.ReminderCarryForward), adminHandler.CarryForwardReminders)
 	}
 
-	// Protected API routes (require authentication)
+	// Shared snapshots, readable by anyone with the link (listed in publicRoutes)
+	r.GET("/shared/overview/:token", dashboardHandler.GetSharedOverview)
+
+	// API routes (authenticated by requireAuth)
 	api := r.Group("/")
-	api.Use(middleware.AuthMiddleware(authService))
 	{
 		// Task management endpoints
 		taskGroup := api.Group("/tasks")
 		{
-			taskGroup.GET("/", taskHandler.GetTasks)
-			taskGroup.POST("/", taskHandler.CreateTask)
+			handleRoot(taskGroup, http.MethodGet, taskHandler.GetTasks)
+			taskGroup.GET("/due", taskHandler.GetTasksDueSoon)
+			taskGroup.GET("/board", taskHandler.GetTaskBoard)
+			taskGroup.GET("/backlog", taskHandler.GetTaskBacklog)
+			taskGroup.GET("/agenda", taskHandler.GetTaskAgenda)
+			taskGroup.GET("/completed", taskHandler.GetCompletedTasks)
+			handleRoot(taskGroup, http.MethodPost, taskHandler.CreateTask)
+			taskGroup.POST("/quick-add", taskHandler.QuickAddTask)
+			taskGroup.POST("/reschedule-overdue", taskHandler.RescheduleOverdue)
+			taskGroup.POST("/sync-calendar", middleware.RequireFeature(cfg.Features.CalendarSync), taskHandler.SyncCalendar)
 			taskGroup.PUT("/:id", taskHandler.UpdateTask)
+			taskGroup.DELETE("/completed", taskHandler.DeleteCompletedTasks)
 			taskGroup.DELETE("/:id", taskHandler.DeleteTask)
 			taskGroup.PATCH("/:id/start", taskHandler.StartTask)
+			taskGroup.PATCH("/:id/block", taskHandler.BlockTask)
+			taskGroup.PATCH("/:id/unblock", taskHandler.UnblockTask)
 			taskGroup.PATCH("/:id/complete", taskHandler.CompleteTask)
+			taskGroup.PATCH("/:id/toggle", taskHandler.ToggleTask)
 		}
 
 		// Meeting management endpoints
 		meetingGroup := api.Group("/meetings")
 		{
-			meetingGroup.GET("/", meetingHandler.GetMeetings)
-			meetingGroup.POST("/", meetingHandler.CreateMeeting)
+			handleRoot(meetingGroup, http.MethodGet, meetingHandler.GetMeetings)
+			handleRoot(meetingGroup, http.MethodPost, meetingHandler.CreateMeeting)
+			meetingGroup.GET("/:id", meetingHandler.GetMeeting)
+			meetingGroup.PUT("/:id", meetingHandler.UpdateMeeting)
+			meetingGroup.PATCH("/:id", meetingHandler.UpdateMeeting)
+			meetingGroup.POST("/:id/duplicate", meetingHandler.DuplicateMeeting)
+			meetingGroup.POST("/import/ics", meetingHandler.ImportICS)
+			meetingGroup.POST("/sync-calendar", middleware.RequireFeature(cfg.Features.CalendarSync), meetingHandler.SyncCalendar)
+			meetingGroup.PATCH("/:id/reschedule", meetingHandler.RescheduleMeeting)
 			meetingGroup.PATCH("/:id/status", meetingHandler.UpdateMeetingStatus)
+			meetingGroup.PATCH("/:id/attendance", meetingHandler.UpdateMeetingAttendance)
+			meetingGroup.PUT("/:id/notes", meetingHandler.UpdateMeetingNotes)
+			meetingGroup.POST("/:id/action-items/:itemId/to-task", meetingHandler.ActionItemToTask)
 		}
 
 		// Reminder management endpoints
 		reminderGroup := api.Group("/reminders")
 		{
-			reminderGroup.GET("/", reminderHandler.GetReminders)
-			reminderGroup.POST("/", reminderHandler.CreateReminder)
+			handleRoot(reminderGroup, http.MethodGet, reminderHandler.GetReminders)
+			handleRoot(reminderGroup, http.MethodPost, reminderHandler.CreateReminder)
+			reminderGroup.PUT("/:id", reminderHandler.UpdateReminder)
 			reminderGroup.PATCH("/:id/complete", reminderHandler.CompleteReminder)
+			reminderGroup.PATCH("/:id/dismiss", reminderHandler.DismissReminder)
+			reminderGroup.POST("/complete", reminderHandler.CompleteReminders)
+			reminderGroup.POST("/reschedule-overdue", reminderHandler.RescheduleOverdue)
 		}
 
 		// Dashboard analytics endpoints
@@ -147,7 +313,20 @@ func main() {
 			dashboardGroup.GET("/calendar", dashboardHandler.GetCalendarEvents)
 			dashboardGroup.GET("/gantt", dashboardHandler.GetGanttData)
 			dashboardGroup.GET("/overview", dashboardHandler.GetOverview)
+			dashboardGroup.POST("/overview/share", dashboardHandler.ShareOverview)
+			dashboardGroup.GET("/badges", dashboardHandler.GetBadges)
+			dashboardGroup.GET("/activity", dashboardHandler.GetActivity)
+			dashboardGroup.GET("/day/:date", dashboardHandler.GetDay)
+			dashboardGroup.GET("/workload", dashboardHandler.GetWorkload)
+			dashboardGroup.GET("/overdue", dashboardHandler.GetOverdue)
+			dashboardGroup.GET("/estimate-accuracy", dashboardHandler.GetEstimateAccuracy)
+			dashboardGroup.GET("/availability", dashboardHandler.GetAvailability)
+			dashboardGroup.GET("/conflicts", dashboardHandler.GetConflicts)
+			dashboardGroup.GET("/schedule", dashboardHandler.GetSchedule)
 		}
+
+		// Incremental sync for offline-capable clients
+		api.GET("/sync", syncHandler.GetChanges)
 	}
 
 	// Get port from environment or default to 8080
@@ -156,13 +335,61 @@ func main() {
 		port = "8080"
 	}
 
+	srv := newServer(cfg, ":"+port, r)
+
 	// Start the server
 	log.Printf("🚀 FocusFlow API server starting on port %s", port)
 	log.Printf("📍 Health check: http://localhost:%s/", port)
 	log.Printf("🔐 Authentication: http://localhost:%s/auth/google", port)
 	log.Printf("📚 API Documentation: Check README.md for endpoints")
-	
-	if err := r.Run(":" + port); err != nil {
-		log.Fatalf("❌ Failed to start server: %v", err)
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("❌ Failed to start server: %v", err)
+		}
+	}()
+
+	// Wait for an interrupt, then let in-flight requests finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	log.Printf("🛑 Shutting down server...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("❌ Server forced to shut down: %v", err)
+	}
+}
+
+// handleRoot registers a group's root route both with and without the trailing
+// slash. Relying on Gin's redirect instead would make some clients drop the
+// Authorization header when following it.
+func handleRoot(group *gin.RouterGroup, method string, handler gin.HandlerFunc) {
+	group.Handle(method, "", handler)
+	group.Handle(method, "/", handler)
+}
+
+// newRouter creates the bare router. c.ClientIP() reads X-Forwarded-For only
+// from the proxies in TRUSTED_PROXIES; with none, the connection's address is used.
+func newRouter(cfg *config.Config) (*gin.Engine, error) {
+	r := gin.New()
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// newServer builds the HTTP server with timeouts so slow clients can't hold connections open
+func newServer(cfg *config.Config, addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
 	}
-}
\ No newline at end of file
+}
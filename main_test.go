@@ -0,0 +1,105 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"focusflow-be/internal/config"
+)
+
+func TestNewServer(t *testing.T) {
+	// The limits newServer copies from the config
+	type limits struct {
+		read, readHeader, write, idle time.Duration
+		maxHeaderBytes                int
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want limits
+	}{
+		{
+			name: "defaults",
+			want: limits{15 * time.Second, 15 * time.Second, 30 * time.Second, 60 * time.Second, 1 << 20},
+		},
+		{
+			name: "from the environment",
+			env: map[string]string{
+				"READ_TIMEOUT":     "5s",
+				"WRITE_TIMEOUT":    "2m",
+				"IDLE_TIMEOUT":     "90s",
+				"MAX_HEADER_BYTES": "8192",
+			},
+			want: limits{5 * time.Second, 5 * time.Second, 2 * time.Minute, 90 * time.Second, 8192},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"READ_TIMEOUT", "WRITE_TIMEOUT", "IDLE_TIMEOUT", "MAX_HEADER_BYTES"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			handler := http.NotFoundHandler()
+			server := newServer(config.New(), ":8080", handler)
+
+			if server.Addr != ":8080" || server.Handler == nil {
+				t.Errorf("Addr = %q, Handler = %v, want :8080 and the router", server.Addr, server.Handler)
+			}
+			got := limits{server.ReadTimeout, server.ReadHeaderTimeout, server.WriteTimeout, server.IdleTimeout, server.MaxHeaderBytes}
+			if got != tt.want {
+				t.Errorf("server limits = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRouterClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		trusted    string
+		remoteAddr string
+		forwarded  string
+		want       string
+	}{
+		{"no proxies trusted by default", "", "10.0.0.5:4321", "203.0.113.7", "10.0.0.5"},
+		{"trusted proxy", "10.0.0.0/8", "10.0.0.5:4321", "203.0.113.7", "203.0.113.7"},
+		{"client-supplied hop is skipped", "10.0.0.0/8", "10.0.0.5:4321", "198.51.100.1, 203.0.113.7", "203.0.113.7"},
+		{"chain of trusted proxies", "10.0.0.0/8", "10.0.0.5:4321", "203.0.113.7, 10.1.2.3", "203.0.113.7"},
+		{"untrusted peer", "10.0.0.0/8", "192.0.2.9:4321", "203.0.113.7", "192.0.2.9"},
+		{"single trusted IP", "192.0.2.9", "192.0.2.9:4321", "203.0.113.7", "203.0.113.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TRUSTED_PROXIES", tt.trusted)
+			r, err := newRouter(config.New())
+			if err != nil {
+				t.Fatalf("newRouter: %v", err)
+			}
+			var got string
+			r.GET("/", func(c *gin.Context) { got = c.ClientIP() })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", tt.forwarded)
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRouterInvalidProxy(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8,not-an-ip")
+	if _, err := newRouter(config.New()); err == nil {
+		t.Error("newRouter accepted an invalid proxy")
+	}
+}